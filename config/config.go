@@ -2,44 +2,233 @@ package config
 
 import (
 	"errors"
+	"fmt"
 	"log"
+	"os"
+	"reflect"
+	"strings"
 	"time"
 
+	"github.com/go-playground/validator/v10"
 	"github.com/spf13/viper"
 )
 
+const (
+	// ENV_PREFIX prefixes the environment variable of every key, e.g. APP_POSTGRES_HOST for postgres.host
+	ENV_PREFIX = "APP"
+
+	// ENV_PROFILE selects the profile when no --profile flag is given
+	ENV_PROFILE = "APP_PROFILE"
+
+	// FILE_SUFFIX of an environment variable names a file holding the value, e.g. APP_POSTGRES_PASSWORD_FILE
+	FILE_SUFFIX = "_FILE"
+
+	PROFILE_LOCAL      = "local"
+	PROFILE_DOCKER     = "docker"
+	PROFILE_PRODUCTION = "production"
+)
+
+// Config of the app, fields tagged with reload:"true" are reloaded without restart, see Reloader
 type Config struct {
-	Server   ServerConfig
-	Logger   Logger
-	Postgres PostgresConfig
+	Server     ServerConfig
+	Logger     Logger
+	Postgres   PostgresConfig
+	Search     SearchConfig
+	Tracing    TracingConfig
+	Cache      CacheConfig
+	Outbox     OutboxConfig
+	Webhooks   WebhooksConfig
+	Stream     StreamConfig
+	Pagination PaginationConfig
+	Features   FeaturesConfig
+
+	// name is the path, without extension, the config was loaded from, and file the path of its file
+	name string
+	file string
 }
 
 type ServerConfig struct {
-	AppVersion     string
-	Mode           string
-	Port           string
+	AppVersion     string `validate:"required"`
+	Mode           string `validate:"required"`
+	Port           string `validate:"required"`
+	MetricsAddr    string `validate:"required"`
 	Debug          bool
-	ReadTimeout    time.Duration
-	WriteTimeout   time.Duration
-	CtxDefaultTime time.Duration
+	ReadTimeout    time.Duration `validate:"gt=0"`
+	WriteTimeout   time.Duration `validate:"gt=0"`
+	CtxDefaultTime time.Duration `validate:"gt=0"`
+	DrainTime      time.Duration `validate:"gte=0"`
+	ProbeTimeout   time.Duration `validate:"gt=0"`
+	AllowOrigins   []string      `validate:"min=1" reload:"true"`
+	CacheControl   CacheControlConfig
+}
+
+// CacheControlConfig is the Cache-Control header of every read route, none is sent when it is empty.
+// Their responses always carry an ETag, so no-cache lets clients revalidate them for a 304.
+type CacheControlConfig struct {
+	BlogsList string
+	BlogsItem string
+	NewsList  string
+	NewsItem  string
 }
 
 type Logger struct {
 	Development bool
-	Level       string
-	Encoding    string
+	Level       string `validate:"oneof=debug info warn error dpanic panic fatal" reload:"true"`
+	Encoding    string `validate:"oneof=json console"`
 }
 
 type PostgresConfig struct {
-	Host     string
-	Port     string
-	Username string
-	Password string
-	DBName   string
-	SSLMode  string
-	PgDriver string
+	Host        string `validate:"required"`
+	Port        string `validate:"required,numeric"`
+	Username    string `validate:"required"`
+	Password    string `validate:"required"`
+	DBName      string `validate:"required"`
+	SSLMode     string `validate:"oneof=disable allow prefer require verify-ca verify-full"`
+	PgDriver    string `validate:"required"`
+	AutoMigrate bool
+
+	// TLS files, the client certificate and key go together
+	SSLRootCert string
+	SSLCert     string `validate:"required_with=SSLKey"`
+	SSLKey      string `validate:"required_with=SSLCert"`
+
+	// pool of connections, lifetimes are in seconds
+	MaxOpenConns    int           `validate:"gt=0"`
+	MaxIdleConns    int           `validate:"gte=0,ltefield=MaxOpenConns"`
+	ConnMaxLifetime time.Duration `validate:"gte=0"`
+	ConnMaxIdleTime time.Duration `validate:"gte=0"`
+
+	// timeouts are in seconds, 0 waits forever
+	ConnectTimeout   time.Duration `validate:"gte=0"`
+	StatementTimeout time.Duration `validate:"gte=0"`
+	ApplicationName  string
+
+	// attempts after the first one while the database is unavailable at startup, the first
+	// waits ConnectBackoff seconds and every next one twice longer
+	ConnectRetries int           `validate:"gte=0"`
+	ConnectBackoff time.Duration `validate:"gte=0"`
+	// read replicas as host:port, with the credentials and options of the primary, checked every
+	// ReplicaCheckInterval seconds. Reads of a client go to the primary ReadYourWritesWindow seconds after its writes.
+	Replicas             []string      `validate:"dive,hostname_port"`
+	ReplicaCheckInterval time.Duration `validate:"gt=0"`
+	ReadYourWritesWindow time.Duration `validate:"gte=0"`
+}
+
+type SearchConfig struct {
+	SimilarityThreshold float64       `validate:"gte=0,lte=1"`
+	SuggestLimit        int           `validate:"gt=0"`
+	SuggestCacheSize    int           `validate:"gte=0"`
+	SuggestCacheTTL     time.Duration `validate:"gte=0"`
+}
+
+type TracingConfig struct {
+	Exporter    string `validate:"omitempty,oneof=otlp stdout none"`
+	Endpoint    string `validate:"required_if=Exporter otlp"`
+	Insecure    bool
+	ServiceName string  `validate:"required"`
+	SampleRatio float64 `validate:"gte=0,lte=1"`
+}
+
+// CacheConfig of the blogs and news reads, the memory driver caches per instance and redis for every instance.
+// Entries live TTL seconds, Size bounds the entries of memory and KeyPrefix namespaces the keys in Redis.
+// Misses are read from the primary, so a lagging replica is never cached: the replicas only take the reads
+// which are not cached, and the primary one read per entry until it expires or is written.
+type CacheConfig struct {
+	Driver        string        `validate:"oneof=none memory redis"`
+	TTL           time.Duration `validate:"gt=0"`
+	Size          int           `validate:"required_if=Driver memory,gte=0"`
+	RedisAddr     string        `validate:"required_if=Driver redis"`
+	RedisPassword string
+	RedisDB       int `validate:"gte=0"`
+	KeyPrefix     string
+}
+
+// OutboxConfig of the relay delivering the content events of the outbox to Sink: log, webhook or nats.
+// Every PollInterval seconds it claims BatchSize events at a time for ClaimTimeout seconds, a failed one is
+// retried RetryBackoff seconds later and twice longer after every attempt, up to MaxRetryBackoff. Delivered
+// events are deleted after Retention seconds, 0 keeps them.
+type OutboxConfig struct {
+	Relay           bool
+	Sink            string        `validate:"oneof=log webhook nats"`
+	PollInterval    time.Duration `validate:"gt=0"`
+	BatchSize       int           `validate:"gt=0"`
+	ClaimTimeout    time.Duration `validate:"gt=0"`
+	RetryBackoff    time.Duration `validate:"gt=0"`
+	MaxRetryBackoff time.Duration `validate:"gt=0"`
+	Retention       time.Duration `validate:"gte=0"`
+	WebhookURL      string        `validate:"required_if=Sink webhook"`
+	WebhookTimeout  time.Duration `validate:"gt=0"`
+	NatsURL         string        `validate:"required_if=Sink nats"`
+	Subject         string        `validate:"required_if=Sink nats"`
+}
+
+// WebhooksConfig of the dispatcher posting the deliveries of webhook subscriptions. Every PollInterval seconds
+// it claims up to BatchSize deliveries for ClaimTimeout seconds and posts them, Concurrency at a time, each within
+// Timeout seconds. A failed one is retried RetryBackoff seconds later and twice longer after every attempt, up to
+// MaxRetryBackoff, and is dead after MaxAttempts. Deliveries are added by the outbox relay, for the events it relays.
+// Subscriptions can not target loopback, private or link-local addresses unless AllowPrivateNetworks, for local development.
+type WebhooksConfig struct {
+	Dispatch             bool
+	AllowPrivateNetworks bool
+	PollInterval         time.Duration `validate:"gt=0"`
+	BatchSize            int           `validate:"gt=0"`
+	ClaimTimeout         time.Duration `validate:"gt=0"`
+	Concurrency          int           `validate:"gt=0"`
+	Timeout              time.Duration `validate:"gt=0"`
+	MaxAttempts          int           `validate:"gt=0"`
+	RetryBackoff         time.Duration `validate:"gt=0"`
+	MaxRetryBackoff      time.Duration `validate:"gt=0"`
+}
+
+// StreamConfig of the server-sent event streams of content changes. A heartbeat is sent every Heartbeat seconds
+// so proxies keep idle streams open, and clients reconnect Retry seconds after a stream ends. A client more than
+// BufferSize events behind is disconnected, it resumes after its Last-Event-ID from the outbox, ReplayBatch events
+// at a time, with the events written up to ReplayLookback seconds before it. The listener of postgres notifications reconnects ReconnectBackoff seconds after it lost its connection.
+type StreamConfig struct {
+	Heartbeat        time.Duration `validate:"gt=0"`
+	Retry            time.Duration `validate:"gt=0"`
+	BufferSize       int           `validate:"gt=0"`
+	ReplayBatch      int           `validate:"gt=0"`
+	ReplayLookback   time.Duration `validate:"gte=0"`
+	ReconnectBackoff time.Duration `validate:"gt=0"`
+}
+
+type PaginationConfig struct {
+	DefaultSize int `validate:"gt=0,ltefield=MaxSize" reload:"true"`
+	MaxSize     int `validate:"gt=0" reload:"true"`
+}
+
+// FeaturesConfig turns features off without a deploy
+type FeaturesConfig struct {
+	FuzzySearch bool `reload:"true"`
+	Suggestions bool `reload:"true"`
+}
+
+// ValidationError lists every invalid or missing field of a config
+type ValidationError struct {
+	Fields []string
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid config: " + strings.Join(e.Fields, "; ")
+}
+
+// ProfilePath returns the config file path, without extension, of the profile
+func ProfilePath(profile string) string {
+	return "./config/config-" + profile
 }
 
+// Profile returns the profile of the APP_PROFILE environment variable, local by default
+func Profile() string {
+	if profile := os.Getenv(ENV_PROFILE); profile != "" {
+		return profile
+	}
+
+	return PROFILE_LOCAL
+}
+
+// LoadConfig reads the config file, overrides its keys with the environment and validates the result.
+// Every key is bound to APP_<SECTION>_<KEY>, or read from the file named by APP_<SECTION>_<KEY>_FILE.
 func LoadConfig(filename string) (*Config, error) {
 
 	var cfg Config
@@ -47,7 +236,6 @@ func LoadConfig(filename string) (*Config, error) {
 	v := viper.New()
 	v.SetConfigName(filename)
 	v.AddConfigPath(".")
-	v.AutomaticEnv()
 
 	if err := v.ReadInConfig(); err != nil {
 		if _, ok := err.(viper.ConfigFileNotFoundError); ok {
@@ -57,11 +245,108 @@ func LoadConfig(filename string) (*Config, error) {
 		return nil, err
 	}
 
+	if err := bindEnvs(v, reflect.TypeOf(cfg), ""); err != nil {
+		return nil, err
+	}
+
 	err := v.Unmarshal(&cfg)
 	if err != nil {
 		log.Print("unable to decode into struct")
 		return nil, err
 	}
 
+	if err = cfg.Validate(); err != nil {
+		return nil, err
+	}
+	cfg.name = filename
+	cfg.file = v.ConfigFileUsed()
+
 	return &cfg, nil
 }
+
+// bindEnvs binds every key of the struct to its environment variable, and sets the keys
+// whose variable with FILE_SUFFIX names a file, e.g. a docker or kubernetes secret
+func bindEnvs(v *viper.Viper, t reflect.Type, prefix string) error {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		key := prefix + strings.ToLower(field.Name)
+
+		if field.Type.Kind() == reflect.Struct {
+			if err := bindEnvs(v, field.Type, key+"."); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := v.BindEnv(key, envName(key)); err != nil {
+			return err
+		}
+
+		if path, ok := os.LookupEnv(envName(key) + FILE_SUFFIX); ok {
+			value, err := os.ReadFile(path)
+			if err != nil {
+				return fmt.Errorf("failed to read %s: %w", envName(key)+FILE_SUFFIX, err)
+			}
+			v.Set(key, strings.TrimRight(string(value), "\r\n"))
+		}
+	}
+
+	return nil
+}
+
+// envName returns the environment variable of a key
+func envName(key string) string {
+	return ENV_PREFIX + "_" + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
+
+// Validate returns a ValidationError listing every invalid field, nil when the config is valid
+func (c *Config) Validate() error {
+	err := validator.New().Struct(c)
+
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		return err
+	}
+
+	fields := make([]string, 0, len(validationErrors))
+	for _, fieldErr := range validationErrors {
+		key := strings.ToLower(strings.TrimPrefix(fieldErr.Namespace(), "Config."))
+		fields = append(fields, fmt.Sprintf("%s (%s) %s", key, envName(key), ruleMessage(fieldErr)))
+	}
+
+	return &ValidationError{Fields: fields}
+}
+
+// ruleMessage describes the failed rule of a field
+func ruleMessage(fieldErr validator.FieldError) string {
+	switch fieldErr.Tag() {
+	case "required", "required_if":
+		return "is required"
+	case "required_with":
+		return "is required with " + strings.ToLower(fieldErr.Param())
+	case "oneof":
+		return fmt.Sprintf("must be one of [%s]", fieldErr.Param())
+	case "hostname_port":
+		return "must be host:port"
+	case "numeric":
+		return "must be a number"
+	case "min":
+		if fieldErr.Param() == "1" {
+			return "must not be empty"
+		}
+		return fmt.Sprintf("must have at least %s values", fieldErr.Param())
+	case "ltefield":
+		return "must be at most " + strings.ToLower(fieldErr.Param())
+	case "gt":
+		return "must be greater than " + fieldErr.Param()
+	case "gte":
+		return "must be at least " + fieldErr.Param()
+	case "lte":
+		return "must be at most " + fieldErr.Param()
+	default:
+		return fmt.Sprintf("failed on the %q rule", fieldErr.Tag())
+	}
+}
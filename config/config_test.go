@@ -0,0 +1,97 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadConfig(t *testing.T) {
+
+	t.Run("LoadConfig", func(t *testing.T) {
+		cfg, err := LoadConfig("config-local")
+		require.NoError(t, err)
+		require.Equal(t, "localhost", cfg.Postgres.Host)
+	})
+
+	t.Run("LoadConfig env", func(t *testing.T) {
+		t.Setenv("APP_POSTGRES_HOST", "postgres")
+		t.Setenv("APP_LOGGER_LEVEL", "debug")
+
+		cfg, err := LoadConfig("config-local")
+		require.NoError(t, err)
+		require.Equal(t, "postgres", cfg.Postgres.Host)
+		require.Equal(t, "debug", cfg.Logger.Level)
+	})
+
+	t.Run("LoadConfig env file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "password")
+		require.NoError(t, os.WriteFile(path, []byte("secret\n"), 0o600))
+		t.Setenv("APP_POSTGRES_PASSWORD_FILE", path)
+
+		cfg, err := LoadConfig("config-local")
+		require.NoError(t, err)
+		require.Equal(t, "secret", cfg.Postgres.Password)
+	})
+
+	t.Run("LoadConfig env file missing", func(t *testing.T) {
+		t.Setenv("APP_POSTGRES_PASSWORD_FILE", filepath.Join(t.TempDir(), "password"))
+
+		_, err := LoadConfig("config-local")
+		require.Error(t, err)
+	})
+
+	t.Run("LoadConfig invalid", func(t *testing.T) {
+
+		// production takes the database credentials and the redis address from the environment only
+		_, err := LoadConfig("config-production")
+
+		var validationErr *ValidationError
+		require.True(t, errors.As(err, &validationErr))
+		require.Equal(t, []string{
+			"server.alloworigins (APP_SERVER_ALLOWORIGINS) must not be empty",
+			"postgres.host (APP_POSTGRES_HOST) is required",
+			"postgres.username (APP_POSTGRES_USERNAME) is required",
+			"postgres.password (APP_POSTGRES_PASSWORD) is required",
+			"postgres.dbname (APP_POSTGRES_DBNAME) is required",
+			"cache.redisaddr (APP_CACHE_REDISADDR) is required",
+		}, validationErr.Fields)
+	})
+
+	t.Run("LoadConfig production", func(t *testing.T) {
+		t.Setenv("APP_POSTGRES_HOST", "postgres")
+		t.Setenv("APP_POSTGRES_USERNAME", "realtemirov")
+		t.Setenv("APP_POSTGRES_PASSWORD", "123456")
+		t.Setenv("APP_POSTGRES_DBNAME", "task-for-dell")
+		t.Setenv("APP_SERVER_ALLOWORIGINS", "https://a.com,https://b.com")
+		t.Setenv("APP_CACHE_REDISADDR", "redis:6379")
+
+		cfg, err := LoadConfig("config-production")
+		require.NoError(t, err)
+		require.Equal(t, "Production", cfg.Server.Mode)
+		require.Equal(t, []string{"https://a.com", "https://b.com"}, cfg.Server.AllowOrigins)
+		require.Equal(t, "redis:6379", cfg.Cache.RedisAddr)
+	})
+}
+
+func TestConfig_Validate(t *testing.T) {
+	t.Parallel()
+
+	cfg, err := LoadConfig("config-local")
+	require.NoError(t, err)
+
+	cfg.Logger.Level = "verbose"
+	cfg.Postgres.Port = "postgres"
+	cfg.Tracing.SampleRatio = 2
+
+	var validationErr *ValidationError
+	require.True(t, errors.As(cfg.Validate(), &validationErr))
+	require.Equal(t, []string{
+		"logger.level (APP_LOGGER_LEVEL) must be one of [debug info warn error dpanic panic fatal]",
+		"postgres.port (APP_POSTGRES_PORT) must be a number",
+		"tracing.sampleratio (APP_TRACING_SAMPLERATIO) must be at most 1",
+	}, validationErr.Fields)
+}
@@ -0,0 +1,200 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// RELOAD_DEBOUNCE groups the events of one save, editors write a file in several steps
+const RELOAD_DEBOUNCE = 100 * time.Millisecond
+
+// Printer logs the reloads, logger.Logger implements it
+type Printer interface {
+	Infof(template string, args ...interface{})
+	Errorf(template string, args ...interface{})
+}
+
+// Change is one key whose value differs between two configs
+type Change struct {
+	Key        string
+	Old        interface{}
+	New        interface{}
+	Reloadable bool
+}
+
+func (c Change) String() string {
+	return fmt.Sprintf("%s: %v -> %v", c.Key, c.Old, c.New)
+}
+
+// Diff returns the keys whose value differs from old to new, fields tagged
+// with reload:"true" are applied without restart, the others are not
+func Diff(old, new *Config) []Change {
+	return diff(reflect.ValueOf(old).Elem(), reflect.ValueOf(new).Elem(), "", nil)
+}
+
+func diff(old, new reflect.Value, prefix string, changes []Change) []Change {
+	for i := 0; i < old.NumField(); i++ {
+		field := old.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		key := prefix + strings.ToLower(field.Name)
+
+		if field.Type.Kind() == reflect.Struct {
+			changes = diff(old.Field(i), new.Field(i), key+".", changes)
+			continue
+		}
+
+		if !reflect.DeepEqual(old.Field(i).Interface(), new.Field(i).Interface()) {
+			changes = append(changes, Change{
+				Key:        key,
+				Old:        old.Field(i).Interface(),
+				New:        new.Field(i).Interface(),
+				Reloadable: field.Tag.Get("reload") == "true",
+			})
+		}
+	}
+
+	return changes
+}
+
+// Reloader reloads the config when its file changes or on SIGHUP. A new config is applied only when
+// it is valid and every changed key is reloadable, otherwise the running one is kept.
+type Reloader struct {
+	mu       sync.Mutex
+	current  *Config
+	log      Printer
+	appliers []func(cfg *Config)
+}
+
+// NewReloader constructs a Reloader of a config returned by LoadConfig
+func NewReloader(cfg *Config, log Printer) *Reloader {
+	return &Reloader{current: cfg, log: log}
+}
+
+// OnReload applies fn to the running config now and to every reloaded config
+func (r *Reloader) OnReload(fn func(cfg *Config)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.appliers = append(r.appliers, fn)
+	fn(r.current)
+}
+
+// Config returns the running config
+func (r *Reloader) Config() *Config {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.current
+}
+
+// Reload reads the config file again and applies it, it returns the applied changes
+func (r *Reloader) Reload() ([]Change, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	cfg, err := LoadConfig(r.current.name)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := Diff(r.current, cfg)
+
+	var rejected []string
+	for _, change := range changes {
+		if !change.Reloadable {
+			rejected = append(rejected, change.Key)
+		}
+	}
+	if len(rejected) > 0 {
+		return nil, fmt.Errorf("%s can not be reloaded, restart to apply", strings.Join(rejected, ", "))
+	}
+	if len(changes) == 0 {
+		return nil, nil
+	}
+
+	r.current = cfg
+	for _, fn := range r.appliers {
+		fn(cfg)
+	}
+
+	return changes, nil
+}
+
+// Watch reloads the config on every change of its file and on SIGHUP until ctx is done
+func (r *Reloader) Watch(ctx context.Context) error {
+	file, err := filepath.Abs(r.current.file)
+	if err != nil {
+		return err
+	}
+
+	// the directory is watched, editors and kubernetes replace the file instead of writing it
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	if err = watcher.Add(filepath.Dir(file)); err != nil {
+		watcher.Close()
+		return err
+	}
+
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+
+	go func() {
+		defer watcher.Close()
+		defer signal.Stop(hup)
+
+		debounce := time.NewTimer(RELOAD_DEBOUNCE)
+		debounce.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if filepath.Clean(event.Name) == file && event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Rename) != 0 {
+					debounce.Reset(RELOAD_DEBOUNCE)
+				}
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				r.log.Errorf("config: watch %s: %v", file, err)
+			case <-debounce.C:
+				r.reload("file changed")
+			case <-hup:
+				r.reload("SIGHUP")
+			}
+		}
+	}()
+
+	return nil
+}
+
+// reload reloads the config and logs what changed
+func (r *Reloader) reload(reason string) {
+	changes, err := r.Reload()
+	if err != nil {
+		r.log.Errorf("config: reload on %s rejected: %v", reason, err)
+		return
+	}
+
+	for _, change := range changes {
+		r.log.Infof("config: reloaded on %s, %s", reason, change)
+	}
+}
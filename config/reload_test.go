@@ -0,0 +1,141 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// printer discards the lines of a Reloader
+type printer struct{}
+
+func (printer) Infof(template string, args ...interface{})  {}
+func (printer) Errorf(template string, args ...interface{}) {}
+
+// copyConfig copies config-local to a temporary directory and returns its name for LoadConfig,
+// with replace applied to the file
+func copyConfig(t *testing.T, replace ...string) (string, string) {
+	data, err := os.ReadFile("config-local.yml")
+	require.NoError(t, err)
+
+	file := filepath.Join(t.TempDir(), "config.yml")
+	require.NoError(t, os.WriteFile(file, []byte(strings.NewReplacer(replace...).Replace(string(data))), 0o600))
+
+	// LoadConfig looks for the name relative to the working directory
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	name, err := filepath.Rel(wd, strings.TrimSuffix(file, ".yml"))
+	require.NoError(t, err)
+
+	return name, file
+}
+
+// rewriteConfig replaces strings of the config file
+func rewriteConfig(t *testing.T, file string, replace ...string) {
+	data, err := os.ReadFile(file)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(file, []byte(strings.NewReplacer(replace...).Replace(string(data))), 0o600))
+}
+
+func TestDiff(t *testing.T) {
+	t.Parallel()
+
+	old, err := LoadConfig("config-local")
+	require.NoError(t, err)
+	new, err := LoadConfig("config-local")
+	require.NoError(t, err)
+
+	require.Empty(t, Diff(old, new))
+
+	new.Logger.Level = "debug"
+	new.Server.Port = ":8001"
+	require.Equal(t, []Change{
+		{Key: "server.port", Old: ":8000", New: ":8001"},
+		{Key: "logger.level", Old: "info", New: "debug", Reloadable: true},
+	}, Diff(old, new))
+}
+
+func TestReloader_Reload(t *testing.T) {
+	t.Parallel()
+
+	name, file := copyConfig(t)
+	cfg, err := LoadConfig(name)
+	require.NoError(t, err)
+
+	// the applier runs on OnReload and on every applied reload
+	var applied []*Config
+	reloader := NewReloader(cfg, printer{})
+	reloader.OnReload(func(cfg *Config) {
+		applied = append(applied, cfg)
+	})
+	require.Equal(t, []*Config{cfg}, applied)
+
+	t.Run("Reload", func(t *testing.T) {
+		rewriteConfig(t, file, "Level: info", "Level: debug", "MaxSize: 50", "MaxSize: 100")
+
+		changes, err := reloader.Reload()
+		require.NoError(t, err)
+		require.Len(t, changes, 2)
+		require.Equal(t, "debug", reloader.Config().Logger.Level)
+		require.Equal(t, 100, reloader.Config().Pagination.MaxSize)
+		require.Len(t, applied, 2)
+	})
+
+	t.Run("Reload unchanged", func(t *testing.T) {
+		changes, err := reloader.Reload()
+		require.NoError(t, err)
+		require.Empty(t, changes)
+		require.Len(t, applied, 2)
+	})
+
+	t.Run("Reload not reloadable", func(t *testing.T) {
+		rewriteConfig(t, file, "Level: debug", "Level: warn", "Port: :8000", "Port: :8001")
+
+		// the running config is kept, the reloadable change included
+		_, err := reloader.Reload()
+		require.EqualError(t, err, "server.port can not be reloaded, restart to apply")
+		require.Equal(t, "debug", reloader.Config().Logger.Level)
+		require.Len(t, applied, 2)
+	})
+
+	t.Run("Reload invalid", func(t *testing.T) {
+		rewriteConfig(t, file, "Port: :8001", "Port: :8000", "Level: warn", "Level: verbose")
+
+		_, err := reloader.Reload()
+		require.Error(t, err)
+		require.Equal(t, "debug", reloader.Config().Logger.Level)
+	})
+}
+
+func TestReloader_Watch(t *testing.T) {
+	t.Parallel()
+
+	name, file := copyConfig(t)
+	cfg, err := LoadConfig(name)
+	require.NoError(t, err)
+
+	applied := make(chan *Config, 1)
+	reloader := NewReloader(cfg, printer{})
+	reloader.OnReload(func(cfg *Config) {
+		applied <- cfg
+	})
+	<-applied
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	require.NoError(t, reloader.Watch(ctx))
+
+	rewriteConfig(t, file, "FuzzySearch: true", "FuzzySearch: false")
+
+	select {
+	case cfg := <-applied:
+		require.False(t, cfg.Features.FuzzySearch)
+	case <-time.After(5 * time.Second):
+		t.Fatal("config was not reloaded")
+	}
+}
@@ -8,5 +8,7 @@ type Handlers interface {
 	Delete() echo.HandlerFunc
 	GetByID() echo.HandlerFunc
 	GetAll() echo.HandlerFunc
+	Bulk() echo.HandlerFunc
+	Export() echo.HandlerFunc
+	Import() echo.HandlerFunc
 }
-
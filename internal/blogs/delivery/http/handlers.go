@@ -1,7 +1,10 @@
 package http
 
 import (
+	"fmt"
+	"io"
 	"net/http"
+	"time"
 
 	echo "github.com/labstack/echo/v4"
 	"github.com/realtemirov/task-for-dell/config"
@@ -36,8 +39,8 @@ func NewBlogsHandlers(cfg *config.Config, blogsUC blogs.UseCase, logger logger.L
 // @Produce  json
 // @Param body body models.BlogSwagger true "blog"
 // @Success 201 {object} models.Blog
-// @Failure 400 {object} httpErrors.ErrorMessage
-// @Failure 500 {object} httpErrors.ErrorMessage
+// @Failure 400 {object} httpErrors.Problem
+// @Failure 500 {object} httpErrors.Problem
 // @Router /blogs [POST]
 func (h *blogsHandlers) Create() echo.HandlerFunc {
 	return func(c echo.Context) error {
@@ -79,9 +82,9 @@ func (h *blogsHandlers) Create() echo.HandlerFunc {
 // @Param id path int true "blog_id"
 // @Param body body models.BlogSwagger true "body"
 // @Success 200 {object} models.Blog
-// @Failure 400 {object} httpErrors.ErrorMessage
-// @Failure 404 {object} httpErrors.ErrorMessage
-// @Failure 500 {object} httpErrors.ErrorMessage
+// @Failure 400 {object} httpErrors.Problem
+// @Failure 404 {object} httpErrors.Problem
+// @Failure 500 {object} httpErrors.Problem
 // @Router /blogs/{id} [PUT]
 func (h *blogsHandlers) Update() echo.HandlerFunc {
 	return func(c echo.Context) error {
@@ -129,9 +132,9 @@ func (h *blogsHandlers) Update() echo.HandlerFunc {
 // @Produce  json
 // @Param id path int true "blog_id"
 // @Success 204 "No Content"
-// @Failure 400 {object} httpErrors.ErrorMessage
-// @Failure 404 {object} httpErrors.ErrorMessage
-// @Failure 500 {object} httpErrors.ErrorMessage
+// @Failure 400 {object} httpErrors.Problem
+// @Failure 404 {object} httpErrors.Problem
+// @Failure 500 {object} httpErrors.Problem
 // @Router /blogs/{id} [DELETE]
 func (h *blogsHandlers) Delete() echo.HandlerFunc {
 	return func(c echo.Context) error {
@@ -161,10 +164,11 @@ func (h *blogsHandlers) Delete() echo.HandlerFunc {
 // @Accept  json
 // @Produce  json
 // @Param id path int true "blog_id"
+// @Param locale query string false "en (default), ru or uz, overrides Accept-Language"
 // @Success 200 {object} models.Blog
-// @Failure 400 {object} httpErrors.ErrorMessage
-// @Failure 404 {object} httpErrors.ErrorMessage
-// @Failure 500 {object} httpErrors.ErrorMessage
+// @Failure 400 {object} httpErrors.Problem
+// @Failure 404 {object} httpErrors.Problem
+// @Failure 500 {object} httpErrors.Problem
 // @Router /blogs/{id} [GET]
 func (h *blogsHandlers) GetByID() echo.HandlerFunc {
 	return func(c echo.Context) error {
@@ -191,15 +195,16 @@ func (h *blogsHandlers) GetByID() echo.HandlerFunc {
 
 // GetAll
 // @Summary GetAll
-// @Description Get all blogs with pagination and search
+// @Description Get all blogs with pagination and search, fuzzy=true ranks titles by trigram similarity
 // @Tags Blogs
 // @Accept  json
 // @Produce  json
 // @Param query query utils.Query true "query"
+// @Param locale query string false "en (default), ru or uz, overrides Accept-Language"
 // @Success 200 {object} models.BlogList
-// @Failure 400 {object} httpErrors.ErrorMessage
-// @Failure 404 {object} httpErrors.ErrorMessage
-// @Failure 500 {object} httpErrors.ErrorMessage
+// @Failure 400 {object} httpErrors.Problem
+// @Failure 404 {object} httpErrors.Problem
+// @Failure 500 {object} httpErrors.Problem
 // @Router /blogs [GET]
 func (h *blogsHandlers) GetAll() echo.HandlerFunc {
 	return func(c echo.Context) error {
@@ -223,3 +228,219 @@ func (h *blogsHandlers) GetAll() echo.HandlerFunc {
 		return c.JSON(http.StatusOK, blogList)
 	}
 }
+
+// Bulk
+// @Summary Bulk
+// @Description Create, update or delete blogs in one request, mode=atomic applies all of them in one transaction or none, mode=best_effort applies every valid operation
+// @Tags Blogs
+// @Accept  json
+// @Produce  json
+// @Param mode query string false "atomic (default) or best_effort"
+// @Param body body []models.BulkOperation true "operations"
+// @Success 200 {object} models.BulkResponse
+// @Success 207 {object} models.BulkResponse
+// @Failure 400 {object} httpErrors.Problem
+// @Failure 500 {object} httpErrors.Problem
+// @Router /blogs/bulk [POST]
+func (h *blogsHandlers) Bulk() echo.HandlerFunc {
+	return func(c echo.Context) error {
+
+		var (
+			err     error
+			mode    string
+			ops     []*models.BulkOperation
+			results []*models.BulkResult
+		)
+
+		mode, err = utils.GetBulkMode(c.QueryParam("mode"))
+		if err != nil {
+			return httpErrors.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		// bind request body to operations
+		if err = c.Bind(&ops); err != nil {
+			return httpErrors.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		if err = utils.ValidateBulkSize(len(ops)); err != nil {
+			return httpErrors.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		results, err = h.blogsUC.Bulk(c.Request().Context(), ops, mode == utils.BULK_MODE_ATOMIC)
+		if err != nil {
+			return httpErrors.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		// status code of every operation
+		for _, result := range results {
+			if result.Err == nil {
+				result.Status = result.SuccessStatus()
+				continue
+			}
+
+			status, problem := httpErrors.ErrResponse(result.Err, utils.GetLocale(c.Request().Context()))
+			result.Status = status
+			result.Error = problem.Code
+			result.Errors = problem.Errors
+		}
+
+		// multi-status when at least one operation failed
+		response := models.NewBulkResponse(mode, results)
+		if response.Failed > 0 {
+			return c.JSON(http.StatusMultiStatus, response)
+		}
+
+		return c.JSON(http.StatusOK, response)
+	}
+}
+
+// Export
+// @Summary Export
+// @Description Stream every blogs matching the GetAll filters as NDJSON or CSV
+// @Tags Blogs
+// @Accept  json
+// @Produce  json
+// @Produce  text/csv
+// @Param format query string false "ndjson (default) or csv"
+// @Param query query utils.Query false "query"
+// @Success 200 {array} models.Blog
+// @Failure 400 {object} httpErrors.Problem
+// @Failure 500 {object} httpErrors.Problem
+// @Router /blogs/export [GET]
+func (h *blogsHandlers) Export() echo.HandlerFunc {
+	return func(c echo.Context) error {
+
+		var (
+			err    error
+			format string
+			query  *utils.Query = &utils.Query{}
+			writer *utils.ExportWriter
+		)
+
+		format, err = utils.GetFormat(c.QueryParam("format"))
+		if err != nil {
+			return httpErrors.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		query, err = utils.GetPaginationFromCtx(c)
+		if err != nil {
+			return httpErrors.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		// the export may take longer than the server write timeout
+		if err = utils.SetWriteDeadline(c, time.Time{}); err != nil {
+			h.logger.FromContext(c.Request().Context()).Warnf("blogsHandlers.Export.SetWriteDeadline: %v", err)
+		}
+
+		// headers are sent with the first row, so errors before it are still reported as usual
+		writer = utils.NewExportWriter(c.Response(), format)
+		err = h.blogsUC.Export(c.Request().Context(), query, func(blog *models.Blog) error {
+			if writer.Written() == 0 {
+				h.writeExportHeader(c, format)
+			}
+			return writer.Write(blog)
+		})
+		if err != nil && writer.Written() == 0 {
+			return httpErrors.ErrResponseWithLog(c, h.logger, err)
+		}
+		if err != nil {
+			// the status is already sent, the truncated body is the only signal left
+			h.logger.FromContext(c.Request().Context()).Errorf("blogsHandlers.Export, Written: %d, Error: %s", writer.Written(), err)
+			return nil
+		}
+
+		if writer.Written() == 0 {
+			h.writeExportHeader(c, format)
+		}
+
+		return writer.Flush()
+	}
+}
+
+// writeExportHeader sends the status and headers of an export
+func (h *blogsHandlers) writeExportHeader(c echo.Context, format string) {
+	c.Response().Header().Set(echo.HeaderContentType, utils.GetFormatContentType(format))
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="blogs.%s"`, format))
+	c.Response().WriteHeader(http.StatusOK)
+}
+
+// Import
+// @Summary Import
+// @Description Upsert blogs from an NDJSON or CSV file, sent as the raw body or the multipart "file" field. Records with an id are inserted or updated, the others are inserted. Rejected lines are reported with their reasons
+// @Tags Blogs
+// @Accept  json
+// @Accept  text/csv
+// @Accept  multipart/form-data
+// @Produce  json
+// @Produce  text/csv
+// @Param format query string false "ndjson (default) or csv"
+// @Param dry_run query bool false "only validate, nothing is written"
+// @Param report query string false "json (default) or csv"
+// @Param file formData file false "file to import"
+// @Success 200 {object} models.ImportReport
+// @Success 207 {object} models.ImportReport
+// @Failure 400 {object} httpErrors.Problem
+// @Failure 500 {object} httpErrors.Problem
+// @Router /blogs/import [POST]
+func (h *blogsHandlers) Import() echo.HandlerFunc {
+	return func(c echo.Context) error {
+
+		var (
+			err          error
+			format       string
+			reportFormat string
+			dryRun       bool
+			file         io.ReadCloser
+			report       *models.ImportReport
+		)
+
+		format, err = utils.GetFormat(c.QueryParam("format"))
+		if err != nil {
+			return httpErrors.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		dryRun, err = utils.GetDryRun(c.QueryParam("dry_run"))
+		if err != nil {
+			return httpErrors.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		reportFormat, err = utils.GetReportFormat(c.QueryParam("report"))
+		if err != nil {
+			return httpErrors.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		// the upload may take longer than the server read and write timeouts
+		if err = utils.SetReadDeadline(c, time.Time{}); err != nil {
+			h.logger.FromContext(c.Request().Context()).Warnf("blogsHandlers.Import.SetReadDeadline: %v", err)
+		}
+		if err = utils.SetWriteDeadline(c, time.Time{}); err != nil {
+			h.logger.FromContext(c.Request().Context()).Warnf("blogsHandlers.Import.SetWriteDeadline: %v", err)
+		}
+
+		file, err = utils.GetImportFile(c)
+		if err != nil {
+			return httpErrors.ErrResponseWithLog(c, h.logger, err)
+		}
+		defer file.Close()
+
+		report, err = h.blogsUC.Import(c.Request().Context(), file, format, dryRun)
+		if err != nil {
+			return httpErrors.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		// multi-status when at least one line was rejected
+		status := http.StatusOK
+		if report.Rejected > 0 {
+			status = http.StatusMultiStatus
+		}
+
+		if reportFormat == utils.FORMAT_CSV {
+			c.Response().Header().Set(echo.HeaderContentType, utils.GetFormatContentType(utils.FORMAT_CSV))
+			c.Response().Header().Set(echo.HeaderContentDisposition, `attachment; filename="blogs-import-report.csv"`)
+			c.Response().WriteHeader(status)
+			return report.WriteCSV(c.Response())
+		}
+
+		return c.JSON(status, report)
+	}
+}
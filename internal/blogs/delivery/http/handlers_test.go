@@ -1,6 +1,9 @@
 package http
 
 import (
+	"context"
+	"database/sql"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -11,6 +14,7 @@ import (
 	"github.com/realtemirov/task-for-dell/internal/blogs/mock"
 	"github.com/realtemirov/task-for-dell/internal/blogs/usecase"
 	"github.com/realtemirov/task-for-dell/internal/models"
+	"github.com/realtemirov/task-for-dell/pkg/httpErrors"
 	"github.com/realtemirov/task-for-dell/pkg/logger"
 	"github.com/realtemirov/task-for-dell/pkg/utils"
 	"github.com/stretchr/testify/require"
@@ -30,8 +34,7 @@ func TestBlogHandlers_Create(t *testing.T) {
 	logger.InitLogger()
 
 	mockBlogUC := mock.NewMockUseCase(ctrl)
-	blogUC := usecase.NewBlogUseCase(cfg, mockBlogUC, logger)
-	blogHandler := NewBlogsHandlers(cfg, blogUC, logger)
+	blogHandler := NewBlogsHandlers(cfg, mockBlogUC, logger)
 	handler := blogHandler.Create()
 
 	t.Run("Create succes case", func(t *testing.T) {
@@ -76,6 +79,45 @@ func TestBlogHandlers_Create(t *testing.T) {
 		echoCtx := e.NewContext(request, response)
 		err = handler(echoCtx)
 		require.NoError(t, err)
+		require.Equal(t, http.StatusBadRequest, response.Code)
+		require.Equal(t, httpErrors.MIMEApplicationProblemJSON, response.Header().Get(echo.HeaderContentType))
+
+		// every failed field is reported by its json name
+		problem := httpErrors.Problem{}
+		require.NoError(t, json.Unmarshal(response.Body.Bytes(), &problem))
+		require.Equal(t, httpErrors.ValidationFailed, problem.Code)
+		require.Len(t, problem.Errors, 2)
+		require.Equal(t, "title", problem.Errors[0].Field)
+		require.Equal(t, "required", problem.Errors[0].Rule)
+		require.Equal(t, "content", problem.Errors[1].Field)
+	})
+
+	t.Run("Create Validate error ru case", func(t *testing.T) {
+		require.NoError(t, httpErrors.LoadMessages())
+
+		bufferData, err := utils.AnyToBytesBuffer(models.Blog{Title: "title-test"})
+		require.NoError(t, err)
+
+		request := httptest.NewRequest(http.MethodPost, "/v1/blogs", strings.NewReader(bufferData.String()))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		request = request.WithContext(utils.WithLocale(request.Context(), utils.ParseAcceptLanguage("ru-RU,ru;q=0.9,en;q=0.8")))
+		response := httptest.NewRecorder()
+
+		e := echo.New()
+		echoCtx := e.NewContext(request, response)
+		err = handler(echoCtx)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusBadRequest, response.Code)
+
+		// title, detail and field messages are in russian
+		problem := httpErrors.Problem{}
+		require.NoError(t, json.Unmarshal(response.Body.Bytes(), &problem))
+		require.Equal(t, "Ошибка проверки данных", problem.Title)
+		require.Equal(t, "1 поле не прошло проверку", problem.Detail)
+		require.Len(t, problem.Errors, 1)
+		require.Equal(t, "content", problem.Errors[0].Field)
+		require.Contains(t, problem.Errors[0].Message, "content")
+		require.NotContains(t, problem.Errors[0].Message, "required")
 	})
 }
 
@@ -92,8 +134,7 @@ func TestBlogHandlers_Update(t *testing.T) {
 	logger.InitLogger()
 
 	mockBlogUC := mock.NewMockUseCase(ctrl)
-	blogUC := usecase.NewBlogUseCase(cfg, mockBlogUC, logger)
-	blogHandler := NewBlogsHandlers(cfg, blogUC, logger)
+	blogHandler := NewBlogsHandlers(cfg, mockBlogUC, logger)
 	handler := blogHandler.Update()
 
 	t.Run("Update succes case", func(t *testing.T) {
@@ -184,8 +225,7 @@ func TestBlogHandlers_GetByID(t *testing.T) {
 	logger := logger.NewApiLogger(cfg)
 	logger.InitLogger()
 	mockBlogUC := mock.NewMockUseCase(ctrl)
-	blogUC := usecase.NewBlogUseCase(cfg, mockBlogUC, logger)
-	blogHandler := NewBlogsHandlers(cfg, blogUC, logger)
+	blogHandler := NewBlogsHandlers(cfg, mockBlogUC, logger)
 	handler := blogHandler.GetByID()
 
 	t.Run("GetByID succes case", func(t *testing.T) {
@@ -237,8 +277,7 @@ func TestBlogHandlers_Delete(t *testing.T) {
 	logger := logger.NewApiLogger(cfg)
 	logger.InitLogger()
 	mockBlogUC := mock.NewMockUseCase(ctrl)
-	blogUC := usecase.NewBlogUseCase(cfg, mockBlogUC, logger)
-	blogHandler := NewBlogsHandlers(cfg, blogUC, logger)
+	blogHandler := NewBlogsHandlers(cfg, mockBlogUC, logger)
 	handler := blogHandler.Delete()
 
 	t.Run("Delete succes case", func(t *testing.T) {
@@ -284,8 +323,7 @@ func TestBlogHandlers_GetAll(t *testing.T) {
 	logger := logger.NewApiLogger(cfg)
 	logger.InitLogger()
 	mockBlogUC := mock.NewMockUseCase(ctrl)
-	blogUC := usecase.NewBlogUseCase(cfg, mockBlogUC, logger)
-	blogHandler := NewBlogsHandlers(cfg, blogUC, logger)
+	blogHandler := NewBlogsHandlers(cfg, mockBlogUC, logger)
 	handler := blogHandler.GetAll()
 
 	t.Run("GetAll succes case", func(t *testing.T) {
@@ -340,3 +378,260 @@ func TestBlogHandlers_GetAll(t *testing.T) {
 		require.NoError(t, err)
 	})
 }
+
+func TestBlogHandlers_Bulk(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cfg, err := config.LoadConfig("./../../../../config/config-local")
+	require.NoError(t, err)
+
+	logger := logger.NewApiLogger(cfg)
+	logger.InitLogger()
+	mockBlogUC := mock.NewMockUseCase(ctrl)
+	blogHandler := NewBlogsHandlers(cfg, mockBlogUC, logger)
+	handler := blogHandler.Bulk()
+
+	t.Run("Bulk succes case", func(t *testing.T) {
+		ops := []*models.BulkOperation{
+			{Op: models.BulkOpCreate, Title: "title-test", Content: "content-test"},
+			{Op: models.BulkOpDelete, ID: 2},
+		}
+
+		bufferData, err := utils.AnyToBytesBuffer(ops)
+		require.NoError(t, err)
+
+		request := httptest.NewRequest(http.MethodPost, "/v1/blogs/bulk", strings.NewReader(bufferData.String()))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		response := httptest.NewRecorder()
+
+		e := echo.New()
+		echoCtx := e.NewContext(request, response)
+
+		mockBlogUC.EXPECT().Bulk(gomock.Any(), gomock.Len(2), true).Return([]*models.BulkResult{
+			{Op: models.BulkOpCreate, ID: 1},
+			{Op: models.BulkOpDelete, ID: 2},
+		}, nil)
+
+		err = handler(echoCtx)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, response.Code)
+	})
+
+	t.Run("Bulk Mode error case", func(t *testing.T) {
+		request := httptest.NewRequest(http.MethodPost, "/v1/blogs/bulk?mode=test", strings.NewReader("[]"))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		response := httptest.NewRecorder()
+
+		e := echo.New()
+		echoCtx := e.NewContext(request, response)
+
+		err = handler(echoCtx)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusBadRequest, response.Code)
+	})
+
+	t.Run("Bulk Size error case", func(t *testing.T) {
+		request := httptest.NewRequest(http.MethodPost, "/v1/blogs/bulk", strings.NewReader("[]"))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		response := httptest.NewRecorder()
+
+		e := echo.New()
+		echoCtx := e.NewContext(request, response)
+
+		err = handler(echoCtx)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusBadRequest, response.Code)
+	})
+}
+
+// TestBlogHandlers_BulkUseCase runs the handler on the usecase, with a mock repository
+func TestBlogHandlers_BulkUseCase(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cfg, err := config.LoadConfig("./../../../../config/config-local")
+	require.NoError(t, err)
+
+	logger := logger.NewApiLogger(cfg)
+	logger.InitLogger()
+	mockBlogRepo := mock.NewMockRepository(ctrl)
+	blogHandler := NewBlogsHandlers(cfg, usecase.NewBlogUseCase(cfg, mockBlogRepo, logger), logger)
+	handler := blogHandler.Bulk()
+
+	// invalid operations are rejected by the usecase, the others reach the repository
+	t.Run("Bulk partial failure case", func(t *testing.T) {
+		ops := []*models.BulkOperation{
+			{Op: models.BulkOpCreate, Title: "title-test", Content: "content-test"},
+			{Op: models.BulkOpCreate, Title: ""},
+		}
+
+		bufferData, err := utils.AnyToBytesBuffer(ops)
+		require.NoError(t, err)
+
+		request := httptest.NewRequest(http.MethodPost, "/v1/blogs/bulk?mode=best_effort", strings.NewReader(bufferData.String()))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		response := httptest.NewRecorder()
+
+		e := echo.New()
+		echoCtx := e.NewContext(request, response)
+
+		mockBlogRepo.EXPECT().Bulk(gomock.Any(), gomock.Len(1), false).Return([]*models.BulkResult{
+			{Op: models.BulkOpCreate, ID: 1},
+		}, nil)
+
+		err = handler(echoCtx)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusMultiStatus, response.Code)
+		require.Contains(t, response.Body.String(), `"status":400`)
+	})
+}
+
+func TestBlogHandlers_Export(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cfg, err := config.LoadConfig("./../../../../config/config-local")
+	require.NoError(t, err)
+
+	logger := logger.NewApiLogger(cfg)
+	logger.InitLogger()
+	mockBlogUC := mock.NewMockUseCase(ctrl)
+	blogHandler := NewBlogsHandlers(cfg, mockBlogUC, logger)
+	handler := blogHandler.Export()
+
+	// export streams two rows through the callback
+	exportRows := func(ctx context.Context, query *utils.Query, fn func(blog *models.Blog) error) error {
+		for i := int64(1); i <= 2; i++ {
+			if err := fn(&models.Blog{ID: i, Title: "title-test", Content: "content-test"}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	t.Run("Export NDJSON succes case", func(t *testing.T) {
+		request := httptest.NewRequest(http.MethodGet, "/v1/blogs/export?search=title", nil)
+		response := httptest.NewRecorder()
+
+		e := echo.New()
+		echoCtx := e.NewContext(request, response)
+
+		mockBlogUC.EXPECT().Export(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(exportRows)
+
+		err = handler(echoCtx)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, response.Code)
+		require.Equal(t, "application/x-ndjson", response.Header().Get(echo.HeaderContentType))
+		require.Len(t, strings.Split(strings.TrimSpace(response.Body.String()), "\n"), 2)
+	})
+
+	t.Run("Export CSV succes case", func(t *testing.T) {
+		request := httptest.NewRequest(http.MethodGet, "/v1/blogs/export?format=csv", nil)
+		response := httptest.NewRecorder()
+
+		e := echo.New()
+		echoCtx := e.NewContext(request, response)
+
+		mockBlogUC.EXPECT().Export(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(exportRows)
+
+		err = handler(echoCtx)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, response.Code)
+		require.True(t, strings.HasPrefix(response.Body.String(), "id,title,content,created_at\n"))
+		require.Len(t, strings.Split(strings.TrimSpace(response.Body.String()), "\n"), 3)
+	})
+
+	t.Run("Export Format error case", func(t *testing.T) {
+		request := httptest.NewRequest(http.MethodGet, "/v1/blogs/export?format=xml", nil)
+		response := httptest.NewRecorder()
+
+		e := echo.New()
+		echoCtx := e.NewContext(request, response)
+
+		err = handler(echoCtx)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusBadRequest, response.Code)
+	})
+
+	t.Run("Export UseCase error case", func(t *testing.T) {
+		request := httptest.NewRequest(http.MethodGet, "/v1/blogs/export", nil)
+		response := httptest.NewRecorder()
+
+		e := echo.New()
+		echoCtx := e.NewContext(request, response)
+
+		mockBlogUC.EXPECT().Export(gomock.Any(), gomock.Any(), gomock.Any()).Return(sql.ErrNoRows)
+
+		err = handler(echoCtx)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusNotFound, response.Code)
+	})
+}
+
+func TestBlogHandlers_Import(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cfg, err := config.LoadConfig("./../../../../config/config-local")
+	require.NoError(t, err)
+
+	logger := logger.NewApiLogger(cfg)
+	logger.InitLogger()
+	mockBlogUC := mock.NewMockRepository(ctrl)
+	blogUC := usecase.NewBlogUseCase(cfg, mockBlogUC, logger)
+	blogHandler := NewBlogsHandlers(cfg, blogUC, logger)
+	handler := blogHandler.Import()
+
+	t.Run("Import CSV succes case", func(t *testing.T) {
+		body := "id,title,content\n1,title-test,content-test\n,title-test,content-test\n"
+		request := httptest.NewRequest(http.MethodPost, "/v1/blogs/import?format=csv", strings.NewReader(body))
+		request.Header.Set(echo.HeaderContentType, "text/csv")
+		response := httptest.NewRecorder()
+
+		e := echo.New()
+		echoCtx := e.NewContext(request, response)
+
+		mockBlogUC.EXPECT().Upsert(gomock.Any(), gomock.Len(2)).Return(nil, nil)
+
+		err = handler(echoCtx)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, response.Code)
+		require.Contains(t, response.Body.String(), `"accepted":2`)
+	})
+
+	t.Run("Import Rejected CSV report case", func(t *testing.T) {
+		body := "{\"title\":\"t\",\"content\":\"content-test\"}\n"
+		request := httptest.NewRequest(http.MethodPost, "/v1/blogs/import?dry_run=true&report=csv", strings.NewReader(body))
+		response := httptest.NewRecorder()
+
+		e := echo.New()
+		echoCtx := e.NewContext(request, response)
+
+		err = handler(echoCtx)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusMultiStatus, response.Code)
+		require.Equal(t, "text/csv; charset=utf-8", response.Header().Get(echo.HeaderContentType))
+		require.True(t, strings.HasPrefix(response.Body.String(), "line,reason,raw\n1,"))
+	})
+
+	t.Run("Import Dry Run error case", func(t *testing.T) {
+		request := httptest.NewRequest(http.MethodPost, "/v1/blogs/import?dry_run=maybe", strings.NewReader(""))
+		response := httptest.NewRecorder()
+
+		e := echo.New()
+		echoCtx := e.NewContext(request, response)
+
+		err = handler(echoCtx)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusBadRequest, response.Code)
+	})
+}
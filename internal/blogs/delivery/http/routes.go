@@ -2,14 +2,20 @@ package http
 
 import (
 	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/realtemirov/task-for-dell/config"
 	"github.com/realtemirov/task-for-dell/internal/blogs"
+	"github.com/realtemirov/task-for-dell/pkg/utils"
 )
 
 // MapBlogsRoutes maps routes for blogs
-func MapBlogsRoutes(blogGroup *echo.Group, h blogs.Handlers) {
+func MapBlogsRoutes(blogGroup *echo.Group, h blogs.Handlers, cfg *config.Config) {
 	blogGroup.POST("", h.Create())
+	blogGroup.POST("/bulk", h.Bulk())
+	blogGroup.POST("/import", h.Import(), middleware.BodyLimit(utils.IMPORT_BODY_LIMIT))
 	blogGroup.PUT("/:id", h.Update())
 	blogGroup.DELETE("/:id", h.Delete())
-	blogGroup.GET("/:id", h.GetByID())
-	blogGroup.GET("", h.GetAll())
+	blogGroup.GET("/export", h.Export())
+	blogGroup.GET("/:id", h.GetByID(), utils.ETag(cfg.Server.CacheControl.BlogsItem))
+	blogGroup.GET("", h.GetAll(), utils.ETag(cfg.Server.CacheControl.BlogsList))
 }
@@ -11,6 +11,7 @@ package mock
 
 import (
 	context "context"
+	io "io"
 	reflect "reflect"
 
 	models "github.com/realtemirov/task-for-dell/internal/models"
@@ -41,6 +42,21 @@ func (m *MockUseCase) EXPECT() *MockUseCaseMockRecorder {
 	return m.recorder
 }
 
+// Bulk mocks base method.
+func (m *MockUseCase) Bulk(ctx context.Context, ops []*models.BulkOperation, atomic bool) ([]*models.BulkResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Bulk", ctx, ops, atomic)
+	ret0, _ := ret[0].([]*models.BulkResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Bulk indicates an expected call of Bulk.
+func (mr *MockUseCaseMockRecorder) Bulk(ctx, ops, atomic any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Bulk", reflect.TypeOf((*MockUseCase)(nil).Bulk), ctx, ops, atomic)
+}
+
 // Create mocks base method.
 func (m *MockUseCase) Create(ctx context.Context, blog *models.Blog) (*models.Blog, error) {
 	m.ctrl.T.Helper()
@@ -70,6 +86,20 @@ func (mr *MockUseCaseMockRecorder) Delete(ctx, blogID any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockUseCase)(nil).Delete), ctx, blogID)
 }
 
+// Export mocks base method.
+func (m *MockUseCase) Export(ctx context.Context, query *utils.Query, fn func(*models.Blog) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Export", ctx, query, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Export indicates an expected call of Export.
+func (mr *MockUseCaseMockRecorder) Export(ctx, query, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Export", reflect.TypeOf((*MockUseCase)(nil).Export), ctx, query, fn)
+}
+
 // GetAll mocks base method.
 func (m *MockUseCase) GetAll(ctx context.Context, query *utils.Query) (*models.BlogList, error) {
 	m.ctrl.T.Helper()
@@ -100,6 +130,21 @@ func (mr *MockUseCaseMockRecorder) GetByID(ctx, blogID any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockUseCase)(nil).GetByID), ctx, blogID)
 }
 
+// Import mocks base method.
+func (m *MockUseCase) Import(ctx context.Context, r io.Reader, format string, dryRun bool) (*models.ImportReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Import", ctx, r, format, dryRun)
+	ret0, _ := ret[0].(*models.ImportReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Import indicates an expected call of Import.
+func (mr *MockUseCaseMockRecorder) Import(ctx, r, format, dryRun any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Import", reflect.TypeOf((*MockUseCase)(nil).Import), ctx, r, format, dryRun)
+}
+
 // Update mocks base method.
 func (m *MockUseCase) Update(ctx context.Context, blog *models.Blog) (*models.Blog, error) {
 	m.ctrl.T.Helper()
@@ -13,4 +13,7 @@ type Repository interface {
 	Delete(ctx context.Context, blogID int64) error
 	GetByID(ctx context.Context, blogID int64) (*models.Blog, error)
 	GetAll(ctx context.Context, query *utils.Query) (*models.BlogList, error)
+	Bulk(ctx context.Context, ops []*models.BulkOperation, atomic bool) ([]*models.BulkResult, error)
+	Export(ctx context.Context, query *utils.Query, fn func(blog *models.Blog) error) error
+	Upsert(ctx context.Context, blogList []*models.Blog) ([]*models.BulkResult, error)
 }
@@ -4,37 +4,54 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/pkg/errors"
 	"github.com/realtemirov/task-for-dell/internal/blogs"
 	"github.com/realtemirov/task-for-dell/internal/models"
+	"github.com/realtemirov/task-for-dell/pkg/db/postgres"
+	"github.com/realtemirov/task-for-dell/pkg/domainErrors"
+	"github.com/realtemirov/task-for-dell/pkg/metrics"
+	"github.com/realtemirov/task-for-dell/pkg/tracing"
 	"github.com/realtemirov/task-for-dell/pkg/utils"
 )
 
+// errBulkRollback rolls back an atomic batch whose failure is reported in its results
+var errBulkRollback = errors.New("bulk rollback")
+
 type blogsRepo struct {
-	db *sqlx.DB
+	db *postgres.DB
 }
 
 // NewBlogsRepository constructor
-func NewBlogsRepository(db *sqlx.DB) blogs.Repository {
+func NewBlogsRepository(db *postgres.DB) blogs.Repository {
 	return &blogsRepo{db: db}
 }
 
 // Create implements blogs.Repository.
 func (r *blogsRepo) Create(ctx context.Context, blog *models.Blog) (*models.Blog, error) {
+	ctx, span := tracing.Start(ctx, "blogsRepo.Create")
+	defer span.End()
+	defer metrics.ObserveQuery("blogs", "Create", time.Now())
+
+	return r.create(ctx, r.db.Writer(ctx), blog)
+}
+
+// create inserts the blog using db, which is either the pool or a transaction.
+func (r *blogsRepo) create(ctx context.Context, db sqlx.ExtContext, blog *models.Blog) (*models.Blog, error) {
 
 	// result for response
 	result := models.Blog{}
 
 	// insert entitiy and scan result
-	if err := r.db.QueryRowxContext(
+	if err := db.QueryRowxContext(
 		ctx,
 		createQuery,
 		&blog.Title,
 		&blog.Content,
 	).StructScan(&result); err != nil {
-		return nil, errors.Wrap(err, "blogsRepo.Create.StructScan")
+		return nil, errors.Wrap(postgres.MapError(err), "blogsRepo.Create.StructScan")
 	}
 
 	// if no error, return result
@@ -43,19 +60,28 @@ func (r *blogsRepo) Create(ctx context.Context, blog *models.Blog) (*models.Blog
 
 // Update implements blogs.Repository.
 func (r *blogsRepo) Update(ctx context.Context, blog *models.Blog) (*models.Blog, error) {
+	ctx, span := tracing.Start(ctx, "blogsRepo.Update")
+	defer span.End()
+	defer metrics.ObserveQuery("blogs", "Update", time.Now())
+
+	return r.update(ctx, r.db.Writer(ctx), blog)
+}
+
+// update updates the blog using db, which is either the pool or a transaction.
+func (r *blogsRepo) update(ctx context.Context, db sqlx.ExtContext, blog *models.Blog) (*models.Blog, error) {
 
 	// response result
 	result := models.Blog{}
 
 	// update entity and scan result
-	if err := r.db.QueryRowxContext(
+	if err := db.QueryRowxContext(
 		ctx,
 		updateQuery,
 		&blog.Title,
 		&blog.Content,
 		&blog.ID,
 	).StructScan(&result); err != nil {
-		return nil, errors.Wrap(err, "blogsRepo.Update.StructScan")
+		return nil, errors.Wrap(postgres.MapError(err), "blogsRepo.Update.StructScan")
 	}
 
 	// if no error, return result
@@ -64,21 +90,30 @@ func (r *blogsRepo) Update(ctx context.Context, blog *models.Blog) (*models.Blog
 
 // Delete implements blogs.Repository.
 func (r *blogsRepo) Delete(ctx context.Context, blogID int64) error {
+	ctx, span := tracing.Start(ctx, "blogsRepo.Delete")
+	defer span.End()
+	defer metrics.ObserveQuery("blogs", "Delete", time.Now())
+
+	return r.delete(ctx, r.db.Writer(ctx), blogID)
+}
+
+// delete deletes the blog using db, which is either the pool or a transaction.
+func (r *blogsRepo) delete(ctx context.Context, db sqlx.ExtContext, blogID int64) error {
 
 	// delete entity and return result
-	result, err := r.db.ExecContext(ctx, deleteQuery, blogID)
+	result, err := db.ExecContext(ctx, deleteQuery, blogID)
 	if err != nil {
-		return errors.Wrap(err, "blogsRepo.Delete.ExecContext")
+		return errors.Wrap(postgres.MapError(err), "blogsRepo.Delete.ExecContext")
 	}
 
 	// if didn't rows affected, return error
 	rowsAffected, err := result.RowsAffected()
 	if err != nil {
-		return errors.Wrap(err, "blogsRepo.Delete.RowsAffected")
+		return errors.Wrap(postgres.MapError(err), "blogsRepo.Delete.RowsAffected")
 	}
 
 	if rowsAffected == 0 {
-		return errors.Wrap(sql.ErrNoRows, "blogsRepo.Delete.RowsAffected")
+		return errors.Wrap(postgres.MapError(sql.ErrNoRows), "blogsRepo.Delete.RowsAffected")
 	}
 
 	return nil
@@ -86,16 +121,19 @@ func (r *blogsRepo) Delete(ctx context.Context, blogID int64) error {
 
 // GetByID implements blogs.Repository.
 func (r *blogsRepo) GetByID(ctx context.Context, blogID int64) (*models.Blog, error) {
+	ctx, span := tracing.Start(ctx, "blogsRepo.GetByID")
+	defer span.End()
+	defer metrics.ObserveQuery("blogs", "GetByID", time.Now())
 
 	result := models.Blog{}
 
 	// get entity by id and scan result
-	if err := r.db.QueryRowxContext(
+	if err := r.db.Reader(ctx).QueryRowxContext(
 		ctx,
 		getByIDQuery,
 		blogID,
 	).StructScan(&result); err != nil {
-		return nil, errors.Wrap(err, "blogsRepo.GetByID.StructScan")
+		return nil, errors.Wrap(postgres.MapError(err), "blogsRepo.GetByID.StructScan")
 	}
 
 	// if no error, return result
@@ -104,6 +142,14 @@ func (r *blogsRepo) GetByID(ctx context.Context, blogID int64) (*models.Blog, er
 
 // GetAll implements blogs.Repository.
 func (r *blogsRepo) GetAll(ctx context.Context, query *utils.Query) (*models.BlogList, error) {
+	ctx, span := tracing.Start(ctx, "blogsRepo.GetAll")
+	defer span.End()
+	defer metrics.ObserveQuery("blogs", "GetAll", time.Now())
+
+	// fuzzy search ranks blogs by trigram similarity instead of exact LIKE
+	if query.Search != "" && query.Fuzzy {
+		return r.getAllFuzzy(ctx, query)
+	}
 
 	var (
 
@@ -111,50 +157,69 @@ func (r *blogsRepo) GetAll(ctx context.Context, query *utils.Query) (*models.Blo
 		totalCount      int
 		totalCountQuery string = getTotalCountQuery
 		allBlogsQuery   string = getAllQuery
+
+		// the arguments of both queries, the offset and limit follow them in the rows query
+		args []interface{}
 	)
 
 	// if search query is empty, get all blogs
 	if query.Search != "" {
 
-		// change query for get total count
-		totalCountQuery = fmt.Sprintf("%s%s", getTotalCountQuery, " AND title LIKE '%"+query.Search+"%' ")
+		// the search text is a parameter, its wildcards match literally
+		totalCountQuery += " AND title LIKE $1"
+		allBlogsQuery += " AND title LIKE $1"
+		args = append(args, "%"+utils.EscapeLike(query.Search)+"%")
+	}
 
-		// change query for get all blogs
-		allBlogsQuery = fmt.Sprintf(`%s %s`, getAllQuery, " AND title LIKE '%"+query.Search+"%' ")
+	// keep only blogs translated to the locale, it is one of the supported locales
+	if locale := query.GetAvailableLocale(); locale != "" {
+		totalCountQuery += fmt.Sprintf(availableLocaleFilter, locale)
+		allBlogsQuery += fmt.Sprintf(availableLocaleFilter, locale)
 	}
 
 	// change query for get all blogs, sort by created_at, add offset and limit
-	allBlogsQuery += fmt.Sprintf(" ORDER BY created_at %s OFFSET $1 LIMIT $2", query.GetSort())
+	allBlogsQuery += fmt.Sprintf(" ORDER BY created_at %s OFFSET $%d LIMIT $%d", query.GetSort(), len(args)+1, len(args)+2)
+
+	// the count and the rows are read from one database, replicas may lag differently
+	reader := r.db.Reader(ctx)
 
 	// get total count and scan result
-	if err := r.db.QueryRowContext(
+	if err := reader.QueryRowContext(
 		ctx,
 		totalCountQuery,
+		args...,
 	).Scan(&totalCount); err != nil {
-		return nil, errors.Wrap(err, "blogsRepo.GetAll.QueryRowContext.Scan")
+		return nil, errors.Wrap(postgres.MapError(err), "blogsRepo.GetAll.QueryRowContext.Scan")
 	}
 
 	// if total count is 0, return empty list
 	if totalCount == 0 {
+
+		// offer "did you mean" titles when the search matched nothing
+		suggestions, err := r.getSuggestions(ctx, reader, query)
+		if err != nil {
+			return nil, err
+		}
+
 		return &models.BlogList{
-			TotalCount: totalCount,
-			TotalPage:  utils.GetTotalPages(totalCount, query.GetLimit()),
-			Page:       query.GetPage(),
-			Limit:      query.GetLimit(),
-			HasMore:    false,
-			Blogs:      make([]*models.Blog, 0),
+			TotalCount:  totalCount,
+			TotalPage:   utils.GetTotalPages(totalCount, query.GetLimit()),
+			Page:        query.GetPage(),
+			Limit:       query.GetLimit(),
+			HasMore:     false,
+			Blogs:       make([]*models.Blog, 0),
+			Suggestions: suggestions,
 		}, nil
 	}
 
 	// get all blogs
-	rows, err := r.db.QueryxContext(
+	rows, err := reader.QueryxContext(
 		ctx,
 		allBlogsQuery,
-		query.GetOffset(),
-		query.GetLimit(),
+		append(args, query.GetOffset(), query.GetLimit())...,
 	)
 	if err != nil {
-		return nil, errors.Wrap(err, "blogsRepo.GetAll.QueryxContext")
+		return nil, errors.Wrap(postgres.MapError(err), "blogsRepo.GetAll.QueryxContext")
 	}
 	defer rows.Close()
 
@@ -165,7 +230,7 @@ func (r *blogsRepo) GetAll(ctx context.Context, query *utils.Query) (*models.Blo
 	for rows.Next() {
 		blog := models.Blog{}
 		if err := rows.StructScan(&blog); err != nil {
-			return nil, errors.Wrap(err, "blogsRepo.GetAll.StructScan")
+			return nil, errors.Wrap(postgres.MapError(err), "blogsRepo.GetAll.StructScan")
 		}
 
 		blogs = append(blogs, &blog)
@@ -173,7 +238,7 @@ func (r *blogsRepo) GetAll(ctx context.Context, query *utils.Query) (*models.Blo
 
 	// if error, return error
 	if err = rows.Err(); err != nil {
-		return nil, errors.Wrap(err, "blogsRepo.GetAll.rows.Err")
+		return nil, errors.Wrap(postgres.MapError(err), "blogsRepo.GetAll.rows.Err")
 	}
 
 	// if no error, return result
@@ -186,3 +251,377 @@ func (r *blogsRepo) GetAll(ctx context.Context, query *utils.Query) (*models.Blo
 		Blogs:      blogs,
 	}, nil
 }
+
+// getAllFuzzy returns blogs whose title is similar to the search text, most similar first.
+func (r *blogsRepo) getAllFuzzy(ctx context.Context, query *utils.Query) (*models.BlogList, error) {
+
+	var (
+		totalCount      int
+		suggestions     []string
+		blogs           = make([]*models.Blog, 0, query.GetLimit())
+		totalCountQuery = getFuzzyTotalCountQuery
+		allQuery        = getAllFuzzyQuery
+	)
+
+	// keep only blogs translated to the locale
+	if locale := query.GetAvailableLocale(); locale != "" {
+		totalCountQuery += fmt.Sprintf(availableLocaleFilter, locale)
+		allQuery += fmt.Sprintf(availableLocaleFilter, locale)
+	}
+
+	// sort by similarity then created_at, add offset and limit
+	allQuery += fmt.Sprintf(" ORDER BY similarity(title, $1) DESC, created_at %s OFFSET $2 LIMIT $3", query.GetSort())
+
+	err := postgres.WithSimilarityThreshold(ctx, r.db.Reader(ctx), query.GetThreshold(), func(tx postgres.Querier) error {
+
+		// get total count and scan result
+		if err := tx.QueryRowContext(
+			ctx,
+			totalCountQuery,
+			query.Search,
+		).Scan(&totalCount); err != nil {
+			return errors.Wrap(postgres.MapError(err), "blogsRepo.getAllFuzzy.QueryRowContext.Scan")
+		}
+
+		// nothing is similar enough, offer "did you mean" titles instead
+		if totalCount == 0 {
+			var err error
+			suggestions, err = r.selectSuggestions(ctx, tx, query.Search)
+			return err
+		}
+
+		rows, err := tx.QueryxContext(
+			ctx,
+			allQuery,
+			query.Search,
+			query.GetOffset(),
+			query.GetLimit(),
+		)
+		if err != nil {
+			return errors.Wrap(postgres.MapError(err), "blogsRepo.getAllFuzzy.QueryxContext")
+		}
+		defer rows.Close()
+
+		// scan rows
+		for rows.Next() {
+			blog := models.Blog{}
+			if err := rows.StructScan(&blog); err != nil {
+				return errors.Wrap(postgres.MapError(err), "blogsRepo.getAllFuzzy.StructScan")
+			}
+
+			blogs = append(blogs, &blog)
+		}
+
+		return errors.Wrap(postgres.MapError(rows.Err()), "blogsRepo.getAllFuzzy.rows.Err")
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.BlogList{
+		TotalCount:  totalCount,
+		TotalPage:   utils.GetTotalPages(totalCount, query.GetLimit()),
+		Page:        query.GetPage(),
+		Limit:       query.GetLimit(),
+		HasMore:     utils.GetHasMore(query.GetPage(), totalCount, query.GetLimit()),
+		Blogs:       blogs,
+		Suggestions: suggestions,
+	}, nil
+}
+
+// getSuggestions returns "did you mean" titles for a search that matched no blogs.
+func (r *blogsRepo) getSuggestions(ctx context.Context, db postgres.Querier, query *utils.Query) ([]string, error) {
+
+	// nothing to suggest without search text
+	if query.Search == "" {
+		return nil, nil
+	}
+
+	var suggestions []string
+	err := postgres.WithSimilarityThreshold(ctx, db, query.GetThreshold(), func(tx postgres.Querier) error {
+		var err error
+		suggestions, err = r.selectSuggestions(ctx, tx, query.Search)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return suggestions, nil
+}
+
+// selectSuggestions selects the titles closest to the search text by word similarity.
+func (r *blogsRepo) selectSuggestions(ctx context.Context, tx postgres.Querier, search string) ([]string, error) {
+
+	suggestions := make([]string, 0, utils.SUGGESTIONS_SIZE)
+	if err := tx.SelectContext(
+		ctx,
+		&suggestions,
+		getSuggestionsQuery,
+		search,
+		utils.SUGGESTIONS_SIZE,
+	); err != nil {
+		return nil, errors.Wrap(postgres.MapError(err), "blogsRepo.selectSuggestions.SelectContext")
+	}
+
+	return suggestions, nil
+}
+
+// Bulk implements blogs.Repository.
+func (r *blogsRepo) Bulk(ctx context.Context, ops []*models.BulkOperation, atomic bool) ([]*models.BulkResult, error) {
+	ctx, span := tracing.Start(ctx, "blogsRepo.Bulk")
+	defer span.End()
+	defer metrics.ObserveQuery("blogs", "Bulk", time.Now())
+
+	// results in the same order as operations
+	results := make([]*models.BulkResult, len(ops))
+
+	// best effort, every operation is applied on its own, in a savepoint of the transaction of ctx
+	if !atomic {
+		for i, op := range ops {
+			err := r.db.WithinSavepoint(ctx, func(ctx context.Context) error {
+				results[i] = r.execBulkOperation(ctx, r.db.Writer(ctx), op)
+				return results[i].Err
+			})
+			if results[i] == nil {
+				results[i] = &models.BulkResult{Op: op.Op, ID: op.ID, Err: err}
+			}
+		}
+
+		return results, nil
+	}
+
+	// atomic, every operation is applied in one transaction, or a savepoint of the transaction of ctx
+	err := r.db.WithinTx(ctx, func(ctx context.Context) error {
+		for i, op := range ops {
+			results[i] = r.execBulkOperation(ctx, r.db.Writer(ctx), op)
+			if results[i].Err == nil {
+				continue
+			}
+
+			// one failure rolls back the whole batch
+			for j := range ops {
+				if j == i {
+					continue
+				}
+				results[j] = &models.BulkResult{
+					Op:  ops[j].Op,
+					ID:  ops[j].ID,
+					Err: utils.ErrBulkAborted,
+				}
+			}
+
+			return errBulkRollback
+		}
+
+		return nil
+	})
+	if err != nil && !errors.Is(err, errBulkRollback) {
+		return nil, errors.Wrap(err, "blogsRepo.Bulk.WithinTx")
+	}
+
+	return results, nil
+}
+
+// execBulkOperation applies one bulk operation using db, which is either the pool or a transaction.
+func (r *blogsRepo) execBulkOperation(ctx context.Context, db sqlx.ExtContext, op *models.BulkOperation) *models.BulkResult {
+
+	var (
+		err    error
+		blog   *models.Blog
+		result = &models.BulkResult{Op: op.Op, ID: op.ID}
+	)
+
+	switch op.Op {
+	case models.BulkOpCreate:
+		blog, err = r.create(ctx, db, &models.Blog{Title: op.Title, Content: op.Content})
+	case models.BulkOpUpdate:
+		blog, err = r.update(ctx, db, &models.Blog{ID: op.ID, Title: op.Title, Content: op.Content})
+	case models.BulkOpDelete:
+		err = r.delete(ctx, db, op.ID)
+	default:
+		err = domainErrors.Invalid(fmt.Sprintf("unknown operation %q", op.Op), nil)
+	}
+
+	if err != nil {
+		result.Err = err
+		return result
+	}
+
+	if blog != nil {
+		result.ID = blog.ID
+		result.Data = blog
+	}
+
+	return result
+}
+
+// Export implements blogs.Repository.
+func (r *blogsRepo) Export(ctx context.Context, query *utils.Query, fn func(blog *models.Blog) error) error {
+	ctx, span := tracing.Start(ctx, "blogsRepo.Export")
+	defer span.End()
+	defer metrics.ObserveQuery("blogs", "Export", time.Now())
+
+	// fuzzy search streams blogs similar to the search text, most similar first
+	if query.Search != "" && query.Fuzzy {
+		return postgres.WithSimilarityThreshold(ctx, r.db.Reader(ctx), query.GetThreshold(), func(tx postgres.Querier) error {
+			exportQuery := getAllFuzzyQuery + fmt.Sprintf(" ORDER BY similarity(title, $1) DESC, created_at %s, id", query.GetSort())
+			return r.export(ctx, tx, fn, exportQuery, query.Search)
+		})
+	}
+
+	var (
+		exportQuery string = getAllQuery
+		args        []interface{}
+	)
+
+	// if search query is not empty, filter by title
+	if query.Search != "" {
+		exportQuery += " AND title LIKE $1"
+		args = append(args, "%"+utils.EscapeLike(query.Search)+"%")
+	}
+
+	// sort by created_at, without offset and limit
+	exportQuery += fmt.Sprintf(" ORDER BY created_at %s, id", query.GetSort())
+
+	return r.export(ctx, r.db.Reader(ctx), fn, exportQuery, args...)
+}
+
+// export scans rows of the query one by one and passes them to fn, rows are never held in memory.
+func (r *blogsRepo) export(ctx context.Context, db sqlx.QueryerContext, fn func(blog *models.Blog) error, query string, args ...interface{}) error {
+
+	rows, err := db.QueryxContext(ctx, query, args...)
+	if err != nil {
+		return errors.Wrap(postgres.MapError(err), "blogsRepo.Export.QueryxContext")
+	}
+	defer rows.Close()
+
+	// scan rows
+	for rows.Next() {
+		blog := models.Blog{}
+		if err := rows.StructScan(&blog); err != nil {
+			return errors.Wrap(postgres.MapError(err), "blogsRepo.Export.StructScan")
+		}
+
+		if err := fn(&blog); err != nil {
+			return err
+		}
+	}
+
+	return errors.Wrap(postgres.MapError(rows.Err()), "blogsRepo.Export.rows.Err")
+}
+
+// upsertedRow is a row written by Upsert, inserted or updated
+type upsertedRow struct {
+	models.Blog
+	Inserted bool `db:"inserted"`
+}
+
+// Upsert implements blogs.Repository, the result of every row written is a create or an update with the row as data.
+func (r *blogsRepo) Upsert(ctx context.Context, blogList []*models.Blog) ([]*models.BulkResult, error) {
+	ctx, span := tracing.Start(ctx, "blogsRepo.Upsert")
+	defer span.End()
+	defer metrics.ObserveQuery("blogs", "Upsert", time.Now())
+
+	var (
+		inserts    []interface{}
+		insertRows int
+		upserts    []interface{}
+		upsertRows int
+		dated      []interface{}
+		datedRows  int
+	)
+
+	// blogs with id are inserted or updated, the others are inserted
+	for _, blog := range blogList {
+		createdAt := blog.CreatedAt
+		if createdAt.IsZero() {
+			createdAt = time.Now()
+		}
+
+		// an existing row keeps its created_at unless one is given
+		if blog.ID > 0 && !blog.CreatedAt.IsZero() {
+			dated = append(dated, blog.ID, blog.Title, blog.Content, createdAt)
+			datedRows++
+			continue
+		}
+		if blog.ID > 0 {
+			upserts = append(upserts, blog.ID, blog.Title, blog.Content, createdAt)
+			upsertRows++
+			continue
+		}
+
+		inserts = append(inserts, blog.Title, blog.Content, createdAt)
+		insertRows++
+	}
+
+	// one transaction, or a savepoint of the transaction of ctx
+	rows := make([]*upsertedRow, 0, len(blogList))
+	err := r.db.WithinTx(ctx, func(ctx context.Context) error {
+		tx := r.db.Writer(ctx)
+		rows = rows[:0]
+
+		// explicit ids first, then move the sequence so inserted ids do not collide
+		if datedRows > 0 {
+			if err := sqlx.SelectContext(
+				ctx,
+				tx,
+				&rows,
+				fmt.Sprintf(upsertManyQuery, postgres.Placeholders(datedRows, 4))+upsertCreatedAtQuery+returningUpsertedQuery,
+				dated...,
+			); err != nil {
+				return errors.Wrap(postgres.MapError(err), "blogsRepo.Upsert.SelectContext")
+			}
+		}
+
+		if upsertRows > 0 {
+			upserted := make([]*upsertedRow, 0, upsertRows)
+			if err := sqlx.SelectContext(
+				ctx,
+				tx,
+				&upserted,
+				fmt.Sprintf(upsertManyQuery, postgres.Placeholders(upsertRows, 4))+returningUpsertedQuery,
+				upserts...,
+			); err != nil {
+				return errors.Wrap(postgres.MapError(err), "blogsRepo.Upsert.SelectContext")
+			}
+			rows = append(rows, upserted...)
+		}
+
+		if datedRows+upsertRows > 0 {
+			if _, err := tx.ExecContext(ctx, syncIDSequenceQuery); err != nil {
+				return errors.Wrap(postgres.MapError(err), "blogsRepo.Upsert.syncIDSequence")
+			}
+		}
+
+		if insertRows > 0 {
+			inserted := make([]*upsertedRow, 0, insertRows)
+			if err := sqlx.SelectContext(
+				ctx,
+				tx,
+				&inserted,
+				insertManyQuery+postgres.Placeholders(insertRows, 3)+returningUpsertedQuery,
+				inserts...,
+			); err != nil {
+				return errors.Wrap(postgres.MapError(err), "blogsRepo.Upsert.insertMany")
+			}
+			rows = append(rows, inserted...)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*models.BulkResult, 0, len(rows))
+	for _, row := range rows {
+		result := &models.BulkResult{Op: models.BulkOpUpdate, ID: row.ID, Data: &row.Blog}
+		if row.Inserted {
+			result.Op = models.BulkOpCreate
+		}
+		results = append(results, result)
+	}
+
+	return results, nil
+}
@@ -47,4 +47,64 @@ var (
 	FROM blogs
 	WHERE
 		1=1`, fieldsOfBlogsTable)
+
+	// query for get total count of blogs similar to the search text, uses pg_trgm.similarity_threshold.
+	getFuzzyTotalCountQuery = `SELECT COUNT(id) FROM blogs WHERE title % $1`
+
+	// query for get blogs similar to the search text, uses pg_trgm.similarity_threshold.
+	getAllFuzzyQuery = fmt.Sprintf(`
+	SELECT 
+		%s 
+	FROM blogs
+	WHERE
+		title %% $1`, fieldsOfBlogsTable)
+
+	// filter of blogs translated to a locale, formatted with a supported locale.
+	availableLocaleFilter = ` AND EXISTS (SELECT 1 FROM translations WHERE content_type = 'blog' AND content_id = blogs.id AND locale = '%s')`
+
+	// query for get "did you mean" titles, uses pg_trgm.word_similarity_threshold.
+	getSuggestionsQuery = `
+	SELECT
+		title
+	FROM blogs
+	WHERE
+		$1 <% title
+	GROUP BY title
+	ORDER BY word_similarity($1, title) DESC, title
+	LIMIT $2`
+
+	// query prefix for insert many blogs, followed by one placeholder row per blog.
+	insertManyQuery = `
+	INSERT INTO blogs
+	(
+		title,
+		content,
+		created_at
+	)
+	VALUES `
+
+	// query for insert or update many blogs by id, formatted with placeholder rows.
+	upsertManyQuery = `
+	INSERT INTO blogs
+	(
+		id,
+		title,
+		content,
+		created_at
+	)
+	VALUES %s
+	ON CONFLICT (id) DO UPDATE SET
+		title = EXCLUDED.title,
+		content = EXCLUDED.content`
+
+	// suffix of upsertManyQuery updating created_at too, for rows given with their date.
+	upsertCreatedAtQuery = `,
+		created_at = EXCLUDED.created_at`
+
+	// suffix of insertManyQuery and upsertManyQuery returning the rows written, inserted or updated.
+	returningUpsertedQuery = `
+	RETURNING ` + fieldsOfBlogsTable + `, (xmax = 0) AS inserted`
+
+	// query for move the id sequence past ids inserted explicitly.
+	syncIDSequenceQuery = `SELECT setval(pg_get_serial_sequence('blogs', 'id'), (SELECT COALESCE(MAX(id), 1) FROM blogs))`
 )
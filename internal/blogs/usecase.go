@@ -2,6 +2,7 @@ package blogs
 
 import (
 	"context"
+	"io"
 
 	"github.com/realtemirov/task-for-dell/internal/models"
 	"github.com/realtemirov/task-for-dell/pkg/utils"
@@ -13,4 +14,7 @@ type UseCase interface {
 	Delete(ctx context.Context, blogID int64) error
 	GetByID(ctx context.Context, blogID int64) (*models.Blog, error)
 	GetAll(ctx context.Context, query *utils.Query) (*models.BlogList, error)
+	Bulk(ctx context.Context, ops []*models.BulkOperation, atomic bool) ([]*models.BulkResult, error)
+	Export(ctx context.Context, query *utils.Query, fn func(blog *models.Blog) error) error
+	Import(ctx context.Context, r io.Reader, format string, dryRun bool) (*models.ImportReport, error)
 }
@@ -0,0 +1,178 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/realtemirov/task-for-dell/internal/blogs"
+	"github.com/realtemirov/task-for-dell/internal/models"
+	"github.com/realtemirov/task-for-dell/pkg/cache"
+	"github.com/realtemirov/task-for-dell/pkg/db/postgres"
+	"github.com/realtemirov/task-for-dell/pkg/logger"
+	"github.com/realtemirov/task-for-dell/pkg/tracing"
+	"github.com/realtemirov/task-for-dell/pkg/utils"
+)
+
+const (
+	// cacheKeyBlog prefixes the cached blog of an id
+	cacheKeyBlog = "blogs:id:"
+
+	// cacheKeyList prefixes the cached lists, every write deletes them all
+	cacheKeyList = "blogs:list:"
+)
+
+// cachedBlogUC serves GetByID and GetAll from a cache, writes delete the entries they change.
+// Misses are read from the primary, a lagging replica would cache rows older than the write.
+// Every miss is, another instance may have written just before, see CacheConfig.
+type cachedBlogUC struct {
+	blogs.UseCase
+	loader *cache.Loader
+	log    logger.Logger
+}
+
+// Cached Blog UseCase constructor, entries live for ttl
+func NewCachedBlogUseCase(uc blogs.UseCase, c cache.Cache, ttl time.Duration, log logger.Logger) blogs.UseCase {
+	return &cachedBlogUC{
+		UseCase: uc,
+		loader:  cache.NewLoader("blogs", c, ttl),
+		log:     log,
+	}
+}
+
+// GetByID implements blogs.UseCase.
+func (u *cachedBlogUC) GetByID(ctx context.Context, blogID int64) (*models.Blog, error) {
+	ctx, span := tracing.Start(ctx, "cachedBlogUC.GetByID")
+	defer span.End()
+
+	blog := &models.Blog{}
+	err := u.loader.Load(ctx, blogCacheKey(blogID), blog, func(ctx context.Context) (interface{}, error) {
+		return u.UseCase.GetByID(postgres.WithPrimary(ctx), blogID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return blog, nil
+}
+
+// GetAll implements blogs.UseCase.
+func (u *cachedBlogUC) GetAll(ctx context.Context, query *utils.Query) (*models.BlogList, error) {
+	ctx, span := tracing.Start(ctx, "cachedBlogUC.GetAll")
+	defer span.End()
+
+	// lists filtered by translations change with them, they are not cached
+	if query.GetAvailableLocale() != "" {
+		return u.UseCase.GetAll(ctx, query)
+	}
+
+	result := &models.BlogList{}
+	err := u.loader.Load(ctx, listCacheKey(query), result, func(ctx context.Context) (interface{}, error) {
+		return u.UseCase.GetAll(postgres.WithPrimary(ctx), query)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Create implements blogs.UseCase.
+func (u *cachedBlogUC) Create(ctx context.Context, blog *models.Blog) (*models.Blog, error) {
+
+	created, err := u.UseCase.Create(ctx, blog)
+	if err != nil {
+		return nil, err
+	}
+	u.invalidate(ctx)
+
+	return created, nil
+}
+
+// Update implements blogs.UseCase.
+func (u *cachedBlogUC) Update(ctx context.Context, blog *models.Blog) (*models.Blog, error) {
+
+	updated, err := u.UseCase.Update(ctx, blog)
+	if err != nil {
+		return nil, err
+	}
+	u.invalidate(ctx, blog.ID)
+
+	return updated, nil
+}
+
+// Delete implements blogs.UseCase.
+func (u *cachedBlogUC) Delete(ctx context.Context, blogID int64) error {
+
+	if err := u.UseCase.Delete(ctx, blogID); err != nil {
+		return err
+	}
+	u.invalidate(ctx, blogID)
+
+	return nil
+}
+
+// Bulk implements blogs.UseCase.
+func (u *cachedBlogUC) Bulk(ctx context.Context, ops []*models.BulkOperation, atomic bool) ([]*models.BulkResult, error) {
+
+	results, err := u.UseCase.Bulk(ctx, ops, atomic)
+
+	// some operations may be applied even when others failed
+	ids := make([]int64, 0, len(ops))
+	for _, op := range ops {
+		if op.ID > 0 {
+			ids = append(ids, op.ID)
+		}
+	}
+	u.invalidate(ctx, ids...)
+
+	return results, err
+}
+
+// Import implements blogs.UseCase.
+func (u *cachedBlogUC) Import(ctx context.Context, r io.Reader, format string, dryRun bool) (*models.ImportReport, error) {
+
+	report, err := u.UseCase.Import(ctx, r, format, dryRun)
+	if dryRun {
+		return report, err
+	}
+
+	// imports update blogs by id in batches, every cached blog may be stale
+	if deleteErr := u.loader.InvalidatePrefix(ctx, cacheKeyBlog); deleteErr != nil {
+		u.log.FromContext(ctx).Warnf("cachedBlogUC.Import: failed to invalidate blogs: %v", deleteErr)
+	}
+	u.invalidate(ctx)
+
+	return report, err
+}
+
+// invalidate deletes the cached blogs of ids and every cached list. Failures are logged,
+// the entries then expire after their ttl.
+func (u *cachedBlogUC) invalidate(ctx context.Context, ids ...int64) {
+
+	keys := make([]string, 0, len(ids))
+	for _, id := range ids {
+		keys = append(keys, blogCacheKey(id))
+	}
+	if err := u.loader.Invalidate(ctx, keys...); err != nil {
+		u.log.FromContext(ctx).Warnf("cachedBlogUC.invalidate: failed to delete blogs %v: %v", ids, err)
+	}
+
+	if err := u.loader.InvalidatePrefix(ctx, cacheKeyList); err != nil {
+		u.log.FromContext(ctx).Warnf("cachedBlogUC.invalidate: failed to delete lists: %v", err)
+	}
+}
+
+// blogCacheKey returns the cache key of the blog of id
+func blogCacheKey(id int64) string {
+	return fmt.Sprintf("%s%d", cacheKeyBlog, id)
+}
+
+// listCacheKey returns the cache key of the list of query, the config threshold applies when it is 0
+func listCacheKey(query *utils.Query) string {
+	return fmt.Sprintf(
+		"%slimit=%d:page=%d:sort=%s:fuzzy=%t:threshold=%g:search=%q",
+		cacheKeyList, query.Limit, query.Page, query.Sort, query.Fuzzy, query.Threshold, query.Search,
+	)
+}
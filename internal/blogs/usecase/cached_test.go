@@ -0,0 +1,197 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/realtemirov/task-for-dell/internal/blogs/mock"
+	"github.com/realtemirov/task-for-dell/internal/models"
+	"github.com/realtemirov/task-for-dell/pkg/cache"
+	"github.com/realtemirov/task-for-dell/pkg/db/postgres"
+	"github.com/realtemirov/task-for-dell/pkg/domainErrors"
+	"github.com/realtemirov/task-for-dell/pkg/logger"
+	"github.com/realtemirov/task-for-dell/pkg/utils"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestCachedBlogUC_GetByID(t *testing.T) {
+	t.Parallel()
+
+	// Create a new instance of the gomock controller
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// usecase of blog behind the cache
+	mockBlogUC := mock.NewMockUseCase(ctrl)
+	blogUC := NewCachedBlogUseCase(mockBlogUC, cache.NewMemory(10), time.Minute, logger.NewApiLogger(nil))
+	ctx := context.Background()
+
+	t.Run("GetByID cached", func(t *testing.T) {
+
+		// only the first call reads the blog
+		mockBlogUC.EXPECT().GetByID(gomock.Any(), int64(1)).Return(&models.Blog{ID: 1, Title: "title"}, nil).Times(1)
+
+		for i := 0; i < 2; i++ {
+			blog, err := blogUC.GetByID(ctx, 1)
+			require.NoError(t, err)
+			require.Equal(t, "title", blog.Title)
+		}
+	})
+
+	t.Run("GetByID miss read from the primary", func(t *testing.T) {
+
+		// a lagging replica would be cached until the ttl
+		mockBlogUC.EXPECT().GetByID(gomock.Any(), int64(2)).DoAndReturn(
+			func(ctx context.Context, id int64) (*models.Blog, error) {
+				require.True(t, postgres.UsePrimary(ctx))
+				return &models.Blog{ID: id}, nil
+			},
+		)
+
+		_, err := blogUC.GetByID(ctx, 2)
+		require.NoError(t, err)
+	})
+
+	t.Run("GetByID copies", func(t *testing.T) {
+
+		// callers may change the blog, e.g. to translate it, without changing the cache
+		blog, err := blogUC.GetByID(ctx, 1)
+		require.NoError(t, err)
+		blog.Title = "translated"
+
+		blog, err = blogUC.GetByID(ctx, 1)
+		require.NoError(t, err)
+		require.Equal(t, "title", blog.Title)
+	})
+
+	t.Run("GetByID invalidated by Update", func(t *testing.T) {
+
+		updated := &models.Blog{ID: 1, Title: "updated"}
+		mockBlogUC.EXPECT().Update(gomock.Any(), updated).Return(updated, nil)
+		mockBlogUC.EXPECT().GetByID(gomock.Any(), int64(1)).Return(updated, nil).Times(1)
+
+		_, err := blogUC.Update(ctx, updated)
+		require.NoError(t, err)
+
+		blog, err := blogUC.GetByID(ctx, 1)
+		require.NoError(t, err)
+		require.Equal(t, "updated", blog.Title)
+	})
+
+	t.Run("GetByID invalidated by Delete", func(t *testing.T) {
+
+		mockBlogUC.EXPECT().Delete(gomock.Any(), int64(1)).Return(nil)
+		mockBlogUC.EXPECT().GetByID(gomock.Any(), int64(1)).Return(nil, domainErrors.NotFound("record not found", nil))
+
+		require.NoError(t, blogUC.Delete(ctx, 1))
+
+		_, err := blogUC.GetByID(ctx, 1)
+		require.Equal(t, domainErrors.KindNotFound, domainErrors.KindOf(err))
+	})
+
+	t.Run("GetByID updated during a slow load", func(t *testing.T) {
+
+		started, release := make(chan struct{}), make(chan struct{})
+		updated := &models.Blog{ID: 3, Title: "updated"}
+		gomock.InOrder(
+			mockBlogUC.EXPECT().GetByID(gomock.Any(), int64(3)).DoAndReturn(
+				func(ctx context.Context, id int64) (*models.Blog, error) {
+					close(started)
+					<-release
+					return &models.Blog{ID: id, Title: "title"}, nil
+				},
+			),
+			mockBlogUC.EXPECT().GetByID(gomock.Any(), int64(3)).Return(updated, nil),
+		)
+		mockBlogUC.EXPECT().Update(gomock.Any(), updated).Return(updated, nil)
+
+		// the load reads the blogs before the update, which commits meanwhile
+		loaded := make(chan error, 1)
+		go func() {
+			_, err := blogUC.GetByID(ctx, 3)
+			loaded <- err
+		}()
+		<-started
+		_, err := blogUC.Update(ctx, updated)
+		require.NoError(t, err)
+		close(release)
+		require.NoError(t, <-loaded)
+
+		// the blogs read before the update is not cached
+		blog, err := blogUC.GetByID(ctx, 3)
+		require.NoError(t, err)
+		require.Equal(t, "updated", blog.Title)
+
+		blog, err = blogUC.GetByID(ctx, 3)
+		require.NoError(t, err)
+		require.Equal(t, "updated", blog.Title)
+	})
+}
+
+func TestCachedBlogUC_GetAll(t *testing.T) {
+	t.Parallel()
+
+	// Create a new instance of the gomock controller
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// usecase of blog behind the cache
+	mockBlogUC := mock.NewMockUseCase(ctrl)
+	blogUC := NewCachedBlogUseCase(mockBlogUC, cache.NewMemory(10), time.Minute, logger.NewApiLogger(nil))
+	ctx := context.Background()
+
+	list := &models.BlogList{TotalCount: 1, Blogs: []*models.Blog{{ID: 1, Title: "title"}}}
+
+	t.Run("GetAll cached by query", func(t *testing.T) {
+
+		// one read for each distinct query
+		mockBlogUC.EXPECT().GetAll(gomock.Any(), gomock.Any()).Return(list, nil).Times(2)
+
+		for i := 0; i < 2; i++ {
+			for _, page := range []int{1, 2} {
+				result, err := blogUC.GetAll(ctx, &utils.Query{Limit: 10, Page: page})
+				require.NoError(t, err)
+				require.Equal(t, 1, result.TotalCount)
+			}
+		}
+	})
+
+	t.Run("GetAll invalidated by Create", func(t *testing.T) {
+
+		blog := &models.Blog{Title: "new title", Content: "new content"}
+		mockBlogUC.EXPECT().Create(gomock.Any(), blog).Return(&models.Blog{ID: 2}, nil)
+		mockBlogUC.EXPECT().GetAll(gomock.Any(), gomock.Any()).Return(list, nil).Times(1)
+
+		_, err := blogUC.Create(ctx, blog)
+		require.NoError(t, err)
+
+		_, err = blogUC.GetAll(ctx, &utils.Query{Limit: 10, Page: 1})
+		require.NoError(t, err)
+	})
+
+	t.Run("GetAll invalidated by Import", func(t *testing.T) {
+
+		mockBlogUC.EXPECT().Import(gomock.Any(), gomock.Any(), utils.FORMAT_CSV, false).Return(&models.ImportReport{}, nil)
+		mockBlogUC.EXPECT().GetAll(gomock.Any(), gomock.Any()).Return(list, nil).Times(1)
+
+		_, err := blogUC.Import(ctx, strings.NewReader(""), utils.FORMAT_CSV, false)
+		require.NoError(t, err)
+
+		_, err = blogUC.GetAll(ctx, &utils.Query{Limit: 10, Page: 1})
+		require.NoError(t, err)
+	})
+
+	t.Run("GetAll by available locale not cached", func(t *testing.T) {
+
+		query := &utils.Query{Limit: 10, AvailableLocale: utils.LOCALE_RU}
+		mockBlogUC.EXPECT().GetAll(gomock.Any(), query).Return(list, nil).Times(2)
+
+		for i := 0; i < 2; i++ {
+			_, err := blogUC.GetAll(ctx, query)
+			require.NoError(t, err)
+		}
+	})
+}
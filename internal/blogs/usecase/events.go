@@ -0,0 +1,192 @@
+package usecase
+
+import (
+	"context"
+	"io"
+
+	"github.com/realtemirov/task-for-dell/internal/blogs"
+	"github.com/realtemirov/task-for-dell/internal/models"
+	"github.com/realtemirov/task-for-dell/internal/outbox"
+	"github.com/realtemirov/task-for-dell/pkg/db/postgres"
+	"github.com/realtemirov/task-for-dell/pkg/tracing"
+)
+
+// eventsBlogUC writes the events of blog changes to the outbox, in the transaction of the change
+type eventsBlogUC struct {
+	blogs.UseCase
+	tx     postgres.TxManager
+	outbox outbox.Repository
+}
+
+// Events Blog UseCase constructor
+func NewEventsBlogUseCase(uc blogs.UseCase, tx postgres.TxManager, outboxRepo outbox.Repository) blogs.UseCase {
+	return &eventsBlogUC{
+		UseCase: uc,
+		tx:      tx,
+		outbox:  outboxRepo,
+	}
+}
+
+// Create implements blogs.UseCase.
+func (u *eventsBlogUC) Create(ctx context.Context, blog *models.Blog) (*models.Blog, error) {
+	ctx, span := tracing.Start(ctx, "eventsBlogUC.Create")
+	defer span.End()
+
+	var created *models.Blog
+	err := u.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		if created, err = u.UseCase.Create(ctx, blog); err != nil {
+			return err
+		}
+
+		return u.add(ctx, created.ID, created, models.EventContentCreated, models.EventContentPublished)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+// Update implements blogs.UseCase.
+func (u *eventsBlogUC) Update(ctx context.Context, blog *models.Blog) (*models.Blog, error) {
+	ctx, span := tracing.Start(ctx, "eventsBlogUC.Update")
+	defer span.End()
+
+	var updated *models.Blog
+	err := u.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		if updated, err = u.UseCase.Update(ctx, blog); err != nil {
+			return err
+		}
+
+		return u.add(ctx, updated.ID, updated, models.EventContentUpdated)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return updated, nil
+}
+
+// Delete implements blogs.UseCase.
+func (u *eventsBlogUC) Delete(ctx context.Context, blogID int64) error {
+	ctx, span := tracing.Start(ctx, "eventsBlogUC.Delete")
+	defer span.End()
+
+	return u.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := u.UseCase.Delete(ctx, blogID); err != nil {
+			return err
+		}
+
+		return u.add(ctx, blogID, nil, models.EventContentDeleted)
+	})
+}
+
+// Bulk implements blogs.UseCase, only the operations applied emit events.
+func (u *eventsBlogUC) Bulk(ctx context.Context, ops []*models.BulkOperation, atomic bool) ([]*models.BulkResult, error) {
+	ctx, span := tracing.Start(ctx, "eventsBlogUC.Bulk")
+	defer span.End()
+
+	var results []*models.BulkResult
+	err := u.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		if results, err = u.UseCase.Bulk(ctx, ops, atomic); err != nil {
+			return err
+		}
+
+		events, err := resultEvents(results)
+		if err != nil {
+			return err
+		}
+
+		return u.outbox.Add(ctx, events...)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// Import implements blogs.UseCase, every batch the import writes is written in a transaction with its events.
+func (u *eventsBlogUC) Import(ctx context.Context, r io.Reader, format string, dryRun bool) (*models.ImportReport, error) {
+	ctx, span := tracing.Start(ctx, "eventsBlogUC.Import")
+	defer span.End()
+
+	return u.UseCase.Import(models.WithImportBatchWrapper(ctx, u.importBatch), r, format, dryRun)
+}
+
+// importBatch writes a batch of an import and the events of the rows it applied, in one transaction
+func (u *eventsBlogUC) importBatch(ctx context.Context, write models.ImportBatchFunc) error {
+	return u.tx.WithinTx(ctx, func(ctx context.Context) error {
+		applied, err := write(ctx)
+		if err != nil {
+			return err
+		}
+
+		events, err := resultEvents(applied)
+		if err != nil {
+			return err
+		}
+
+		return u.outbox.Add(ctx, events...)
+	})
+}
+
+// resultEvents returns the events of the applied results of a bulk or an import
+func resultEvents(results []*models.BulkResult) ([]*models.Event, error) {
+	events := make([]*models.Event, 0, len(results))
+	for _, result := range results {
+		if result == nil || result.Err != nil {
+			continue
+		}
+
+		var types []string
+		switch result.Op {
+		case models.BulkOpCreate:
+			types = []string{models.EventContentCreated, models.EventContentPublished}
+		case models.BulkOpUpdate:
+			types = []string{models.EventContentUpdated}
+		case models.BulkOpDelete:
+			types = []string{models.EventContentDeleted}
+		}
+
+		resultEvents, err := newEvents(result.ID, result.Data, types...)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, resultEvents...)
+	}
+
+	return events, nil
+}
+
+// add writes events of types of the blog of id to the outbox
+func (u *eventsBlogUC) add(ctx context.Context, id int64, blog *models.Blog, types ...string) error {
+	var payload interface{}
+	if blog != nil {
+		payload = blog
+	}
+
+	events, err := newEvents(id, payload, types...)
+	if err != nil {
+		return err
+	}
+
+	return u.outbox.Add(ctx, events...)
+}
+
+// newEvents returns the events of types of the blog of id, payload is the blog, nil for deletes
+func newEvents(id int64, payload interface{}, types ...string) ([]*models.Event, error) {
+	events := make([]*models.Event, 0, len(types))
+	for _, eventType := range types {
+		event, err := models.NewContentEvent(eventType, models.ContentTypeBlog, id, payload)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+
+	return events, nil
+}
@@ -0,0 +1,84 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/realtemirov/task-for-dell/internal/blogs"
+	"github.com/realtemirov/task-for-dell/internal/models"
+	"github.com/realtemirov/task-for-dell/internal/translations"
+	"github.com/realtemirov/task-for-dell/pkg/utils"
+)
+
+// localizedBlogUC translates the blogs it reads to the locale of the request
+type localizedBlogUC struct {
+	blogs.UseCase
+	translationsUC translations.UseCase
+}
+
+// Localized Blog UseCase constructor, blogs without a translation stay in the default locale
+func NewLocalizedBlogUseCase(uc blogs.UseCase, translationsUC translations.UseCase) blogs.UseCase {
+	return &localizedBlogUC{
+		UseCase:        uc,
+		translationsUC: translationsUC,
+	}
+}
+
+// GetByID implements blogs.UseCase.
+func (u *localizedBlogUC) GetByID(ctx context.Context, blogID int64) (*models.Blog, error) {
+
+	blog, err := u.UseCase.GetByID(ctx, blogID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = u.localize(ctx, []*models.Blog{blog}); err != nil {
+		return nil, err
+	}
+
+	return blog, nil
+}
+
+// GetAll implements blogs.UseCase.
+func (u *localizedBlogUC) GetAll(ctx context.Context, query *utils.Query) (*models.BlogList, error) {
+
+	result, err := u.UseCase.GetAll(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = u.localize(ctx, result.Blogs); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// localize replaces title and content of the blogs with their translations
+func (u *localizedBlogUC) localize(ctx context.Context, blogList []*models.Blog) error {
+
+	if len(blogList) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, 0, len(blogList))
+	for _, blog := range blogList {
+		ids = append(ids, blog.ID)
+	}
+
+	locale := utils.GetLocale(ctx)
+	translated, err := u.translationsUC.GetMany(ctx, models.ContentTypeBlog, ids, locale)
+	if err != nil {
+		return err
+	}
+
+	for _, blog := range blogList {
+		blog.Locale = utils.DEFAULT_LOCALE
+		if translation, ok := translated[blog.ID]; ok {
+			blog.Title = translation.Title
+			blog.Content = translation.Content
+			blog.Locale = locale
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,97 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/realtemirov/task-for-dell/internal/blogs/mock"
+	"github.com/realtemirov/task-for-dell/internal/models"
+	translationsMock "github.com/realtemirov/task-for-dell/internal/translations/mock"
+	"github.com/realtemirov/task-for-dell/pkg/utils"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestLocalizedBlogUC_GetByID(t *testing.T) {
+	t.Parallel()
+
+	// Create a new instance of the gomock controller
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// usecases of blog and translations
+	mockBlogUC := mock.NewMockUseCase(ctrl)
+	mockTranslationsUC := translationsMock.NewMockUseCase(ctrl)
+	blogUC := NewLocalizedBlogUseCase(mockBlogUC, mockTranslationsUC)
+
+	t.Run("GetByID translated", func(t *testing.T) {
+		ctx := utils.WithLocale(context.Background(), utils.LOCALE_RU)
+
+		// mock the blog and its russian translation
+		mockBlogUC.EXPECT().GetByID(ctx, int64(1)).Return(&models.Blog{ID: 1, Title: "title", Content: "content"}, nil)
+		mockTranslationsUC.EXPECT().GetMany(ctx, models.ContentTypeBlog, []int64{1}, utils.LOCALE_RU).Return(
+			map[int64]*models.Translation{1: {ContentID: 1, Locale: utils.LOCALE_RU, Title: "заголовок", Content: "содержание"}}, nil,
+		)
+
+		// call the GetByID method of the usecase
+		blog, err := blogUC.GetByID(ctx, 1)
+
+		// check the result
+		require.NoError(t, err)
+		require.Equal(t, "заголовок", blog.Title)
+		require.Equal(t, "содержание", blog.Content)
+		require.Equal(t, utils.LOCALE_RU, blog.Locale)
+	})
+
+	t.Run("GetByID fallback", func(t *testing.T) {
+		ctx := utils.WithLocale(context.Background(), utils.LOCALE_UZ)
+
+		// mock the blog without uzbek translation
+		mockBlogUC.EXPECT().GetByID(ctx, int64(1)).Return(&models.Blog{ID: 1, Title: "title", Content: "content"}, nil)
+		mockTranslationsUC.EXPECT().GetMany(ctx, models.ContentTypeBlog, []int64{1}, utils.LOCALE_UZ).Return(
+			map[int64]*models.Translation{}, nil,
+		)
+
+		// call the GetByID method of the usecase
+		blog, err := blogUC.GetByID(ctx, 1)
+
+		// check the result
+		require.NoError(t, err)
+		require.Equal(t, "title", blog.Title)
+		require.Equal(t, utils.DEFAULT_LOCALE, blog.Locale)
+	})
+}
+
+func TestLocalizedBlogUC_GetAll(t *testing.T) {
+	t.Parallel()
+
+	// Create a new instance of the gomock controller
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// usecases of blog and translations
+	mockBlogUC := mock.NewMockUseCase(ctrl)
+	mockTranslationsUC := translationsMock.NewMockUseCase(ctrl)
+	blogUC := NewLocalizedBlogUseCase(mockBlogUC, mockTranslationsUC)
+
+	ctx := utils.WithLocale(context.Background(), utils.LOCALE_RU)
+	query := &utils.Query{}
+
+	// mock two blogs, only the second is translated
+	mockBlogUC.EXPECT().GetAll(ctx, query).Return(&models.BlogList{
+		Blogs: []*models.Blog{{ID: 1, Title: "first"}, {ID: 2, Title: "second"}},
+	}, nil)
+	mockTranslationsUC.EXPECT().GetMany(ctx, models.ContentTypeBlog, []int64{1, 2}, utils.LOCALE_RU).Return(
+		map[int64]*models.Translation{2: {ContentID: 2, Locale: utils.LOCALE_RU, Title: "второй"}}, nil,
+	)
+
+	// call the GetAll method of the usecase
+	result, err := blogUC.GetAll(ctx, query)
+
+	// check the result
+	require.NoError(t, err)
+	require.Equal(t, "first", result.Blogs[0].Title)
+	require.Equal(t, utils.DEFAULT_LOCALE, result.Blogs[0].Locale)
+	require.Equal(t, "второй", result.Blogs[1].Title)
+	require.Equal(t, utils.LOCALE_RU, result.Blogs[1].Locale)
+}
@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"errors"
+	"io"
+	"os"
+
+	blogRepo "github.com/realtemirov/task-for-dell/internal/blogs/repository"
+	blogUseCase "github.com/realtemirov/task-for-dell/internal/blogs/usecase"
+	"github.com/realtemirov/task-for-dell/internal/models"
+	newsRepo "github.com/realtemirov/task-for-dell/internal/news/repository"
+	newsUseCase "github.com/realtemirov/task-for-dell/internal/news/usecase"
+	"github.com/realtemirov/task-for-dell/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+// errContentType is returned for a --type other than blog or news
+var errContentType = errors.New("type must be blog or news")
+
+// newExportCmd exports blogs or news as NDJSON or CSV, with the filters of GET /blogs and GET /news.
+//
+//	task-for-dell export --type news --format csv --search dell --file news.csv
+func newExportCmd() *cobra.Command {
+
+	var (
+		contentType string
+		format      string
+		filePath    string
+		query       utils.Query
+	)
+
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export blogs or news as NDJSON or CSV",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format, err := utils.GetFormat(format)
+			if err != nil {
+				return err
+			}
+			if err = query.SetAvailableLocale(query.AvailableLocale); err != nil {
+				return err
+			}
+
+			var output io.Writer = cmd.OutOrStdout()
+			if filePath != "" {
+				file, err := os.Create(filePath)
+				if err != nil {
+					return err
+				}
+				defer file.Close()
+				output = file
+			}
+
+			cfg, log, db, err := connect()
+			if err != nil {
+				return err
+			}
+			defer db.Close()
+
+			writer := utils.NewExportWriter(output, format)
+			switch contentType {
+			case models.ContentTypeBlog:
+				uc := blogUseCase.NewBlogUseCase(cfg, blogRepo.NewBlogsRepository(db), log)
+				err = uc.Export(cmd.Context(), &query, func(blog *models.Blog) error {
+					return writer.Write(blog)
+				})
+			case models.ContentTypeNews:
+				uc := newsUseCase.NewNewsUseCase(cfg, newsRepo.NewNewsRepository(db), log)
+				err = uc.Export(cmd.Context(), &query, func(news *models.New) error {
+					return writer.Write(news)
+				})
+			default:
+				return errContentType
+			}
+			if err != nil {
+				return err
+			}
+
+			if err = writer.Flush(); err != nil {
+				return err
+			}
+			log.Infof("Exported: %d", writer.Written())
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&contentType, "type", models.ContentTypeBlog, "content to export: blog or news")
+	cmd.Flags().StringVar(&format, "format", utils.FORMAT_NDJSON, "file format: ndjson or csv")
+	cmd.Flags().StringVar(&filePath, "file", "", "file to export to, stdout by default")
+	cmd.Flags().StringVar(&query.Search, "search", "", "only export contents whose title matches")
+	cmd.Flags().StringVar(&query.Sort, "sort", "", "sort of the exported contents")
+	cmd.Flags().StringVar(&query.AvailableLocale, "available-locale", "", "only export contents translated to the locale")
+
+	return cmd
+}
@@ -0,0 +1,121 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"strings"
+
+	blogRepo "github.com/realtemirov/task-for-dell/internal/blogs/repository"
+	blogUseCase "github.com/realtemirov/task-for-dell/internal/blogs/usecase"
+	"github.com/realtemirov/task-for-dell/internal/models"
+	newsRepo "github.com/realtemirov/task-for-dell/internal/news/repository"
+	newsUseCase "github.com/realtemirov/task-for-dell/internal/news/usecase"
+	outboxRepo "github.com/realtemirov/task-for-dell/internal/outbox/repository"
+	"github.com/realtemirov/task-for-dell/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+// errRejected is returned when some lines of an import are rejected, so the command exits with 1
+var errRejected = errors.New("some lines were rejected")
+
+// newImportCmd imports blogs or news from an NDJSON or CSV file.
+//
+//	task-for-dell import --type blog --format csv --file blogs.csv --dry-run --report report.csv
+func newImportCmd() *cobra.Command {
+
+	var (
+		contentType string
+		format      string
+		filePath    string
+		dryRun      bool
+		reportPath  string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "import",
+		Short: "Import blogs or news from an NDJSON or CSV file",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if _, err := utils.GetFormat(format); err != nil {
+				return err
+			}
+
+			var input io.Reader = cmd.InOrStdin()
+			if filePath != "" {
+				file, err := os.Open(filePath)
+				if err != nil {
+					return err
+				}
+				defer file.Close()
+				input = file
+			}
+
+			cfg, log, db, err := connect()
+			if err != nil {
+				return err
+			}
+			defer db.Close()
+
+			// imported contents emit their events, like the ones of the api
+			outboxPGRepo := outboxRepo.NewOutboxRepository(db)
+
+			var report *models.ImportReport
+			switch contentType {
+			case models.ContentTypeBlog:
+				uc := blogUseCase.NewBlogUseCase(cfg, blogRepo.NewBlogsRepository(db), log)
+				uc = blogUseCase.NewEventsBlogUseCase(uc, db, outboxPGRepo)
+				report, err = uc.Import(cmd.Context(), input, format, dryRun)
+			case models.ContentTypeNews:
+				uc := newsUseCase.NewNewsUseCase(cfg, newsRepo.NewNewsRepository(db), log)
+				uc = newsUseCase.NewEventsNewsUseCase(uc, db, outboxPGRepo)
+				report, err = uc.Import(cmd.Context(), input, format, dryRun)
+			default:
+				return errContentType
+			}
+			if err != nil {
+				return err
+			}
+
+			log.Infof("Total: %d, Accepted: %d, Rejected: %d, DryRun: %t", report.Total, report.Accepted, report.Rejected, report.DryRun)
+
+			if reportPath != "" {
+				if err = writeReport(reportPath, report); err != nil {
+					return err
+				}
+			}
+
+			if report.Rejected > 0 {
+				return errRejected
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&contentType, "type", models.ContentTypeBlog, "content to import: blog or news")
+	cmd.Flags().StringVar(&format, "format", utils.FORMAT_NDJSON, "file format: ndjson or csv")
+	cmd.Flags().StringVar(&filePath, "file", "", "file to import, stdin by default")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "only validate, nothing is written")
+	cmd.Flags().StringVar(&reportPath, "report", "", "write rejected lines to this file, as csv when it ends with .csv")
+
+	return cmd
+}
+
+// writeReport writes the report as csv or json depending on the file extension
+func writeReport(path string, report *models.ImportReport) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if strings.HasSuffix(path, ".csv") {
+		return report.WriteCSV(file)
+	}
+
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
@@ -0,0 +1,105 @@
+package cli
+
+import (
+	"strconv"
+
+	"github.com/realtemirov/task-for-dell/pkg/db/migrate"
+	"github.com/spf13/cobra"
+)
+
+func newMigrateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Apply or revert the embedded migrations",
+	}
+
+	cmd.AddCommand(
+		&cobra.Command{
+			Use:   "up",
+			Short: "Apply every migration not applied yet",
+			Args:  cobra.NoArgs,
+			RunE: withMigrator(func(migrator *migrate.Migrator, args []string) error {
+				return migrator.Up()
+			}),
+		},
+		&cobra.Command{
+			Use:   "down [steps]",
+			Short: "Revert the last steps migrations, 1 by default",
+			Args:  cobra.MaximumNArgs(1),
+			RunE: withMigrator(func(migrator *migrate.Migrator, args []string) error {
+				steps := 1
+				if len(args) == 1 {
+					var err error
+					if steps, err = strconv.Atoi(args[0]); err != nil {
+						return err
+					}
+				}
+				return migrator.Down(steps)
+			}),
+		},
+		&cobra.Command{
+			Use:   "goto <version>",
+			Short: "Apply or revert migrations up to the version",
+			Args:  cobra.ExactArgs(1),
+			RunE: withMigrator(func(migrator *migrate.Migrator, args []string) error {
+				version, err := strconv.ParseUint(args[0], 10, 64)
+				if err != nil {
+					return err
+				}
+				return migrator.Goto(uint(version))
+			}),
+		},
+		&cobra.Command{
+			Use:   "force <version>",
+			Short: "Set the version of a dirty schema fixed by hand, without running migrations",
+			Args:  cobra.ExactArgs(1),
+			RunE: withMigrator(func(migrator *migrate.Migrator, args []string) error {
+				version, err := strconv.Atoi(args[0])
+				if err != nil {
+					return err
+				}
+				return migrator.Force(version)
+			}),
+		},
+		&cobra.Command{
+			Use:   "status",
+			Short: "Print the applied and the latest version",
+			Args:  cobra.NoArgs,
+			RunE: withMigrator(func(migrator *migrate.Migrator, args []string) error {
+				return nil
+			}),
+		},
+	)
+
+	return cmd
+}
+
+// withMigrator runs the operation with a migrator of the database, then prints the status
+func withMigrator(operation func(migrator *migrate.Migrator, args []string) error) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+
+		_, _, db, err := connect()
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+
+		migrator, err := migrate.NewMigrator(cmd.Context(), db.DB)
+		if err != nil {
+			return err
+		}
+		defer migrator.Close()
+
+		if err = operation(migrator, args); err != nil {
+			return err
+		}
+
+		status, err := migrator.Status()
+		if err != nil {
+			return err
+		}
+		cmd.Printf("version: %d, dirty: %t, latest: %d\n", status.Version, status.Dirty, status.Latest)
+
+		return nil
+	}
+}
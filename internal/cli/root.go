@@ -0,0 +1,82 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/realtemirov/task-for-dell/config"
+	"github.com/realtemirov/task-for-dell/pkg/db/postgres"
+	"github.com/realtemirov/task-for-dell/pkg/logger"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// configPath is the value of the --config flag of every command, it overrides --profile
+	configPath string
+
+	// profile is the value of the --profile flag of every command
+	profile string
+)
+
+// rootCmd is the app, every feature is one of its subcommands
+var rootCmd = &cobra.Command{
+	Use:           "task-for-dell",
+	Short:         "Blog and News API server and admin commands",
+	SilenceUsage:  true,
+	SilenceErrors: true,
+}
+
+func init() {
+	rootCmd.PersistentFlags().StringVarP(&configPath, "config", "c", "", "config file path without extension, ./config/config-<profile> by default")
+	rootCmd.PersistentFlags().StringVarP(&profile, "profile", "p", config.Profile(), "config profile: local, docker or production, $APP_PROFILE by default")
+
+	rootCmd.AddCommand(
+		newServeCmd(),
+		newMigrateCmd(),
+		newSeedCmd(),
+		newExportCmd(),
+		newImportCmd(),
+		newUserCmd(),
+	)
+}
+
+// Execute runs the command of the arguments and exits with 1 when it fails
+func Execute() {
+	if err := rootCmd.Execute(); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+	}
+}
+
+// loadConfig loads the config of the --config or --profile flag with its logger
+func loadConfig() (*config.Config, logger.Logger, error) {
+	path := configPath
+	if path == "" {
+		path = config.ProfilePath(profile)
+	}
+
+	cfg, err := config.LoadConfig(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	log := logger.NewApiLogger(cfg)
+	log.InitLogger()
+
+	return cfg, log, nil
+}
+
+// connect loads the config and connects to its primary database, commands do not read from replicas
+func connect() (*config.Config, logger.Logger, *postgres.DB, error) {
+	cfg, log, err := loadConfig()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	db, err := postgres.NewPostgresDB(cfg, log)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to connect to db: %w", err)
+	}
+
+	return cfg, log, postgres.NewDB(db), nil
+}
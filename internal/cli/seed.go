@@ -0,0 +1,113 @@
+package cli
+
+import (
+	"context"
+	"math/rand"
+	"strings"
+	"time"
+
+	blogRepo "github.com/realtemirov/task-for-dell/internal/blogs/repository"
+	"github.com/realtemirov/task-for-dell/internal/models"
+	newsRepo "github.com/realtemirov/task-for-dell/internal/news/repository"
+	"github.com/realtemirov/task-for-dell/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+// seedWords are the words fake titles and contents are made of
+var seedWords = strings.Fields(`
+	lorem ipsum dolor sit amet consectetur adipiscing elit sed do eiusmod tempor
+	incididunt ut labore et dolore magna aliqua enim ad minim veniam quis nostrud
+	exercitation ullamco laboris nisi aliquip ex ea commodo consequat duis aute irure
+	in reprehenderit voluptate velit esse cillum fugiat nulla pariatur excepteur sint
+	occaecat cupidatat non proident sunt culpa qui officia deserunt mollit anim id est laborum
+`)
+
+// newSeedCmd inserts fake blogs or news, e.g. to try the search and the pagination.
+//
+//	task-for-dell seed --type news --count 10000
+func newSeedCmd() *cobra.Command {
+
+	var (
+		contentType string
+		count       int
+		batchSize   int
+	)
+
+	cmd := &cobra.Command{
+		Use:   "seed",
+		Short: "Insert fake blogs or news",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if batchSize <= 0 {
+				batchSize = utils.IMPORT_BATCH_SIZE
+			}
+
+			_, log, db, err := connect()
+			if err != nil {
+				return err
+			}
+			defer db.Close()
+
+			// upsert inserts one batch of fake contents of the type
+			var upsert func(ctx context.Context, size int) error
+			switch contentType {
+			case models.ContentTypeBlog:
+				repo := blogRepo.NewBlogsRepository(db)
+				upsert = func(ctx context.Context, size int) error {
+					blogList := make([]*models.Blog, 0, size)
+					for i := 0; i < size; i++ {
+						blogList = append(blogList, &models.Blog{Title: fakeText(3, 8), Content: fakeText(20, 60), CreatedAt: fakeTime()})
+					}
+					_, err := repo.Upsert(ctx, blogList)
+					return err
+				}
+			case models.ContentTypeNews:
+				repo := newsRepo.NewNewsRepository(db)
+				upsert = func(ctx context.Context, size int) error {
+					newsList := make([]*models.New, 0, size)
+					for i := 0; i < size; i++ {
+						newsList = append(newsList, &models.New{Title: fakeText(3, 8), Content: fakeText(20, 60), CreatedAt: fakeTime()})
+					}
+					_, err := repo.Upsert(ctx, newsList)
+					return err
+				}
+			default:
+				return errContentType
+			}
+
+			for seeded := 0; seeded < count; seeded += batchSize {
+				size := batchSize
+				if count-seeded < size {
+					size = count - seeded
+				}
+				if err = upsert(cmd.Context(), size); err != nil {
+					return err
+				}
+				log.Infof("Seeded: %d/%d", seeded+size, count)
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&contentType, "type", models.ContentTypeBlog, "content to seed: blog or news")
+	cmd.Flags().IntVar(&count, "count", 100, "number of contents to insert")
+	cmd.Flags().IntVar(&batchSize, "batch", utils.IMPORT_BATCH_SIZE, "number of contents inserted by one statement")
+
+	return cmd
+}
+
+// fakeText returns between min and max random words
+func fakeText(min, max int) string {
+	words := make([]string, min+rand.Intn(max-min+1))
+	for i := range words {
+		words[i] = seedWords[rand.Intn(len(seedWords))]
+	}
+
+	return strings.Join(words, " ")
+}
+
+// fakeTime returns a random time of the last year
+func fakeTime() time.Time {
+	return time.Now().Add(-time.Duration(rand.Int63n(int64(365 * 24 * time.Hour))))
+}
@@ -0,0 +1,142 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/realtemirov/task-for-dell/config"
+	"github.com/realtemirov/task-for-dell/internal/outbox/relay"
+	outboxRepo "github.com/realtemirov/task-for-dell/internal/outbox/repository"
+	"github.com/realtemirov/task-for-dell/internal/outbox/sink"
+	"github.com/realtemirov/task-for-dell/internal/server"
+	"github.com/realtemirov/task-for-dell/internal/webhooks/dispatcher"
+	webhooksRepo "github.com/realtemirov/task-for-dell/internal/webhooks/repository"
+	"github.com/realtemirov/task-for-dell/pkg/cache"
+	"github.com/realtemirov/task-for-dell/pkg/db/migrate"
+	"github.com/realtemirov/task-for-dell/pkg/db/postgres"
+	"github.com/realtemirov/task-for-dell/pkg/httpErrors"
+	"github.com/realtemirov/task-for-dell/pkg/tracing"
+	"github.com/realtemirov/task-for-dell/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+func newServeCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "serve",
+		Short: "Start the API server",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return serve(cmd.Context())
+		},
+	}
+}
+
+// serve runs the server until SIGTERM
+func serve(ctx context.Context) error {
+
+	cfg, log, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	if err = httpErrors.LoadMessages(); err != nil {
+		return fmt.Errorf("failed to load error messages: %w", err)
+	}
+
+	log.Info("Application started")
+	log.Infof("AppVersion: %s, LogLevel: %s, Mode: %s", cfg.Server.AppVersion, cfg.Logger.Level, cfg.Server.Mode)
+
+	shutdownTracing, err := tracing.NewTracerProvider(ctx, cfg)
+	if err != nil {
+		return fmt.Errorf("failed to init tracing: %w", err)
+	}
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			log.Errorf("failed to shutdown tracing: %v", err)
+		}
+	}()
+
+	db, err := postgres.NewReplicatedDB(cfg, log)
+	if err != nil {
+		return fmt.Errorf("failed to connect to db: %w", err)
+	}
+	log.Info("successfully connected to db")
+	defer db.Close()
+
+	// apply the embedded migrations, other instances wait for the advisory lock
+	if cfg.Postgres.AutoMigrate {
+		if err = migrateUp(ctx, db.DB); err != nil {
+			return fmt.Errorf("failed to migrate db: %w", err)
+		}
+		log.Info("successfully migrated db")
+	}
+
+	// reads go to the replicas which answer health checks
+	ctx, stopWatch := context.WithCancel(ctx)
+	defer stopWatch()
+	db.WatchReplicas(ctx, cfg.Postgres.ReplicaCheckInterval*time.Second, log)
+
+	// blogs and news reads are cached unless the cache driver is none
+	readCache, err := cache.NewCache(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to init cache: %w", err)
+	}
+	if readCache != nil {
+		log.Infof("caching reads in %s for %ds", cfg.Cache.Driver, cfg.Cache.TTL)
+		defer readCache.Close()
+	}
+
+	// content events of the outbox are delivered by the instances with the relay enabled,
+	// to the sink of the config and as deliveries to the webhook subscriptions of each event
+	webhooksPGRepo := webhooksRepo.NewWebhooksRepository(db)
+	if cfg.Outbox.Relay {
+		eventSink, closeSink, err := sink.NewSink(cfg, log)
+		if err != nil {
+			return fmt.Errorf("failed to init outbox sink: %w", err)
+		}
+		defer closeSink()
+		eventSink = sink.NewMultiSink(eventSink, dispatcher.NewSubscriptionsSink(webhooksPGRepo))
+
+		log.Infof("relaying outbox events to %s and webhook subscriptions", cfg.Outbox.Sink)
+		go relay.NewRelay(cfg, outboxRepo.NewOutboxRepository(db), eventSink, log).Run(ctx)
+	}
+
+	// webhook deliveries are posted by the instances with the dispatcher enabled
+	if cfg.Webhooks.Dispatch {
+		client := dispatcher.NewClient(cfg)
+		defer client.CloseIdleConnections()
+
+		go dispatcher.NewDispatcher(cfg, webhooksPGRepo, client, log).Run(ctx)
+	}
+
+	// log level, page sizes, CORS origins and features follow the config file and SIGHUP
+	serv := server.NewServer(cfg, log, db, readCache)
+	reloader := config.NewReloader(cfg, log)
+	reloader.OnReload(func(cfg *config.Config) {
+		if err := log.SetLevel(cfg.Logger.Level); err != nil {
+			log.Errorf("failed to set log level: %v", err)
+		}
+		utils.SetPageSizes(cfg.Pagination.DefaultSize, cfg.Pagination.MaxSize)
+		utils.SetFuzzyEnabled(cfg.Features.FuzzySearch)
+	})
+	reloader.OnReload(serv.Reload)
+
+	if err = reloader.Watch(ctx); err != nil {
+		log.Errorf("failed to watch config, it is not reloaded: %v", err)
+	}
+
+	return serv.Run()
+}
+
+// migrateUp applies every migration not applied yet
+func migrateUp(ctx context.Context, db *sqlx.DB) error {
+	migrator, err := migrate.NewMigrator(ctx, db)
+	if err != nil {
+		return err
+	}
+	defer migrator.Close()
+
+	return migrator.Up()
+}
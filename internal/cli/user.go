@@ -0,0 +1,70 @@
+package cli
+
+import (
+	"bufio"
+	"strings"
+
+	"github.com/realtemirov/task-for-dell/internal/models"
+	usersRepo "github.com/realtemirov/task-for-dell/internal/users/repository"
+	usersUseCase "github.com/realtemirov/task-for-dell/internal/users/usecase"
+	"github.com/spf13/cobra"
+)
+
+func newUserCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "user",
+		Short: "Manage the users of the admin",
+	}
+
+	cmd.AddCommand(newUserCreateCmd())
+
+	return cmd
+}
+
+// newUserCreateCmd creates a user, the password is read from stdin when --password is not set
+// so it does not end up in the shell history.
+//
+//	echo "$ADMIN_PASSWORD" | task-for-dell user create --username admin
+func newUserCreateCmd() *cobra.Command {
+
+	var user models.User
+
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create a user",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if user.Password == "" {
+				scanner := bufio.NewScanner(cmd.InOrStdin())
+				if scanner.Scan() {
+					user.Password = strings.TrimSpace(scanner.Text())
+				}
+				if err := scanner.Err(); err != nil {
+					return err
+				}
+			}
+
+			cfg, log, db, err := connect()
+			if err != nil {
+				return err
+			}
+			defer db.Close()
+
+			uc := usersUseCase.NewUsersUseCase(cfg, usersRepo.NewUsersRepository(db), log)
+			created, err := uc.Create(cmd.Context(), &user)
+			if err != nil {
+				return err
+			}
+
+			log.Infof("Created user, ID: %d, Username: %s, Role: %s", created.ID, created.Username, created.Role)
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&user.Username, "username", "", "username of the user")
+	cmd.Flags().StringVar(&user.Password, "password", "", "password of the user, read from stdin by default")
+	cmd.Flags().StringVar(&user.Role, "role", models.RoleAdmin, "role of the user: admin or editor")
+
+	return cmd
+}
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"strconv"
 	"time"
 )
 
@@ -9,18 +10,40 @@ type Blog struct {
 	Title     string    `json:"title" db:"title" validate:"required,gte=3" example:"this is title"`
 	Content   string    `json:"content" db:"content" validate:"required,gte=10" example:"this is content"`
 	CreatedAt time.Time `json:"created_at" db:"created_at" example:"2021-01-01T00:00:00Z"`
+	Locale    string    `json:"locale,omitempty" db:"-" example:"en"`
 }
 
 type BlogList struct {
-	TotalCount int     `json:"total_count" example:"100"`
-	TotalPage  int     `json:"total_page" example:"10"`
-	Page       int     `json:"page" example:"1"`
-	Limit      int     `json:"limit" example:"10"`
-	HasMore    bool    `json:"has_more" example:"true"`
-	Blogs      []*Blog `json:"blogs" example:[{id:1,title:this is title,content:this is content,created_at:2021-01-01T00:00:00Z}]`
+	TotalCount  int      `json:"total_count" example:"100"`
+	TotalPage   int      `json:"total_page" example:"10"`
+	Page        int      `json:"page" example:"1"`
+	Limit       int      `json:"limit" example:"10"`
+	HasMore     bool     `json:"has_more" example:"true"`
+	Blogs       []*Blog  `json:"blogs"`
+	Suggestions []string `json:"suggestions,omitempty" example:"this is title"`
 }
 
 type BlogSwagger struct {
 	Title   string `json:"title" validate:"required,gte=3,max=255" example:"this is title"`
 	Content string `json:"content" validate:"required,gte=10" example:"this is content"`
 }
+
+// CSVHeader returns the CSV column names of Blog
+func (b *Blog) CSVHeader() []string {
+	return []string{"id", "title", "content", "created_at"}
+}
+
+// CSVRecord returns the CSV columns of Blog
+func (b *Blog) CSVRecord() []string {
+	return []string{
+		strconv.FormatInt(b.ID, 10),
+		b.Title,
+		b.Content,
+		b.CreatedAt.Format(time.RFC3339Nano),
+	}
+}
+
+// UnmarshalCSV sets Blog from a CSV record, columns are matched by header name
+func (b *Blog) UnmarshalCSV(header, record []string) error {
+	return unmarshalContentCSV(header, record, &b.ID, &b.Title, &b.Content, &b.CreatedAt)
+}
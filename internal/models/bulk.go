@@ -0,0 +1,67 @@
+package models
+
+import "github.com/realtemirov/task-for-dell/pkg/utils"
+
+// Bulk operation kinds
+const (
+	BulkOpCreate = "create"
+	BulkOpUpdate = "update"
+	BulkOpDelete = "delete"
+)
+
+type BulkOperation struct {
+	Op      string `json:"op" validate:"required,oneof=create update delete" example:"create"`
+	ID      int64  `json:"id,omitempty" validate:"required_unless=Op create" example:"1"`
+	Title   string `json:"title,omitempty" example:"this is title"`
+	Content string `json:"content,omitempty" example:"this is content"`
+}
+
+type BulkResult struct {
+	Index  int                 `json:"index" example:"0"`
+	Op     string              `json:"op" example:"create"`
+	ID     int64               `json:"id,omitempty" example:"1"`
+	Status int                 `json:"status" example:"201"`
+	Error  string              `json:"error,omitempty" example:"VALIDATION_FAILED"`
+	Errors []*utils.FieldError `json:"errors,omitempty"`
+	Data   interface{}         `json:"data,omitempty"`
+	Err    error               `json:"-"`
+}
+
+type BulkResponse struct {
+	Mode      string        `json:"mode" example:"atomic"`
+	Total     int           `json:"total" example:"2"`
+	Succeeded int           `json:"succeeded" example:"1"`
+	Failed    int           `json:"failed" example:"1"`
+	Results   []*BulkResult `json:"results"`
+}
+
+// SuccessStatus returns the status code of the operation when it succeeded
+func (r *BulkResult) SuccessStatus() int {
+	switch r.Op {
+	case BulkOpCreate:
+		return 201
+	case BulkOpDelete:
+		return 204
+	default:
+		return 200
+	}
+}
+
+// NewBulkResponse summarizes results whose Status is already set
+func NewBulkResponse(mode string, results []*BulkResult) *BulkResponse {
+	response := &BulkResponse{
+		Mode:    mode,
+		Total:   len(results),
+		Results: results,
+	}
+
+	for _, result := range results {
+		if result.Err == nil {
+			response.Succeeded++
+		} else {
+			response.Failed++
+		}
+	}
+
+	return response
+}
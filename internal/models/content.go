@@ -0,0 +1,50 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Content types shared by blogs and news.
+const (
+	ContentTypeBlog = "blog"
+	ContentTypeNews = "news"
+)
+
+// unmarshalContentCSV sets the columns shared by blogs and news, unknown columns are ignored
+func unmarshalContentCSV(header, record []string, id *int64, title, content *string, createdAt *time.Time) error {
+	if len(record) != len(header) {
+		return fmt.Errorf("expected %d columns, got %d", len(header), len(record))
+	}
+
+	for i, column := range header {
+		value := record[i]
+		switch column {
+		case "id":
+			if value == "" {
+				continue
+			}
+			n, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid id %q", value)
+			}
+			*id = n
+		case "title":
+			*title = value
+		case "content":
+			*content = value
+		case "created_at":
+			if value == "" {
+				continue
+			}
+			t, err := time.Parse(time.RFC3339Nano, value)
+			if err != nil {
+				return fmt.Errorf("invalid created_at %q", value)
+			}
+			*createdAt = t
+		}
+	}
+
+	return nil
+}
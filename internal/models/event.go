@@ -0,0 +1,47 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Types of the domain events of content changes. Without drafts, contents are published
+// when they are created, so creates emit ContentCreated and ContentPublished.
+const (
+	EventContentCreated   = "content.created"
+	EventContentUpdated   = "content.updated"
+	EventContentDeleted   = "content.deleted"
+	EventContentPublished = "content.published"
+)
+
+// Event is a domain event of the outbox, delivered at least once: consumers skip the ids they have seen
+type Event struct {
+	ID          int64           `json:"id" db:"id" example:"1"`
+	Type        string          `json:"type" db:"event_type" example:"content.created"`
+	ContentType string          `json:"content_type" db:"content_type" example:"news"`
+	ContentID   int64           `json:"content_id" db:"content_id" example:"1"`
+	Payload     json.RawMessage `json:"payload" db:"payload" swaggertype:"object"`
+	CreatedAt   time.Time       `json:"created_at" db:"created_at" example:"2021-01-01T00:00:00Z"`
+	Attempts    int             `json:"-" db:"attempts"`
+}
+
+// NewContentEvent returns the event of a change of the content of id, payload is the content
+// after the change, or nil for deletes
+func NewContentEvent(eventType, contentType string, id int64, payload interface{}) (*Event, error) {
+	if payload == nil {
+		payload = map[string]int64{"id": id}
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Event{
+		Type:        eventType,
+		ContentType: contentType,
+		ContentID:   id,
+		Payload:     data,
+		CreatedAt:   time.Now(),
+	}, nil
+}
@@ -0,0 +1,97 @@
+package models
+
+import (
+	"context"
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/realtemirov/task-for-dell/pkg/utils"
+)
+
+type ImportError struct {
+	Line   int                 `json:"line" example:"3"`
+	Reason string              `json:"reason" example:"title is required"`
+	Errors []*utils.FieldError `json:"errors,omitempty"`
+	Raw    string              `json:"raw,omitempty" example:"{\"title\":\"\"}"`
+}
+
+type ImportReport struct {
+	Format   string         `json:"format" example:"ndjson"`
+	DryRun   bool           `json:"dry_run" example:"false"`
+	Total    int            `json:"total" example:"100"`
+	Accepted int            `json:"accepted" example:"99"`
+	Rejected int            `json:"rejected" example:"1"`
+	Errors   []*ImportError `json:"errors"`
+
+	// locale of the rejection reasons
+	locale string
+}
+
+// ImportBatchFunc writes a batch of an import and returns the rows it applied, with the row as data
+type ImportBatchFunc func(ctx context.Context) ([]*BulkResult, error)
+
+// ImportBatchWrapper runs the write of a batch of an import, e.g. in a transaction with the events of its rows
+type ImportBatchWrapper func(ctx context.Context, write ImportBatchFunc) error
+
+// importBatchKey holds the ImportBatchWrapper of a context
+type importBatchKey struct{}
+
+// WithImportBatchWrapper returns a context whose imports write every batch through wrap
+func WithImportBatchWrapper(ctx context.Context, wrap ImportBatchWrapper) context.Context {
+	return context.WithValue(ctx, importBatchKey{}, wrap)
+}
+
+// WriteImportBatch runs write through the ImportBatchWrapper of ctx, directly without one
+func WriteImportBatch(ctx context.Context, write ImportBatchFunc) error {
+	if wrap, ok := ctx.Value(importBatchKey{}).(ImportBatchWrapper); ok {
+		return wrap(ctx, write)
+	}
+
+	_, err := write(ctx)
+	return err
+}
+
+// NewImportReport constructs an empty ImportReport whose reasons are in the locale
+func NewImportReport(format string, dryRun bool, locale string) *ImportReport {
+	return &ImportReport{
+		Format: format,
+		DryRun: dryRun,
+		Errors: make([]*ImportError, 0),
+		locale: locale,
+	}
+}
+
+// Reject records a rejected line, with the failed fields of a validation error
+func (r *ImportReport) Reject(line int, err error, raw string) {
+	importErr := &ImportError{
+		Line:   line,
+		Reason: err.Error(),
+		Errors: utils.ValidationErrors(err, r.locale),
+		Raw:    raw,
+	}
+	if importErr.Errors != nil {
+		importErr.Reason = strings.Join(utils.ValidationMessages(err, r.locale), "; ")
+	}
+
+	r.Rejected++
+	r.Errors = append(r.Errors, importErr)
+}
+
+// WriteCSV writes the rejected lines with their reasons as CSV
+func (r *ImportReport) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"line", "reason", "raw"}); err != nil {
+		return err
+	}
+
+	for _, e := range r.Errors {
+		if err := writer.Write([]string{strconv.Itoa(e.Line), e.Reason, e.Raw}); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,12 @@
+package models
+
+type Suggestion struct {
+	Type  string `json:"type" db:"type" example:"blog"`
+	ID    int64  `json:"id" db:"id" example:"1"`
+	Title string `json:"title" db:"title" example:"this is title"`
+}
+
+type SuggestionList struct {
+	Query       string        `json:"query" example:"thi"`
+	Suggestions []*Suggestion `json:"suggestions"`
+}
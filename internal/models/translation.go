@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+type Translation struct {
+	ContentType string    `json:"content_type" db:"content_type" example:"blog"`
+	ContentID   int64     `json:"content_id" db:"content_id" example:"1"`
+	Locale      string    `json:"locale" db:"locale" validate:"required,oneof=en ru uz" example:"uz"`
+	Title       string    `json:"title" db:"title" validate:"required,gte=3,max=255" example:"bu sarlavha"`
+	Content     string    `json:"content" db:"content" validate:"required,gte=10" example:"bu kontent matni"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at" example:"2021-01-01T00:00:00Z"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at" example:"2021-01-01T00:00:00Z"`
+}
+
+type TranslationList struct {
+	Translations []*Translation `json:"translations"`
+}
+
+type TranslationSwagger struct {
+	Title   string `json:"title" validate:"required,gte=3,max=255" example:"bu sarlavha"`
+	Content string `json:"content" validate:"required,gte=10" example:"bu kontent matni"`
+}
+
+// contentTables are the tables of every content type
+var contentTables = map[string]string{
+	ContentTypeBlog: "blogs",
+	ContentTypeNews: "news",
+}
+
+// ContentTable returns the table of the content type, false for an unknown type
+func ContentTable(contentType string) (string, bool) {
+	table, ok := contentTables[contentType]
+	return table, ok
+}
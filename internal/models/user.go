@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// roles of users
+const (
+	RoleAdmin  = "admin"
+	RoleEditor = "editor"
+)
+
+type User struct {
+	ID           int64     `json:"id" db:"id" example:"1"`
+	Username     string    `json:"username" db:"username" validate:"required,alphanum,gte=3,max=64" example:"admin"`
+	Password     string    `json:"-" db:"-" validate:"required,gte=8,max=72"`
+	PasswordHash string    `json:"-" db:"password_hash"`
+	Role         string    `json:"role" db:"role" validate:"required,oneof=admin editor" example:"admin"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at" example:"2021-01-01T00:00:00Z"`
+}
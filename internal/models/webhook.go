@@ -0,0 +1,69 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// statuses of webhook deliveries. A failed attempt keeps the delivery pending until its next attempt,
+// it is dead once it failed every attempt, and is sent again when it is redelivered.
+const (
+	WebhookStatusPending   = "pending"
+	WebhookStatusDelivered = "delivered"
+	WebhookStatusDead      = "dead"
+)
+
+// WebhookSubscription posts the events of EventTypes, of ContentTypes or of every content type when empty,
+// to URL, signed with Secret. Secret is only returned when the subscription is created.
+type WebhookSubscription struct {
+	ID           int64     `json:"id" db:"id" example:"1"`
+	URL          string    `json:"url" db:"url" validate:"required,url,max=2048" example:"https://partner.example.com/webhooks"`
+	Secret       string    `json:"secret,omitempty" db:"secret" validate:"omitempty,gte=16,max=128" example:"5f2b8e0c9a7d4e1f8b3c6a9d2e5f8b1c"`
+	EventTypes   []string  `json:"event_types" db:"event_types" validate:"required,min=1,dive,oneof=content.created content.updated content.deleted content.published" example:"content.published"`
+	ContentTypes []string  `json:"content_types" db:"content_types" validate:"dive,oneof=blog news" example:"news"`
+	Paused       bool      `json:"paused" db:"paused" example:"false"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at" example:"2021-01-01T00:00:00Z"`
+	UpdatedAt    time.Time `json:"updated_at" db:"updated_at" example:"2021-01-01T00:00:00Z"`
+}
+
+type WebhookSubscriptionList struct {
+	Subscriptions []*WebhookSubscription `json:"subscriptions"`
+}
+
+type WebhookSubscriptionSwagger struct {
+	URL          string   `json:"url" validate:"required,url,max=2048" example:"https://partner.example.com/webhooks"`
+	Secret       string   `json:"secret,omitempty" validate:"omitempty,gte=16,max=128" example:"5f2b8e0c9a7d4e1f8b3c6a9d2e5f8b1c"`
+	EventTypes   []string `json:"event_types" validate:"required,min=1" example:"content.published"`
+	ContentTypes []string `json:"content_types,omitempty" example:"news"`
+	Paused       bool     `json:"paused,omitempty" example:"false"`
+}
+
+// WebhookDelivery is an event of a subscription to post, with the result of its last attempt
+type WebhookDelivery struct {
+	ID             int64           `json:"id" db:"id" example:"1"`
+	SubscriptionID int64           `json:"subscription_id" db:"subscription_id" example:"1"`
+	EventID        int64           `json:"event_id" db:"event_id" example:"1"`
+	EventType      string          `json:"event_type" db:"event_type" example:"content.published"`
+	Payload        json.RawMessage `json:"payload" db:"payload" swaggertype:"object"`
+	Status         string          `json:"status" db:"status" example:"delivered"`
+	Attempts       int             `json:"attempts" db:"attempts" example:"1"`
+	ResponseStatus int             `json:"response_status" db:"response_status" example:"200"`
+	LastError      string          `json:"last_error,omitempty" db:"last_error" example:"webhook answered 503 Service Unavailable"`
+	NextAttemptAt  time.Time       `json:"next_attempt_at" db:"next_attempt_at" example:"2021-01-01T00:00:00Z"`
+	CreatedAt      time.Time       `json:"created_at" db:"created_at" example:"2021-01-01T00:00:00Z"`
+	UpdatedAt      time.Time       `json:"updated_at" db:"updated_at" example:"2021-01-01T00:00:00Z"`
+	DeliveredAt    *time.Time      `json:"delivered_at,omitempty" db:"delivered_at" example:"2021-01-01T00:00:00Z"`
+
+	// URL and Secret of the subscription, read by the dispatcher only
+	URL    string `json:"-" db:"url"`
+	Secret string `json:"-" db:"secret"`
+}
+
+type WebhookDeliveryList struct {
+	TotalCount int                `json:"total_count" example:"100"`
+	TotalPage  int                `json:"total_page" example:"10"`
+	Page       int                `json:"page" example:"1"`
+	Limit      int                `json:"limit" example:"10"`
+	HasMore    bool               `json:"has_more" example:"true"`
+	Deliveries []*WebhookDelivery `json:"deliveries"`
+}
@@ -1,6 +1,9 @@
 package http
 
 import (
+	"context"
+	"database/sql"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -12,6 +15,7 @@ import (
 	"github.com/realtemirov/task-for-dell/internal/models"
 	"github.com/realtemirov/task-for-dell/internal/news/mock"
 	"github.com/realtemirov/task-for-dell/internal/news/usecase"
+	"github.com/realtemirov/task-for-dell/pkg/httpErrors"
 	"github.com/realtemirov/task-for-dell/pkg/logger"
 	"github.com/realtemirov/task-for-dell/pkg/utils"
 	"github.com/stretchr/testify/require"
@@ -31,8 +35,7 @@ func TestNewsHandlers_Create(t *testing.T) {
 	logger.InitLogger()
 
 	mockNewsUC := mock.NewMockUseCase(ctrl)
-	newsUC := usecase.NewNewsUseCase(cfg, mockNewsUC, logger)
-	newsHandler := NewNewsHandlers(cfg, newsUC, logger)
+	newsHandler := NewNewsHandlers(cfg, mockNewsUC, logger)
 	handler := newsHandler.Create()
 
 	t.Run("Create succes case", func(t *testing.T) {
@@ -77,6 +80,45 @@ func TestNewsHandlers_Create(t *testing.T) {
 		echoCtx := e.NewContext(request, response)
 		err = handler(echoCtx)
 		require.NoError(t, err)
+		require.Equal(t, http.StatusBadRequest, response.Code)
+		require.Equal(t, httpErrors.MIMEApplicationProblemJSON, response.Header().Get(echo.HeaderContentType))
+
+		// every failed field is reported by its json name
+		problem := httpErrors.Problem{}
+		require.NoError(t, json.Unmarshal(response.Body.Bytes(), &problem))
+		require.Equal(t, httpErrors.ValidationFailed, problem.Code)
+		require.Len(t, problem.Errors, 2)
+		require.Equal(t, "title", problem.Errors[0].Field)
+		require.Equal(t, "required", problem.Errors[0].Rule)
+		require.Equal(t, "content", problem.Errors[1].Field)
+	})
+
+	t.Run("Create Validate error ru case", func(t *testing.T) {
+		require.NoError(t, httpErrors.LoadMessages())
+
+		bufferData, err := utils.AnyToBytesBuffer(models.New{Title: "title-test"})
+		require.NoError(t, err)
+
+		request := httptest.NewRequest(http.MethodPost, "/v1/news", strings.NewReader(bufferData.String()))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		request = request.WithContext(utils.WithLocale(request.Context(), utils.ParseAcceptLanguage("ru-RU,ru;q=0.9,en;q=0.8")))
+		response := httptest.NewRecorder()
+
+		e := echo.New()
+		echoCtx := e.NewContext(request, response)
+		err = handler(echoCtx)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusBadRequest, response.Code)
+
+		// title, detail and field messages are in russian
+		problem := httpErrors.Problem{}
+		require.NoError(t, json.Unmarshal(response.Body.Bytes(), &problem))
+		require.Equal(t, "Ошибка проверки данных", problem.Title)
+		require.Equal(t, "1 поле не прошло проверку", problem.Detail)
+		require.Len(t, problem.Errors, 1)
+		require.Equal(t, "content", problem.Errors[0].Field)
+		require.Contains(t, problem.Errors[0].Message, "content")
+		require.NotContains(t, problem.Errors[0].Message, "required")
 	})
 }
 
@@ -93,8 +135,7 @@ func TestNewsHandlers_Update(t *testing.T) {
 	logger.InitLogger()
 
 	mockNewsUC := mock.NewMockUseCase(ctrl)
-	newsUC := usecase.NewNewsUseCase(cfg, mockNewsUC, logger)
-	newsHandler := NewNewsHandlers(cfg, newsUC, logger)
+	newsHandler := NewNewsHandlers(cfg, mockNewsUC, logger)
 	handler := newsHandler.Update()
 
 	t.Run("Update succes case", func(t *testing.T) {
@@ -185,8 +226,7 @@ func TestNewsHandlers_GetByID(t *testing.T) {
 	logger := logger.NewApiLogger(cfg)
 	logger.InitLogger()
 	mockNewsUC := mock.NewMockUseCase(ctrl)
-	newsUC := usecase.NewNewsUseCase(cfg, mockNewsUC, logger)
-	newsHandler := NewNewsHandlers(cfg, newsUC, logger)
+	newsHandler := NewNewsHandlers(cfg, mockNewsUC, logger)
 	handler := newsHandler.GetByID()
 
 	t.Run("GetByID succes case", func(t *testing.T) {
@@ -238,8 +278,7 @@ func TestNewsHandlers_Delete(t *testing.T) {
 	logger := logger.NewApiLogger(cfg)
 	logger.InitLogger()
 	mockNewsUC := mock.NewMockUseCase(ctrl)
-	newsUC := usecase.NewNewsUseCase(cfg, mockNewsUC, logger)
-	newsHandler := NewNewsHandlers(cfg, newsUC, logger)
+	newsHandler := NewNewsHandlers(cfg, mockNewsUC, logger)
 	handler := newsHandler.Delete()
 
 	t.Run("Delete succes case", func(t *testing.T) {
@@ -285,8 +324,7 @@ func TestNewsHandlers_GetAll(t *testing.T) {
 	logger := logger.NewApiLogger(cfg)
 	logger.InitLogger()
 	mockNewsUC := mock.NewMockUseCase(ctrl)
-	newsUC := usecase.NewNewsUseCase(cfg, mockNewsUC, logger)
-	newsHandler := NewNewsHandlers(cfg, newsUC, logger)
+	newsHandler := NewNewsHandlers(cfg, mockNewsUC, logger)
 	handler := newsHandler.GetAll()
 
 	t.Run("GetAll succes case", func(t *testing.T) {
@@ -341,3 +379,260 @@ func TestNewsHandlers_GetAll(t *testing.T) {
 		require.NoError(t, err)
 	})
 }
+
+func TestNewsHandlers_Bulk(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cfg, err := config.LoadConfig("./../../../../config/config-local")
+	require.NoError(t, err)
+
+	logger := logger.NewApiLogger(cfg)
+	logger.InitLogger()
+	mockNewsUC := mock.NewMockUseCase(ctrl)
+	newsHandler := NewNewsHandlers(cfg, mockNewsUC, logger)
+	handler := newsHandler.Bulk()
+
+	t.Run("Bulk succes case", func(t *testing.T) {
+		ops := []*models.BulkOperation{
+			{Op: models.BulkOpCreate, Title: "title-test", Content: "content-test"},
+			{Op: models.BulkOpDelete, ID: 2},
+		}
+
+		bufferData, err := utils.AnyToBytesBuffer(ops)
+		require.NoError(t, err)
+
+		request := httptest.NewRequest(http.MethodPost, "/v1/news/bulk", strings.NewReader(bufferData.String()))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		response := httptest.NewRecorder()
+
+		e := echo.New()
+		echoCtx := e.NewContext(request, response)
+
+		mockNewsUC.EXPECT().Bulk(gomock.Any(), gomock.Len(2), true).Return([]*models.BulkResult{
+			{Op: models.BulkOpCreate, ID: 1},
+			{Op: models.BulkOpDelete, ID: 2},
+		}, nil)
+
+		err = handler(echoCtx)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, response.Code)
+	})
+
+	t.Run("Bulk Mode error case", func(t *testing.T) {
+		request := httptest.NewRequest(http.MethodPost, "/v1/news/bulk?mode=test", strings.NewReader("[]"))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		response := httptest.NewRecorder()
+
+		e := echo.New()
+		echoCtx := e.NewContext(request, response)
+
+		err = handler(echoCtx)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusBadRequest, response.Code)
+	})
+
+	t.Run("Bulk Size error case", func(t *testing.T) {
+		request := httptest.NewRequest(http.MethodPost, "/v1/news/bulk", strings.NewReader("[]"))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		response := httptest.NewRecorder()
+
+		e := echo.New()
+		echoCtx := e.NewContext(request, response)
+
+		err = handler(echoCtx)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusBadRequest, response.Code)
+	})
+}
+
+// TestNewsHandlers_BulkUseCase runs the handler on the usecase, with a mock repository
+func TestNewsHandlers_BulkUseCase(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cfg, err := config.LoadConfig("./../../../../config/config-local")
+	require.NoError(t, err)
+
+	logger := logger.NewApiLogger(cfg)
+	logger.InitLogger()
+	mockNewsRepo := mock.NewMockRepository(ctrl)
+	newsHandler := NewNewsHandlers(cfg, usecase.NewNewsUseCase(cfg, mockNewsRepo, logger), logger)
+	handler := newsHandler.Bulk()
+
+	// invalid operations are rejected by the usecase, the others reach the repository
+	t.Run("Bulk partial failure case", func(t *testing.T) {
+		ops := []*models.BulkOperation{
+			{Op: models.BulkOpCreate, Title: "title-test", Content: "content-test"},
+			{Op: models.BulkOpCreate, Title: ""},
+		}
+
+		bufferData, err := utils.AnyToBytesBuffer(ops)
+		require.NoError(t, err)
+
+		request := httptest.NewRequest(http.MethodPost, "/v1/news/bulk?mode=best_effort", strings.NewReader(bufferData.String()))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		response := httptest.NewRecorder()
+
+		e := echo.New()
+		echoCtx := e.NewContext(request, response)
+
+		mockNewsRepo.EXPECT().Bulk(gomock.Any(), gomock.Len(1), false).Return([]*models.BulkResult{
+			{Op: models.BulkOpCreate, ID: 1},
+		}, nil)
+
+		err = handler(echoCtx)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusMultiStatus, response.Code)
+		require.Contains(t, response.Body.String(), `"status":400`)
+	})
+}
+
+func TestNewsHandlers_Export(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cfg, err := config.LoadConfig("./../../../../config/config-local")
+	require.NoError(t, err)
+
+	logger := logger.NewApiLogger(cfg)
+	logger.InitLogger()
+	mockNewsUC := mock.NewMockUseCase(ctrl)
+	newsHandler := NewNewsHandlers(cfg, mockNewsUC, logger)
+	handler := newsHandler.Export()
+
+	// export streams two rows through the callback
+	exportRows := func(ctx context.Context, query *utils.Query, fn func(new *models.New) error) error {
+		for i := int64(1); i <= 2; i++ {
+			if err := fn(&models.New{ID: i, Title: "title-test", Content: "content-test"}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	t.Run("Export NDJSON succes case", func(t *testing.T) {
+		request := httptest.NewRequest(http.MethodGet, "/v1/news/export?search=title", nil)
+		response := httptest.NewRecorder()
+
+		e := echo.New()
+		echoCtx := e.NewContext(request, response)
+
+		mockNewsUC.EXPECT().Export(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(exportRows)
+
+		err = handler(echoCtx)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, response.Code)
+		require.Equal(t, "application/x-ndjson", response.Header().Get(echo.HeaderContentType))
+		require.Len(t, strings.Split(strings.TrimSpace(response.Body.String()), "\n"), 2)
+	})
+
+	t.Run("Export CSV succes case", func(t *testing.T) {
+		request := httptest.NewRequest(http.MethodGet, "/v1/news/export?format=csv", nil)
+		response := httptest.NewRecorder()
+
+		e := echo.New()
+		echoCtx := e.NewContext(request, response)
+
+		mockNewsUC.EXPECT().Export(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(exportRows)
+
+		err = handler(echoCtx)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, response.Code)
+		require.True(t, strings.HasPrefix(response.Body.String(), "id,title,content,created_at\n"))
+		require.Len(t, strings.Split(strings.TrimSpace(response.Body.String()), "\n"), 3)
+	})
+
+	t.Run("Export Format error case", func(t *testing.T) {
+		request := httptest.NewRequest(http.MethodGet, "/v1/news/export?format=xml", nil)
+		response := httptest.NewRecorder()
+
+		e := echo.New()
+		echoCtx := e.NewContext(request, response)
+
+		err = handler(echoCtx)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusBadRequest, response.Code)
+	})
+
+	t.Run("Export UseCase error case", func(t *testing.T) {
+		request := httptest.NewRequest(http.MethodGet, "/v1/news/export", nil)
+		response := httptest.NewRecorder()
+
+		e := echo.New()
+		echoCtx := e.NewContext(request, response)
+
+		mockNewsUC.EXPECT().Export(gomock.Any(), gomock.Any(), gomock.Any()).Return(sql.ErrNoRows)
+
+		err = handler(echoCtx)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusNotFound, response.Code)
+	})
+}
+
+func TestNewsHandlers_Import(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cfg, err := config.LoadConfig("./../../../../config/config-local")
+	require.NoError(t, err)
+
+	logger := logger.NewApiLogger(cfg)
+	logger.InitLogger()
+	mockNewsUC := mock.NewMockRepository(ctrl)
+	newsUC := usecase.NewNewsUseCase(cfg, mockNewsUC, logger)
+	newsHandler := NewNewsHandlers(cfg, newsUC, logger)
+	handler := newsHandler.Import()
+
+	t.Run("Import CSV succes case", func(t *testing.T) {
+		body := "id,title,content\n1,title-test,content-test\n,title-test,content-test\n"
+		request := httptest.NewRequest(http.MethodPost, "/v1/news/import?format=csv", strings.NewReader(body))
+		request.Header.Set(echo.HeaderContentType, "text/csv")
+		response := httptest.NewRecorder()
+
+		e := echo.New()
+		echoCtx := e.NewContext(request, response)
+
+		mockNewsUC.EXPECT().Upsert(gomock.Any(), gomock.Len(2)).Return(nil, nil)
+
+		err = handler(echoCtx)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, response.Code)
+		require.Contains(t, response.Body.String(), `"accepted":2`)
+	})
+
+	t.Run("Import Rejected CSV report case", func(t *testing.T) {
+		body := "{\"title\":\"t\",\"content\":\"content-test\"}\n"
+		request := httptest.NewRequest(http.MethodPost, "/v1/news/import?dry_run=true&report=csv", strings.NewReader(body))
+		response := httptest.NewRecorder()
+
+		e := echo.New()
+		echoCtx := e.NewContext(request, response)
+
+		err = handler(echoCtx)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusMultiStatus, response.Code)
+		require.Equal(t, "text/csv; charset=utf-8", response.Header().Get(echo.HeaderContentType))
+		require.True(t, strings.HasPrefix(response.Body.String(), "line,reason,raw\n1,"))
+	})
+
+	t.Run("Import Dry Run error case", func(t *testing.T) {
+		request := httptest.NewRequest(http.MethodPost, "/v1/news/import?dry_run=maybe", strings.NewReader(""))
+		response := httptest.NewRecorder()
+
+		e := echo.New()
+		echoCtx := e.NewContext(request, response)
+
+		err = handler(echoCtx)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusBadRequest, response.Code)
+	})
+}
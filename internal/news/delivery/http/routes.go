@@ -2,14 +2,20 @@ package http
 
 import (
 	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/realtemirov/task-for-dell/config"
 	"github.com/realtemirov/task-for-dell/internal/news"
+	"github.com/realtemirov/task-for-dell/pkg/utils"
 )
 
 // MapNewsRoutes maps routes for newss
-func MapNewsRoutes(newsGroup *echo.Group, h news.Handlers) {
+func MapNewsRoutes(newsGroup *echo.Group, h news.Handlers, cfg *config.Config) {
 	newsGroup.POST("", h.Create())
+	newsGroup.POST("/bulk", h.Bulk())
+	newsGroup.POST("/import", h.Import(), middleware.BodyLimit(utils.IMPORT_BODY_LIMIT))
 	newsGroup.PUT("/:id", h.Update())
 	newsGroup.DELETE("/:id", h.Delete())
-	newsGroup.GET("/:id", h.GetByID())
-	newsGroup.GET("", h.GetAll())
+	newsGroup.GET("/export", h.Export())
+	newsGroup.GET("/:id", h.GetByID(), utils.ETag(cfg.Server.CacheControl.NewsItem))
+	newsGroup.GET("", h.GetAll(), utils.ETag(cfg.Server.CacheControl.NewsList))
 }
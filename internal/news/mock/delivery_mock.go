@@ -39,6 +39,20 @@ func (m *MockHandlers) EXPECT() *MockHandlersMockRecorder {
 	return m.recorder
 }
 
+// Bulk mocks base method.
+func (m *MockHandlers) Bulk() echo.HandlerFunc {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Bulk")
+	ret0, _ := ret[0].(echo.HandlerFunc)
+	return ret0
+}
+
+// Bulk indicates an expected call of Bulk.
+func (mr *MockHandlersMockRecorder) Bulk() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Bulk", reflect.TypeOf((*MockHandlers)(nil).Bulk))
+}
+
 // Create mocks base method.
 func (m *MockHandlers) Create() echo.HandlerFunc {
 	m.ctrl.T.Helper()
@@ -67,6 +81,20 @@ func (mr *MockHandlersMockRecorder) Delete() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockHandlers)(nil).Delete))
 }
 
+// Export mocks base method.
+func (m *MockHandlers) Export() echo.HandlerFunc {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Export")
+	ret0, _ := ret[0].(echo.HandlerFunc)
+	return ret0
+}
+
+// Export indicates an expected call of Export.
+func (mr *MockHandlersMockRecorder) Export() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Export", reflect.TypeOf((*MockHandlers)(nil).Export))
+}
+
 // GetAll mocks base method.
 func (m *MockHandlers) GetAll() echo.HandlerFunc {
 	m.ctrl.T.Helper()
@@ -95,6 +123,20 @@ func (mr *MockHandlersMockRecorder) GetByID() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockHandlers)(nil).GetByID))
 }
 
+// Import mocks base method.
+func (m *MockHandlers) Import() echo.HandlerFunc {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Import")
+	ret0, _ := ret[0].(echo.HandlerFunc)
+	return ret0
+}
+
+// Import indicates an expected call of Import.
+func (mr *MockHandlersMockRecorder) Import() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Import", reflect.TypeOf((*MockHandlers)(nil).Import))
+}
+
 // Update mocks base method.
 func (m *MockHandlers) Update() echo.HandlerFunc {
 	m.ctrl.T.Helper()
@@ -41,6 +41,21 @@ func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
 	return m.recorder
 }
 
+// Bulk mocks base method.
+func (m *MockRepository) Bulk(ctx context.Context, ops []*models.BulkOperation, atomic bool) ([]*models.BulkResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Bulk", ctx, ops, atomic)
+	ret0, _ := ret[0].([]*models.BulkResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Bulk indicates an expected call of Bulk.
+func (mr *MockRepositoryMockRecorder) Bulk(ctx, ops, atomic any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Bulk", reflect.TypeOf((*MockRepository)(nil).Bulk), ctx, ops, atomic)
+}
+
 // Create mocks base method.
 func (m *MockRepository) Create(ctx context.Context, news *models.New) (*models.New, error) {
 	m.ctrl.T.Helper()
@@ -70,6 +85,20 @@ func (mr *MockRepositoryMockRecorder) Delete(ctx, newsID any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockRepository)(nil).Delete), ctx, newsID)
 }
 
+// Export mocks base method.
+func (m *MockRepository) Export(ctx context.Context, query *utils.Query, fn func(*models.New) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Export", ctx, query, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Export indicates an expected call of Export.
+func (mr *MockRepositoryMockRecorder) Export(ctx, query, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Export", reflect.TypeOf((*MockRepository)(nil).Export), ctx, query, fn)
+}
+
 // GetAll mocks base method.
 func (m *MockRepository) GetAll(ctx context.Context, query *utils.Query) (*models.NewsList, error) {
 	m.ctrl.T.Helper()
@@ -114,3 +143,18 @@ func (mr *MockRepositoryMockRecorder) Update(ctx, news any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockRepository)(nil).Update), ctx, news)
 }
+
+// Upsert mocks base method.
+func (m *MockRepository) Upsert(ctx context.Context, newsList []*models.New) ([]*models.BulkResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Upsert", ctx, newsList)
+	ret0, _ := ret[0].([]*models.BulkResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Upsert indicates an expected call of Upsert.
+func (mr *MockRepositoryMockRecorder) Upsert(ctx, newsList any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upsert", reflect.TypeOf((*MockRepository)(nil).Upsert), ctx, newsList)
+}
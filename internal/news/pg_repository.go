@@ -13,4 +13,7 @@ type Repository interface {
 	Delete(ctx context.Context, newsID int64) error
 	GetByID(ctx context.Context, newsID int64) (*models.New, error)
 	GetAll(ctx context.Context, query *utils.Query) (*models.NewsList, error)
+	Bulk(ctx context.Context, ops []*models.BulkOperation, atomic bool) ([]*models.BulkResult, error)
+	Export(ctx context.Context, query *utils.Query, fn func(new *models.New) error) error
+	Upsert(ctx context.Context, newsList []*models.New) ([]*models.BulkResult, error)
 }
@@ -4,38 +4,55 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/pkg/errors"
 
 	"github.com/realtemirov/task-for-dell/internal/models"
 	"github.com/realtemirov/task-for-dell/internal/news"
+	"github.com/realtemirov/task-for-dell/pkg/db/postgres"
+	"github.com/realtemirov/task-for-dell/pkg/domainErrors"
+	"github.com/realtemirov/task-for-dell/pkg/metrics"
+	"github.com/realtemirov/task-for-dell/pkg/tracing"
 	"github.com/realtemirov/task-for-dell/pkg/utils"
 )
 
+// errBulkRollback rolls back an atomic batch whose failure is reported in its results
+var errBulkRollback = errors.New("bulk rollback")
+
 type newsRepo struct {
-	db *sqlx.DB
+	db *postgres.DB
 }
 
 // NewNewsRepository constructor
-func NewNewsRepository(db *sqlx.DB) news.Repository {
+func NewNewsRepository(db *postgres.DB) news.Repository {
 	return &newsRepo{db: db}
 }
 
 // Create implements news.Repository.
 func (r *newsRepo) Create(ctx context.Context, new *models.New) (*models.New, error) {
+	ctx, span := tracing.Start(ctx, "newsRepo.Create")
+	defer span.End()
+	defer metrics.ObserveQuery("news", "Create", time.Now())
+
+	return r.create(ctx, r.db.Writer(ctx), new)
+}
+
+// create inserts the news using db, which is either the pool or a transaction.
+func (r *newsRepo) create(ctx context.Context, db sqlx.ExtContext, new *models.New) (*models.New, error) {
 
 	// result for response
 	result := models.New{}
 
 	// insert entitiy and scan result
-	if err := r.db.QueryRowxContext(
+	if err := db.QueryRowxContext(
 		ctx,
 		createQuery,
 		&new.Title,
 		&new.Content,
 	).StructScan(&result); err != nil {
-		return nil, errors.Wrap(err, "newsRepo.Create.StructScan")
+		return nil, errors.Wrap(postgres.MapError(err), "newsRepo.Create.StructScan")
 	}
 
 	// if no error, return result
@@ -44,19 +61,28 @@ func (r *newsRepo) Create(ctx context.Context, new *models.New) (*models.New, er
 
 // Update implements news.Repository.
 func (r *newsRepo) Update(ctx context.Context, new *models.New) (*models.New, error) {
+	ctx, span := tracing.Start(ctx, "newsRepo.Update")
+	defer span.End()
+	defer metrics.ObserveQuery("news", "Update", time.Now())
+
+	return r.update(ctx, r.db.Writer(ctx), new)
+}
+
+// update updates the news using db, which is either the pool or a transaction.
+func (r *newsRepo) update(ctx context.Context, db sqlx.ExtContext, new *models.New) (*models.New, error) {
 
 	// response result
 	result := models.New{}
 
 	// update entity and scan result
-	if err := r.db.QueryRowxContext(
+	if err := db.QueryRowxContext(
 		ctx,
 		updateQuery,
 		&new.Title,
 		&new.Content,
 		&new.ID,
 	).StructScan(&result); err != nil {
-		return nil, errors.Wrap(err, "newsRepo.Update.StructScan")
+		return nil, errors.Wrap(postgres.MapError(err), "newsRepo.Update.StructScan")
 	}
 
 	// if no error, return result
@@ -65,21 +91,30 @@ func (r *newsRepo) Update(ctx context.Context, new *models.New) (*models.New, er
 
 // Delete implements news.Repository.
 func (r *newsRepo) Delete(ctx context.Context, newID int64) error {
+	ctx, span := tracing.Start(ctx, "newsRepo.Delete")
+	defer span.End()
+	defer metrics.ObserveQuery("news", "Delete", time.Now())
+
+	return r.delete(ctx, r.db.Writer(ctx), newID)
+}
+
+// delete deletes the news using db, which is either the pool or a transaction.
+func (r *newsRepo) delete(ctx context.Context, db sqlx.ExtContext, newID int64) error {
 
 	// delete entity and return result
-	result, err := r.db.ExecContext(ctx, deleteQuery, newID)
+	result, err := db.ExecContext(ctx, deleteQuery, newID)
 	if err != nil {
-		return errors.Wrap(err, "newsRepo.Delete.ExecContext")
+		return errors.Wrap(postgres.MapError(err), "newsRepo.Delete.ExecContext")
 	}
 
 	// if didn't rows affected, return error
 	rowsAffected, err := result.RowsAffected()
 	if err != nil {
-		return errors.Wrap(err, "newsRepo.Delete.RowsAffected")
+		return errors.Wrap(postgres.MapError(err), "newsRepo.Delete.RowsAffected")
 	}
 
 	if rowsAffected == 0 {
-		return errors.Wrap(sql.ErrNoRows, "newsRepo.Delete.RowsAffected")
+		return errors.Wrap(postgres.MapError(sql.ErrNoRows), "newsRepo.Delete.RowsAffected")
 	}
 
 	return nil
@@ -87,16 +122,19 @@ func (r *newsRepo) Delete(ctx context.Context, newID int64) error {
 
 // GetByID implements news.Repository.
 func (r *newsRepo) GetByID(ctx context.Context, newsID int64) (*models.New, error) {
+	ctx, span := tracing.Start(ctx, "newsRepo.GetByID")
+	defer span.End()
+	defer metrics.ObserveQuery("news", "GetByID", time.Now())
 
 	result := models.New{}
 
 	// get entity by id and scan result
-	if err := r.db.QueryRowxContext(
+	if err := r.db.Reader(ctx).QueryRowxContext(
 		ctx,
 		getByIDQuery,
 		newsID,
 	).StructScan(&result); err != nil {
-		return nil, errors.Wrap(err, "newsRepo.GetByID.StructScan")
+		return nil, errors.Wrap(postgres.MapError(err), "newsRepo.GetByID.StructScan")
 	}
 
 	// if no error, return result
@@ -105,6 +143,14 @@ func (r *newsRepo) GetByID(ctx context.Context, newsID int64) (*models.New, erro
 
 // GetAll implements news.Repository.
 func (r *newsRepo) GetAll(ctx context.Context, query *utils.Query) (*models.NewsList, error) {
+	ctx, span := tracing.Start(ctx, "newsRepo.GetAll")
+	defer span.End()
+	defer metrics.ObserveQuery("news", "GetAll", time.Now())
+
+	// fuzzy search ranks news by trigram similarity instead of exact LIKE
+	if query.Search != "" && query.Fuzzy {
+		return r.getAllFuzzy(ctx, query)
+	}
 
 	var (
 
@@ -112,50 +158,69 @@ func (r *newsRepo) GetAll(ctx context.Context, query *utils.Query) (*models.News
 		totalCount      int
 		totalCountQuery string = getTotalCountQuery
 		allNewsQuery    string = getAllQuery
+
+		// the arguments of both queries, the offset and limit follow them in the rows query
+		args []interface{}
 	)
 
 	// if search query is empty, get all news
 	if query.Search != "" {
 
-		// change query for get total count
-		totalCountQuery = fmt.Sprintf("%s%s", getTotalCountQuery, " AND title LIKE '%"+query.Search+"%' ")
+		// the search text is a parameter, its wildcards match literally
+		totalCountQuery += " AND title LIKE $1"
+		allNewsQuery += " AND title LIKE $1"
+		args = append(args, "%"+utils.EscapeLike(query.Search)+"%")
+	}
 
-		// change query for get all blogs
-		allNewsQuery = fmt.Sprintf(`%s %s`, getAllQuery, " AND title LIKE '%"+query.Search+"%' ")
+	// keep only news translated to the locale, it is one of the supported locales
+	if locale := query.GetAvailableLocale(); locale != "" {
+		totalCountQuery += fmt.Sprintf(availableLocaleFilter, locale)
+		allNewsQuery += fmt.Sprintf(availableLocaleFilter, locale)
 	}
 
 	// change query for get all news, sort by created_at, add offset and limit
-	allNewsQuery += fmt.Sprintf(" ORDER BY created_at %s OFFSET $1 LIMIT $2", query.GetSort())
+	allNewsQuery += fmt.Sprintf(" ORDER BY created_at %s OFFSET $%d LIMIT $%d", query.GetSort(), len(args)+1, len(args)+2)
+
+	// the count and the rows are read from one database, replicas may lag differently
+	reader := r.db.Reader(ctx)
 
 	// get total count and scan result
-	if err := r.db.QueryRowContext(
+	if err := reader.QueryRowContext(
 		ctx,
 		totalCountQuery,
+		args...,
 	).Scan(&totalCount); err != nil {
-		return nil, errors.Wrap(err, "newsRepo.GetAll.QueryRowContext.Scan")
+		return nil, errors.Wrap(postgres.MapError(err), "newsRepo.GetAll.QueryRowContext.Scan")
 	}
 
 	// if total count is 0, return empty list
 	if totalCount == 0 {
+
+		// offer "did you mean" titles when the search matched nothing
+		suggestions, err := r.getSuggestions(ctx, reader, query)
+		if err != nil {
+			return nil, err
+		}
+
 		return &models.NewsList{
-			TotalCount: totalCount,
-			TotalPage:  utils.GetTotalPages(totalCount, query.GetLimit()),
-			Page:       query.GetPage(),
-			Limit:      query.GetLimit(),
-			HasMore:    false,
-			News:       make([]*models.New, 0),
+			TotalCount:  totalCount,
+			TotalPage:   utils.GetTotalPages(totalCount, query.GetLimit()),
+			Page:        query.GetPage(),
+			Limit:       query.GetLimit(),
+			HasMore:     false,
+			News:        make([]*models.New, 0),
+			Suggestions: suggestions,
 		}, nil
 	}
 
 	// get all news
-	rows, err := r.db.QueryxContext(
+	rows, err := reader.QueryxContext(
 		ctx,
 		allNewsQuery,
-		query.GetOffset(),
-		query.GetLimit(),
+		append(args, query.GetOffset(), query.GetLimit())...,
 	)
 	if err != nil {
-		return nil, errors.Wrap(err, "newsRepo.GetAll.QueryxContext")
+		return nil, errors.Wrap(postgres.MapError(err), "newsRepo.GetAll.QueryxContext")
 	}
 	defer rows.Close()
 
@@ -166,7 +231,7 @@ func (r *newsRepo) GetAll(ctx context.Context, query *utils.Query) (*models.News
 	for rows.Next() {
 		news := models.New{}
 		if err := rows.StructScan(&news); err != nil {
-			return nil, errors.Wrap(err, "newsRepo.GetAll.StructScan")
+			return nil, errors.Wrap(postgres.MapError(err), "newsRepo.GetAll.StructScan")
 		}
 
 		newsList = append(newsList, &news)
@@ -174,7 +239,7 @@ func (r *newsRepo) GetAll(ctx context.Context, query *utils.Query) (*models.News
 
 	// if error, return error
 	if err = rows.Err(); err != nil {
-		return nil, errors.Wrap(err, "newsRepo.GetAll.rows.Err")
+		return nil, errors.Wrap(postgres.MapError(err), "newsRepo.GetAll.rows.Err")
 	}
 
 	// if no error, return result
@@ -187,3 +252,377 @@ func (r *newsRepo) GetAll(ctx context.Context, query *utils.Query) (*models.News
 		News:       newsList,
 	}, nil
 }
+
+// getAllFuzzy returns news whose title is similar to the search text, most similar first.
+func (r *newsRepo) getAllFuzzy(ctx context.Context, query *utils.Query) (*models.NewsList, error) {
+
+	var (
+		totalCount      int
+		suggestions     []string
+		newsList        = make([]*models.New, 0, query.GetLimit())
+		totalCountQuery = getFuzzyTotalCountQuery
+		allQuery        = getAllFuzzyQuery
+	)
+
+	// keep only news translated to the locale
+	if locale := query.GetAvailableLocale(); locale != "" {
+		totalCountQuery += fmt.Sprintf(availableLocaleFilter, locale)
+		allQuery += fmt.Sprintf(availableLocaleFilter, locale)
+	}
+
+	// sort by similarity then created_at, add offset and limit
+	allQuery += fmt.Sprintf(" ORDER BY similarity(title, $1) DESC, created_at %s OFFSET $2 LIMIT $3", query.GetSort())
+
+	err := postgres.WithSimilarityThreshold(ctx, r.db.Reader(ctx), query.GetThreshold(), func(tx postgres.Querier) error {
+
+		// get total count and scan result
+		if err := tx.QueryRowContext(
+			ctx,
+			totalCountQuery,
+			query.Search,
+		).Scan(&totalCount); err != nil {
+			return errors.Wrap(postgres.MapError(err), "newsRepo.getAllFuzzy.QueryRowContext.Scan")
+		}
+
+		// nothing is similar enough, offer "did you mean" titles instead
+		if totalCount == 0 {
+			var err error
+			suggestions, err = r.selectSuggestions(ctx, tx, query.Search)
+			return err
+		}
+
+		rows, err := tx.QueryxContext(
+			ctx,
+			allQuery,
+			query.Search,
+			query.GetOffset(),
+			query.GetLimit(),
+		)
+		if err != nil {
+			return errors.Wrap(postgres.MapError(err), "newsRepo.getAllFuzzy.QueryxContext")
+		}
+		defer rows.Close()
+
+		// scan rows
+		for rows.Next() {
+			news := models.New{}
+			if err := rows.StructScan(&news); err != nil {
+				return errors.Wrap(postgres.MapError(err), "newsRepo.getAllFuzzy.StructScan")
+			}
+
+			newsList = append(newsList, &news)
+		}
+
+		return errors.Wrap(postgres.MapError(rows.Err()), "newsRepo.getAllFuzzy.rows.Err")
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.NewsList{
+		TotalCount:  totalCount,
+		TotalPage:   utils.GetTotalPages(totalCount, query.GetLimit()),
+		Page:        query.GetPage(),
+		Limit:       query.GetLimit(),
+		HasMore:     utils.GetHasMore(query.GetPage(), totalCount, query.GetLimit()),
+		News:        newsList,
+		Suggestions: suggestions,
+	}, nil
+}
+
+// getSuggestions returns "did you mean" titles for a search that matched no news.
+func (r *newsRepo) getSuggestions(ctx context.Context, db postgres.Querier, query *utils.Query) ([]string, error) {
+
+	// nothing to suggest without search text
+	if query.Search == "" {
+		return nil, nil
+	}
+
+	var suggestions []string
+	err := postgres.WithSimilarityThreshold(ctx, db, query.GetThreshold(), func(tx postgres.Querier) error {
+		var err error
+		suggestions, err = r.selectSuggestions(ctx, tx, query.Search)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return suggestions, nil
+}
+
+// selectSuggestions selects the titles closest to the search text by word similarity.
+func (r *newsRepo) selectSuggestions(ctx context.Context, tx postgres.Querier, search string) ([]string, error) {
+
+	suggestions := make([]string, 0, utils.SUGGESTIONS_SIZE)
+	if err := tx.SelectContext(
+		ctx,
+		&suggestions,
+		getSuggestionsQuery,
+		search,
+		utils.SUGGESTIONS_SIZE,
+	); err != nil {
+		return nil, errors.Wrap(postgres.MapError(err), "newsRepo.selectSuggestions.SelectContext")
+	}
+
+	return suggestions, nil
+}
+
+// Bulk implements news.Repository.
+func (r *newsRepo) Bulk(ctx context.Context, ops []*models.BulkOperation, atomic bool) ([]*models.BulkResult, error) {
+	ctx, span := tracing.Start(ctx, "newsRepo.Bulk")
+	defer span.End()
+	defer metrics.ObserveQuery("news", "Bulk", time.Now())
+
+	// results in the same order as operations
+	results := make([]*models.BulkResult, len(ops))
+
+	// best effort, every operation is applied on its own, in a savepoint of the transaction of ctx
+	if !atomic {
+		for i, op := range ops {
+			err := r.db.WithinSavepoint(ctx, func(ctx context.Context) error {
+				results[i] = r.execBulkOperation(ctx, r.db.Writer(ctx), op)
+				return results[i].Err
+			})
+			if results[i] == nil {
+				results[i] = &models.BulkResult{Op: op.Op, ID: op.ID, Err: err}
+			}
+		}
+
+		return results, nil
+	}
+
+	// atomic, every operation is applied in one transaction, or a savepoint of the transaction of ctx
+	err := r.db.WithinTx(ctx, func(ctx context.Context) error {
+		for i, op := range ops {
+			results[i] = r.execBulkOperation(ctx, r.db.Writer(ctx), op)
+			if results[i].Err == nil {
+				continue
+			}
+
+			// one failure rolls back the whole batch
+			for j := range ops {
+				if j == i {
+					continue
+				}
+				results[j] = &models.BulkResult{
+					Op:  ops[j].Op,
+					ID:  ops[j].ID,
+					Err: utils.ErrBulkAborted,
+				}
+			}
+
+			return errBulkRollback
+		}
+
+		return nil
+	})
+	if err != nil && !errors.Is(err, errBulkRollback) {
+		return nil, errors.Wrap(err, "newsRepo.Bulk.WithinTx")
+	}
+
+	return results, nil
+}
+
+// execBulkOperation applies one bulk operation using db, which is either the pool or a transaction.
+func (r *newsRepo) execBulkOperation(ctx context.Context, db sqlx.ExtContext, op *models.BulkOperation) *models.BulkResult {
+
+	var (
+		err    error
+		new    *models.New
+		result = &models.BulkResult{Op: op.Op, ID: op.ID}
+	)
+
+	switch op.Op {
+	case models.BulkOpCreate:
+		new, err = r.create(ctx, db, &models.New{Title: op.Title, Content: op.Content})
+	case models.BulkOpUpdate:
+		new, err = r.update(ctx, db, &models.New{ID: op.ID, Title: op.Title, Content: op.Content})
+	case models.BulkOpDelete:
+		err = r.delete(ctx, db, op.ID)
+	default:
+		err = domainErrors.Invalid(fmt.Sprintf("unknown operation %q", op.Op), nil)
+	}
+
+	if err != nil {
+		result.Err = err
+		return result
+	}
+
+	if new != nil {
+		result.ID = new.ID
+		result.Data = new
+	}
+
+	return result
+}
+
+// Export implements news.Repository.
+func (r *newsRepo) Export(ctx context.Context, query *utils.Query, fn func(new *models.New) error) error {
+	ctx, span := tracing.Start(ctx, "newsRepo.Export")
+	defer span.End()
+	defer metrics.ObserveQuery("news", "Export", time.Now())
+
+	// fuzzy search streams news similar to the search text, most similar first
+	if query.Search != "" && query.Fuzzy {
+		return postgres.WithSimilarityThreshold(ctx, r.db.Reader(ctx), query.GetThreshold(), func(tx postgres.Querier) error {
+			exportQuery := getAllFuzzyQuery + fmt.Sprintf(" ORDER BY similarity(title, $1) DESC, created_at %s, id", query.GetSort())
+			return r.export(ctx, tx, fn, exportQuery, query.Search)
+		})
+	}
+
+	var (
+		exportQuery string = getAllQuery
+		args        []interface{}
+	)
+
+	// if search query is not empty, filter by title
+	if query.Search != "" {
+		exportQuery += " AND title LIKE $1"
+		args = append(args, "%"+utils.EscapeLike(query.Search)+"%")
+	}
+
+	// sort by created_at, without offset and limit
+	exportQuery += fmt.Sprintf(" ORDER BY created_at %s, id", query.GetSort())
+
+	return r.export(ctx, r.db.Reader(ctx), fn, exportQuery, args...)
+}
+
+// export scans rows of the query one by one and passes them to fn, rows are never held in memory.
+func (r *newsRepo) export(ctx context.Context, db sqlx.QueryerContext, fn func(new *models.New) error, query string, args ...interface{}) error {
+
+	rows, err := db.QueryxContext(ctx, query, args...)
+	if err != nil {
+		return errors.Wrap(postgres.MapError(err), "newsRepo.Export.QueryxContext")
+	}
+	defer rows.Close()
+
+	// scan rows
+	for rows.Next() {
+		new := models.New{}
+		if err := rows.StructScan(&new); err != nil {
+			return errors.Wrap(postgres.MapError(err), "newsRepo.Export.StructScan")
+		}
+
+		if err := fn(&new); err != nil {
+			return err
+		}
+	}
+
+	return errors.Wrap(postgres.MapError(rows.Err()), "newsRepo.Export.rows.Err")
+}
+
+// upsertedRow is a row written by Upsert, inserted or updated
+type upsertedRow struct {
+	models.New
+	Inserted bool `db:"inserted"`
+}
+
+// Upsert implements news.Repository, the result of every row written is a create or an update with the row as data.
+func (r *newsRepo) Upsert(ctx context.Context, newsList []*models.New) ([]*models.BulkResult, error) {
+	ctx, span := tracing.Start(ctx, "newsRepo.Upsert")
+	defer span.End()
+	defer metrics.ObserveQuery("news", "Upsert", time.Now())
+
+	var (
+		inserts    []interface{}
+		insertRows int
+		upserts    []interface{}
+		upsertRows int
+		dated      []interface{}
+		datedRows  int
+	)
+
+	// news with id are inserted or updated, the others are inserted
+	for _, new := range newsList {
+		createdAt := new.CreatedAt
+		if createdAt.IsZero() {
+			createdAt = time.Now()
+		}
+
+		// an existing row keeps its created_at unless one is given
+		if new.ID > 0 && !new.CreatedAt.IsZero() {
+			dated = append(dated, new.ID, new.Title, new.Content, createdAt)
+			datedRows++
+			continue
+		}
+		if new.ID > 0 {
+			upserts = append(upserts, new.ID, new.Title, new.Content, createdAt)
+			upsertRows++
+			continue
+		}
+
+		inserts = append(inserts, new.Title, new.Content, createdAt)
+		insertRows++
+	}
+
+	// one transaction, or a savepoint of the transaction of ctx
+	rows := make([]*upsertedRow, 0, len(newsList))
+	err := r.db.WithinTx(ctx, func(ctx context.Context) error {
+		tx := r.db.Writer(ctx)
+		rows = rows[:0]
+
+		// explicit ids first, then move the sequence so inserted ids do not collide
+		if datedRows > 0 {
+			if err := sqlx.SelectContext(
+				ctx,
+				tx,
+				&rows,
+				fmt.Sprintf(upsertManyQuery, postgres.Placeholders(datedRows, 4))+upsertCreatedAtQuery+returningUpsertedQuery,
+				dated...,
+			); err != nil {
+				return errors.Wrap(postgres.MapError(err), "newsRepo.Upsert.SelectContext")
+			}
+		}
+
+		if upsertRows > 0 {
+			upserted := make([]*upsertedRow, 0, upsertRows)
+			if err := sqlx.SelectContext(
+				ctx,
+				tx,
+				&upserted,
+				fmt.Sprintf(upsertManyQuery, postgres.Placeholders(upsertRows, 4))+returningUpsertedQuery,
+				upserts...,
+			); err != nil {
+				return errors.Wrap(postgres.MapError(err), "newsRepo.Upsert.SelectContext")
+			}
+			rows = append(rows, upserted...)
+		}
+
+		if datedRows+upsertRows > 0 {
+			if _, err := tx.ExecContext(ctx, syncIDSequenceQuery); err != nil {
+				return errors.Wrap(postgres.MapError(err), "newsRepo.Upsert.syncIDSequence")
+			}
+		}
+
+		if insertRows > 0 {
+			inserted := make([]*upsertedRow, 0, insertRows)
+			if err := sqlx.SelectContext(
+				ctx,
+				tx,
+				&inserted,
+				insertManyQuery+postgres.Placeholders(insertRows, 3)+returningUpsertedQuery,
+				inserts...,
+			); err != nil {
+				return errors.Wrap(postgres.MapError(err), "newsRepo.Upsert.insertMany")
+			}
+			rows = append(rows, inserted...)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*models.BulkResult, 0, len(rows))
+	for _, row := range rows {
+		result := &models.BulkResult{Op: models.BulkOpUpdate, ID: row.ID, Data: &row.New}
+		if row.Inserted {
+			result.Op = models.BulkOpCreate
+		}
+		results = append(results, result)
+	}
+
+	return results, nil
+}
@@ -2,12 +2,17 @@ package repository
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jackc/pgx"
 	"github.com/jmoiron/sqlx"
 	"github.com/realtemirov/task-for-dell/internal/models"
+	"github.com/realtemirov/task-for-dell/pkg/db/postgres"
+	"github.com/realtemirov/task-for-dell/pkg/domainErrors"
 	"github.com/realtemirov/task-for-dell/pkg/utils"
 	"github.com/stretchr/testify/require"
 )
@@ -26,7 +31,7 @@ func TestNewRepo_Create(t *testing.T) {
 	defer sqlxDB.Close()
 
 	// new's repository
-	repo := NewNewsRepository(sqlxDB)
+	repo := NewNewsRepository(postgres.NewDB(sqlxDB))
 
 	// Create new success case
 	t.Run("Create", func(t *testing.T) {
@@ -104,7 +109,7 @@ func TestNewRepo_Update(t *testing.T) {
 	defer sqlxDB.Close()
 
 	// new repository
-	repo := NewNewsRepository(sqlxDB)
+	repo := NewNewsRepository(postgres.NewDB(sqlxDB))
 
 	// Update new success case
 	t.Run("Update", func(t *testing.T) {
@@ -183,7 +188,7 @@ func TestNewRepo_Delete(t *testing.T) {
 	defer sqlxDB.Close()
 
 	// new repository
-	repo := NewNewsRepository(sqlxDB)
+	repo := NewNewsRepository(postgres.NewDB(sqlxDB))
 
 	// Delete newNew success case
 	t.Run("Delete", func(t *testing.T) {
@@ -272,7 +277,7 @@ func TestNewRepo_GetByID(t *testing.T) {
 	defer sqlxDB.Close()
 
 	// new repository
-	repo := NewNewsRepository(sqlxDB)
+	repo := NewNewsRepository(postgres.NewDB(sqlxDB))
 
 	// GetByID success case
 	t.Run("GetByID", func(t *testing.T) {
@@ -339,7 +344,7 @@ func TestNewRepo_GetAll(t *testing.T) {
 	defer sqlxDB.Close()
 
 	// new repository
-	repo := NewNewsRepository(sqlxDB)
+	repo := NewNewsRepository(postgres.NewDB(sqlxDB))
 
 	// GetAll success case, without search
 	t.Run("GetAll", func(t *testing.T) {
@@ -415,7 +420,9 @@ func TestNewRepo_GetAll(t *testing.T) {
 
 		// mock query for get total count, with search
 		mock.ExpectQuery(
-			fmt.Sprintf("%s%s", getTotalCountQuery, " AND title LIKE '%"+query.Search+"%' "),
+			getTotalCountQuery+" AND title LIKE $1",
+		).WithArgs(
+			"%"+query.Search+"%",
 		).WillReturnRows(
 			sqlmock.NewRows([]string{"count"}).AddRow(2),
 		)
@@ -423,9 +430,10 @@ func TestNewRepo_GetAll(t *testing.T) {
 		// mock query with args and return rows, with search
 		mock.ExpectQuery(
 			fmt.Sprintf(
-				"%s ORDER BY created_at %s OFFSET $1 LIMIT $2",
-				fmt.Sprintf(`%s%s`, getAllQuery, " AND title LIKE '%"+query.Search+"%' "), query.GetSort()),
+				"%s AND title LIKE $1 ORDER BY created_at %s OFFSET $2 LIMIT $3",
+				getAllQuery, query.GetSort()),
 		).WithArgs(
+			"%"+query.Search+"%",
 			query.GetOffset(),
 			query.GetLimit(),
 		).WillReturnRows(rows)
@@ -444,6 +452,93 @@ func TestNewRepo_GetAll(t *testing.T) {
 		require.Len(t, News.News, 2)
 	})
 
+	// GetAll search text is a parameter, quotes and wildcards match literally
+	t.Run("GetAll Search Escaped", func(t *testing.T) {
+
+		// mock rows
+		rows := sqlmock.NewRows(
+			[]string{"id", "title", "content"},
+		).AddRow(
+			int64(1),
+			"50%_off' OR '1'='1",
+			"test-content",
+		)
+
+		// mock query for get total count, with the escaped search
+		mock.ExpectQuery(
+			getTotalCountQuery+" AND title LIKE $1",
+		).WithArgs(
+			`%50\%\_off' OR '1'='1%`,
+		).WillReturnRows(
+			sqlmock.NewRows([]string{"count"}).AddRow(1),
+		)
+
+		// mock query with args and return rows, with the escaped search
+		mock.ExpectQuery(
+			getAllQuery+" AND title LIKE $1 ORDER BY created_at ASC OFFSET $2 LIMIT $3",
+		).WithArgs(
+			`%50\%\_off' OR '1'='1%`,
+			0,
+			10,
+		).WillReturnRows(rows)
+
+		// call GetAll method
+		result, err := repo.GetAll(context.Background(), &utils.Query{
+			Limit:  10,
+			Page:   1,
+			Search: "50%_off' OR '1'='1",
+		})
+
+		// check error and result
+		require.NoError(t, err)
+		require.Len(t, result.News, 1)
+	})
+
+	// GetAll success case, only translated to a locale
+	t.Run("GetAll AvailableLocale", func(t *testing.T) {
+
+		// mock rows
+		rows := sqlmock.NewRows(
+			[]string{"id", "title", "content"},
+		).AddRow(
+			int64(1),
+			"test-title",
+			"test-content",
+		)
+
+		// mock query
+		query := utils.Query{
+			Limit:           10,
+			Page:            1,
+			AvailableLocale: utils.LOCALE_RU,
+		}
+
+		// mock query for get total count, with locale filter
+		mock.ExpectQuery(
+			getTotalCountQuery + fmt.Sprintf(availableLocaleFilter, utils.LOCALE_RU),
+		).WillReturnRows(
+			sqlmock.NewRows([]string{"count"}).AddRow(1),
+		)
+
+		// mock query with args and return rows, with locale filter
+		mock.ExpectQuery(
+			fmt.Sprintf(
+				"%s ORDER BY created_at %s OFFSET $1 LIMIT $2",
+				getAllQuery+fmt.Sprintf(availableLocaleFilter, utils.LOCALE_RU), query.GetSort()),
+		).WithArgs(
+			query.GetOffset(),
+			query.GetLimit(),
+		).WillReturnRows(rows)
+
+		// call GetAll method
+		news, err := repo.GetAll(context.Background(), &query)
+
+		// check error and result
+		require.NoError(t, err)
+		require.NotNil(t, news)
+		require.Len(t, news.News, 1)
+	})
+
 	// GetAll TotalCount equal to zero case
 	t.Run("GetAll TotalCount equal to zero", func(t *testing.T) {
 
@@ -614,3 +709,574 @@ func TestNewRepo_GetAll(t *testing.T) {
 
 	})
 }
+
+// TestNewRepo_GetAllFuzzy tests GetAll method with fuzzy search and suggestions.
+func TestNewRepo_GetAllFuzzy(t *testing.T) {
+	t.Parallel()
+
+	// create mock db
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer db.Close()
+
+	// create sqlx db with mock db
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	defer sqlxDB.Close()
+
+	// new repository
+	repo := NewNewsRepository(postgres.NewDB(sqlxDB))
+
+	// GetAll fuzzy success case
+	t.Run("GetAll Fuzzy", func(t *testing.T) {
+
+		// mock rows
+		rows := sqlmock.NewRows(
+			[]string{"id", "title", "content"},
+		).AddRow(
+			int64(1),
+			"government",
+			"test-content",
+		)
+
+		// mock query
+		query := utils.Query{
+			Limit:     10,
+			Page:      1,
+			Search:    "goverment",
+			Fuzzy:     true,
+			Threshold: 0.4,
+		}
+
+		// mock transaction with similarity threshold
+		mock.ExpectBegin()
+		mock.ExpectExec(postgres.SetSimilarityThresholdQuery).
+			WithArgs("0.4").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		// mock query for get total count, with fuzzy search
+		mock.ExpectQuery(getFuzzyTotalCountQuery).
+			WithArgs(query.Search).
+			WillReturnRows(
+				sqlmock.NewRows([]string{"count"}).AddRow(1),
+			)
+
+		// mock query with args and return rows, with fuzzy search
+		mock.ExpectQuery(
+			fmt.Sprintf("%s ORDER BY similarity(title, $1) DESC, created_at %s OFFSET $2 LIMIT $3", getAllFuzzyQuery, query.GetSort()),
+		).WithArgs(
+			query.Search,
+			query.GetOffset(),
+			query.GetLimit(),
+		).WillReturnRows(rows)
+		mock.ExpectCommit()
+
+		// call GetAll method
+		news, err := repo.GetAll(context.Background(), &query)
+
+		// check error and result
+		require.NoError(t, err)
+		require.NotNil(t, news)
+		require.Len(t, news.News, 1)
+		require.Empty(t, news.Suggestions)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	// GetAll fuzzy without matches returns suggestions
+	t.Run("GetAll Fuzzy Suggestions", func(t *testing.T) {
+
+		// mock query
+		query := utils.Query{
+			Limit:  10,
+			Page:   1,
+			Search: "goverment",
+			Fuzzy:  true,
+		}
+
+		// mock transaction with default similarity threshold
+		mock.ExpectBegin()
+		mock.ExpectExec(postgres.SetSimilarityThresholdQuery).
+			WithArgs("0.3").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		// mock query for get total count zero
+		mock.ExpectQuery(getFuzzyTotalCountQuery).
+			WithArgs(query.Search).
+			WillReturnRows(
+				sqlmock.NewRows([]string{"count"}).AddRow(0),
+			)
+
+		// mock query for suggestions
+		mock.ExpectQuery(getSuggestionsQuery).
+			WithArgs(query.Search, utils.SUGGESTIONS_SIZE).
+			WillReturnRows(
+				sqlmock.NewRows([]string{"title"}).AddRow("new government policy"),
+			)
+		mock.ExpectCommit()
+
+		// call GetAll method
+		news, err := repo.GetAll(context.Background(), &query)
+
+		// check error and result
+		require.NoError(t, err)
+		require.NotNil(t, news)
+		require.Len(t, news.News, 0)
+		require.Equal(t, []string{"new government policy"}, news.Suggestions)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	// GetAll exact search without matches returns suggestions
+	t.Run("GetAll Search Suggestions", func(t *testing.T) {
+
+		// mock query
+		query := utils.Query{
+			Limit:  10,
+			Page:   1,
+			Search: "goverment",
+		}
+
+		// mock query for get total count zero, with search
+		mock.ExpectQuery(
+			getTotalCountQuery+" AND title LIKE $1",
+		).WithArgs(
+			"%"+query.Search+"%",
+		).WillReturnRows(
+			sqlmock.NewRows([]string{"count"}).AddRow(0),
+		)
+
+		// mock transaction and query for suggestions
+		mock.ExpectBegin()
+		mock.ExpectExec(postgres.SetSimilarityThresholdQuery).
+			WithArgs("0.3").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(getSuggestionsQuery).
+			WithArgs(query.Search, utils.SUGGESTIONS_SIZE).
+			WillReturnRows(
+				sqlmock.NewRows([]string{"title"}).AddRow("new government policy"),
+			)
+		mock.ExpectCommit()
+
+		// call GetAll method
+		news, err := repo.GetAll(context.Background(), &query)
+
+		// check error and result
+		require.NoError(t, err)
+		require.NotNil(t, news)
+		require.Equal(t, []string{"new government policy"}, news.Suggestions)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	// GetAll fuzzy TotalCount error case
+	t.Run("GetAll Fuzzy TotalCount Error", func(t *testing.T) {
+
+		// mock query
+		query := utils.Query{
+			Limit:  10,
+			Page:   1,
+			Search: "goverment",
+			Fuzzy:  true,
+		}
+
+		// mock transaction rolled back on error
+		mock.ExpectBegin()
+		mock.ExpectExec(postgres.SetSimilarityThresholdQuery).
+			WithArgs("0.3").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(getFuzzyTotalCountQuery).
+			WithArgs(query.Search).
+			WillReturnError(sqlmock.ErrCancelled)
+		mock.ExpectRollback()
+
+		// call GetAll method
+		news, err := repo.GetAll(context.Background(), &query)
+
+		// check error and result
+		require.Error(t, err)
+		require.Nil(t, news)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+// TestNewRepo_Bulk tests Bulk method.
+func TestNewRepo_Bulk(t *testing.T) {
+	t.Parallel()
+
+	// create mock db
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer db.Close()
+
+	// create sqlx db with mock db
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	defer sqlxDB.Close()
+
+	// new repository
+	repo := NewNewsRepository(postgres.NewDB(sqlxDB))
+
+	// operations of the batch
+	ops := []*models.BulkOperation{
+		{Op: models.BulkOpCreate, Title: "test-title", Content: "test-content"},
+		{Op: models.BulkOpDelete, ID: 2},
+	}
+
+	// Bulk atomic success case
+	t.Run("Bulk Atomic", func(t *testing.T) {
+
+		// mock transaction with create and delete
+		mock.ExpectBegin()
+		mock.ExpectQuery(createQuery).WithArgs(
+			"test-title",
+			"test-content",
+		).WillReturnRows(
+			sqlmock.NewRows([]string{"id", "title", "content"}).AddRow(int64(1), "test-title", "test-content"),
+		)
+		mock.ExpectExec(deleteQuery).WithArgs(int64(2)).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		// call Bulk method
+		results, err := repo.Bulk(context.Background(), ops, true)
+
+		// check error and result
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+		require.NoError(t, results[0].Err)
+		require.Equal(t, int64(1), results[0].ID)
+		require.NoError(t, results[1].Err)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	// Bulk atomic failure rolls back every operation
+	t.Run("Bulk Atomic Rollback", func(t *testing.T) {
+
+		// mock transaction with failed delete
+		mock.ExpectBegin()
+		mock.ExpectQuery(createQuery).WithArgs(
+			"test-title",
+			"test-content",
+		).WillReturnRows(
+			sqlmock.NewRows([]string{"id", "title", "content"}).AddRow(int64(1), "test-title", "test-content"),
+		)
+		mock.ExpectExec(deleteQuery).WithArgs(int64(2)).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		// call Bulk method
+		results, err := repo.Bulk(context.Background(), ops, true)
+
+		// check error and result
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+		require.ErrorIs(t, results[0].Err, utils.ErrBulkAborted)
+		require.ErrorIs(t, results[1].Err, sql.ErrNoRows)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	// Bulk best effort applies every operation on its own
+	t.Run("Bulk Best Effort", func(t *testing.T) {
+
+		// mock failed create and successful delete, without transaction
+		mock.ExpectQuery(createQuery).WithArgs(
+			"test-title",
+			"test-content",
+		).WillReturnError(sqlmock.ErrCancelled)
+		mock.ExpectExec(deleteQuery).WithArgs(int64(2)).WillReturnResult(sqlmock.NewResult(0, 1))
+
+		// call Bulk method
+		results, err := repo.Bulk(context.Background(), ops, false)
+
+		// check error and result
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+		require.Error(t, results[0].Err)
+		require.NoError(t, results[1].Err)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	// Bulk begin error case
+	t.Run("Bulk Begin Error", func(t *testing.T) {
+
+		// mock begin error
+		mock.ExpectBegin().WillReturnError(sqlmock.ErrCancelled)
+
+		// call Bulk method
+		results, err := repo.Bulk(context.Background(), ops, true)
+
+		// check error and result
+		require.Error(t, err)
+		require.Nil(t, results)
+	})
+}
+
+// TestNewRepo_Export tests Export method.
+func TestNewRepo_Export(t *testing.T) {
+	t.Parallel()
+
+	// create mock db
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer db.Close()
+
+	// create sqlx db with mock db
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	defer sqlxDB.Close()
+
+	// new repository
+	repo := NewNewsRepository(postgres.NewDB(sqlxDB))
+
+	// Export with search success case
+	t.Run("Export Search", func(t *testing.T) {
+
+		// mock rows
+		rows := sqlmock.NewRows(
+			[]string{"id", "title", "content"},
+		).AddRow(
+			int64(1),
+			"test-title",
+			"test-content",
+		).AddRow(
+			int64(2),
+			"test-title",
+			"test-content",
+		)
+
+		// mock query with escaped search and no pagination
+		mock.ExpectQuery(
+			getAllQuery + " AND title LIKE $1 ORDER BY created_at DESC, id",
+		).WithArgs(
+			`%100\%%`,
+		).WillReturnRows(rows)
+
+		// call Export method
+		exported := make([]*models.New, 0)
+		err := repo.Export(context.Background(), &utils.Query{Search: "100%", Sort: "desc"}, func(new *models.New) error {
+			exported = append(exported, new)
+			return nil
+		})
+
+		// check error and result
+		require.NoError(t, err)
+		require.Len(t, exported, 2)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	// Export fuzzy success case
+	t.Run("Export Fuzzy", func(t *testing.T) {
+
+		// mock transaction with similarity threshold
+		mock.ExpectBegin()
+		mock.ExpectExec(postgres.SetSimilarityThresholdQuery).
+			WithArgs("0.3").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(
+			getAllFuzzyQuery+" ORDER BY similarity(title, $1) DESC, created_at ASC, id",
+		).WithArgs(
+			"goverment",
+		).WillReturnRows(
+			sqlmock.NewRows([]string{"id", "title", "content"}).AddRow(int64(1), "government", "test-content"),
+		)
+		mock.ExpectCommit()
+
+		// call Export method
+		count := 0
+		err := repo.Export(context.Background(), &utils.Query{Search: "goverment", Fuzzy: true}, func(new *models.New) error {
+			count++
+			return nil
+		})
+
+		// check error and result
+		require.NoError(t, err)
+		require.Equal(t, 1, count)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	// Export stops when fn fails
+	t.Run("Export fn Error", func(t *testing.T) {
+
+		// mock rows
+		rows := sqlmock.NewRows(
+			[]string{"id", "title", "content"},
+		).AddRow(
+			int64(1),
+			"test-title",
+			"test-content",
+		).AddRow(
+			int64(2),
+			"test-title",
+			"test-content",
+		)
+
+		// mock query without search
+		mock.ExpectQuery(getAllQuery + " ORDER BY created_at ASC, id").WillReturnRows(rows)
+
+		// call Export method
+		count := 0
+		err := repo.Export(context.Background(), &utils.Query{}, func(new *models.New) error {
+			count++
+			return fmt.Errorf("client gone")
+		})
+
+		// check error and result
+		require.Error(t, err)
+		require.Equal(t, 1, count)
+	})
+
+	// Export query error case
+	t.Run("Export Error", func(t *testing.T) {
+
+		// mock query and return error
+		mock.ExpectQuery(getAllQuery + " ORDER BY created_at ASC, id").WillReturnError(sqlmock.ErrCancelled)
+
+		// call Export method
+		err := repo.Export(context.Background(), &utils.Query{}, func(new *models.New) error {
+			return nil
+		})
+
+		// check error
+		require.Error(t, err)
+	})
+}
+
+// TestNewRepo_Upsert tests Upsert method.
+func TestNewRepo_Upsert(t *testing.T) {
+	t.Parallel()
+
+	// create mock db
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer db.Close()
+
+	// create sqlx db with mock db
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	defer sqlxDB.Close()
+
+	// new repository
+	repo := NewNewsRepository(postgres.NewDB(sqlxDB))
+
+	// one new with id, one without
+	createdAt := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	items := []*models.New{
+		{ID: 5, Title: "test-title", Content: "test-content", CreatedAt: createdAt},
+		{Title: "test-title", Content: "test-content", CreatedAt: createdAt},
+	}
+
+	// Upsert success case
+	t.Run("Upsert", func(t *testing.T) {
+
+		// mock transaction with upsert, sequence sync and insert, returning the rows written
+		mock.ExpectBegin()
+		mock.ExpectQuery(
+			fmt.Sprintf(upsertManyQuery, postgres.Placeholders(1, 4))+upsertCreatedAtQuery+returningUpsertedQuery,
+		).WithArgs(
+			int64(5),
+			"test-title",
+			"test-content",
+			createdAt,
+		).WillReturnRows(sqlmock.NewRows(
+			[]string{"id", "title", "content", "created_at", "inserted"},
+		).AddRow(int64(5), "test-title", "test-content", createdAt, false))
+		mock.ExpectExec(syncIDSequenceQuery).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(
+			insertManyQuery+postgres.Placeholders(1, 3)+returningUpsertedQuery,
+		).WithArgs(
+			"test-title",
+			"test-content",
+			createdAt,
+		).WillReturnRows(sqlmock.NewRows(
+			[]string{"id", "title", "content", "created_at", "inserted"},
+		).AddRow(int64(6), "test-title", "test-content", createdAt, true))
+		mock.ExpectCommit()
+
+		// call Upsert method
+		results, err := repo.Upsert(context.Background(), items)
+
+		// check error and the rows written
+		require.NoError(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+		require.Len(t, results, 2)
+		require.Equal(t, models.BulkOpUpdate, results[0].Op)
+		require.Equal(t, int64(5), results[0].ID)
+		require.Equal(t, models.BulkOpCreate, results[1].Op)
+		require.Equal(t, int64(6), results[1].ID)
+		require.Equal(t, "test-title", results[1].Data.(*models.New).Title)
+	})
+
+	// Upsert of an existing id without created_at keeps the date of the row, as imports of records without one
+	t.Run("Upsert existing id without created_at", func(t *testing.T) {
+
+		// mock upsert not updating created_at
+		mock.ExpectBegin()
+		mock.ExpectQuery(
+			fmt.Sprintf(upsertManyQuery, postgres.Placeholders(1, 4))+returningUpsertedQuery,
+		).WithArgs(
+			int64(7),
+			"test-title",
+			"test-content",
+			sqlmock.AnyArg(),
+		).WillReturnRows(sqlmock.NewRows(
+			[]string{"id", "title", "content", "created_at", "inserted"},
+		).AddRow(int64(7), "test-title", "test-content", createdAt, false))
+		mock.ExpectExec(syncIDSequenceQuery).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		// call Upsert method
+		results, err := repo.Upsert(context.Background(), []*models.New{{ID: 7, Title: "test-title", Content: "test-content"}})
+
+		// check the date of the row is kept
+		require.NoError(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+		require.Len(t, results, 1)
+		require.Equal(t, models.BulkOpUpdate, results[0].Op)
+		require.Equal(t, createdAt, results[0].Data.(*models.New).CreatedAt)
+	})
+
+	// Upsert error rolls back the batch
+	t.Run("Upsert Error", func(t *testing.T) {
+
+		// mock failed upsert
+		mock.ExpectBegin()
+		mock.ExpectQuery(
+			fmt.Sprintf(upsertManyQuery, postgres.Placeholders(1, 4))+upsertCreatedAtQuery+returningUpsertedQuery,
+		).WithArgs(
+			int64(5),
+			"test-title",
+			"test-content",
+			createdAt,
+		).WillReturnError(sqlmock.ErrCancelled)
+		mock.ExpectRollback()
+
+		// call Upsert method
+		_, err := repo.Upsert(context.Background(), items)
+
+		// check error
+		require.Error(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	// Upsert errors of postgres are mapped to domain errors
+	for code, kind := range map[string]domainErrors.Kind{
+		"23505": domainErrors.KindConflict,
+		"23514": domainErrors.KindInvalid,
+		"57014": domainErrors.KindUnavailable,
+	} {
+		t.Run("Upsert SQLSTATE "+code, func(t *testing.T) {
+
+			// mock failed upsert with postgres error
+			mock.ExpectBegin()
+			mock.ExpectQuery(
+				fmt.Sprintf(upsertManyQuery, postgres.Placeholders(1, 4))+upsertCreatedAtQuery+returningUpsertedQuery,
+			).WithArgs(
+				int64(5),
+				"test-title",
+				"test-content",
+				createdAt,
+			).WillReturnError(pgx.PgError{Severity: "ERROR", Code: code})
+			mock.ExpectRollback()
+
+			// call Upsert method
+			_, err := repo.Upsert(context.Background(), items[:1])
+
+			// check error kind
+			require.Error(t, err)
+			require.Equal(t, kind, domainErrors.KindOf(err))
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -48,4 +48,64 @@ var (
 	WHERE
 		1=1
 	`, fieldsOfNewsTable)
+
+	// query for get total count of news similar to the search text, uses pg_trgm.similarity_threshold.
+	getFuzzyTotalCountQuery = `SELECT COUNT(id) FROM news WHERE title % $1`
+
+	// query for get news similar to the search text, uses pg_trgm.similarity_threshold.
+	getAllFuzzyQuery = fmt.Sprintf(`
+	SELECT 
+		%s 
+	FROM news
+	WHERE
+		title %% $1`, fieldsOfNewsTable)
+
+	// filter of news translated to a locale, formatted with a supported locale.
+	availableLocaleFilter = ` AND EXISTS (SELECT 1 FROM translations WHERE content_type = 'news' AND content_id = news.id AND locale = '%s')`
+
+	// query for get "did you mean" titles, uses pg_trgm.word_similarity_threshold.
+	getSuggestionsQuery = `
+	SELECT
+		title
+	FROM news
+	WHERE
+		$1 <% title
+	GROUP BY title
+	ORDER BY word_similarity($1, title) DESC, title
+	LIMIT $2`
+
+	// query prefix for insert many news, followed by one placeholder row per news.
+	insertManyQuery = `
+	INSERT INTO news
+	(
+		title,
+		content,
+		created_at
+	)
+	VALUES `
+
+	// query for insert or update many news by id, formatted with placeholder rows.
+	upsertManyQuery = `
+	INSERT INTO news
+	(
+		id,
+		title,
+		content,
+		created_at
+	)
+	VALUES %s
+	ON CONFLICT (id) DO UPDATE SET
+		title = EXCLUDED.title,
+		content = EXCLUDED.content`
+
+	// suffix of upsertManyQuery updating created_at too, for rows given with their date.
+	upsertCreatedAtQuery = `,
+		created_at = EXCLUDED.created_at`
+
+	// suffix of insertManyQuery and upsertManyQuery returning the rows written, inserted or updated.
+	returningUpsertedQuery = `
+	RETURNING ` + fieldsOfNewsTable + `, (xmax = 0) AS inserted`
+
+	// query for move the id sequence past ids inserted explicitly.
+	syncIDSequenceQuery = `SELECT setval(pg_get_serial_sequence('news', 'id'), (SELECT COALESCE(MAX(id), 1) FROM news))`
 )
@@ -0,0 +1,178 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/realtemirov/task-for-dell/internal/models"
+	"github.com/realtemirov/task-for-dell/internal/news"
+	"github.com/realtemirov/task-for-dell/pkg/cache"
+	"github.com/realtemirov/task-for-dell/pkg/db/postgres"
+	"github.com/realtemirov/task-for-dell/pkg/logger"
+	"github.com/realtemirov/task-for-dell/pkg/tracing"
+	"github.com/realtemirov/task-for-dell/pkg/utils"
+)
+
+const (
+	// cacheKeyNew prefixes the cached news of an id
+	cacheKeyNew = "news:id:"
+
+	// cacheKeyList prefixes the cached lists, every write deletes them all
+	cacheKeyList = "news:list:"
+)
+
+// cachedNewsUC serves GetByID and GetAll from a cache, writes delete the entries they change.
+// Misses are read from the primary, a lagging replica would cache rows older than the write.
+// Every miss is, another instance may have written just before, see CacheConfig.
+type cachedNewsUC struct {
+	news.UseCase
+	loader *cache.Loader
+	log    logger.Logger
+}
+
+// Cached News UseCase constructor, entries live for ttl
+func NewCachedNewsUseCase(uc news.UseCase, c cache.Cache, ttl time.Duration, log logger.Logger) news.UseCase {
+	return &cachedNewsUC{
+		UseCase: uc,
+		loader:  cache.NewLoader("news", c, ttl),
+		log:     log,
+	}
+}
+
+// GetByID implements news.UseCase.
+func (u *cachedNewsUC) GetByID(ctx context.Context, newsID int64) (*models.New, error) {
+	ctx, span := tracing.Start(ctx, "cachedNewsUC.GetByID")
+	defer span.End()
+
+	item := &models.New{}
+	err := u.loader.Load(ctx, newCacheKey(newsID), item, func(ctx context.Context) (interface{}, error) {
+		return u.UseCase.GetByID(postgres.WithPrimary(ctx), newsID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
+
+// GetAll implements news.UseCase.
+func (u *cachedNewsUC) GetAll(ctx context.Context, query *utils.Query) (*models.NewsList, error) {
+	ctx, span := tracing.Start(ctx, "cachedNewsUC.GetAll")
+	defer span.End()
+
+	// lists filtered by translations change with them, they are not cached
+	if query.GetAvailableLocale() != "" {
+		return u.UseCase.GetAll(ctx, query)
+	}
+
+	result := &models.NewsList{}
+	err := u.loader.Load(ctx, listCacheKey(query), result, func(ctx context.Context) (interface{}, error) {
+		return u.UseCase.GetAll(postgres.WithPrimary(ctx), query)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Create implements news.UseCase.
+func (u *cachedNewsUC) Create(ctx context.Context, news *models.New) (*models.New, error) {
+
+	created, err := u.UseCase.Create(ctx, news)
+	if err != nil {
+		return nil, err
+	}
+	u.invalidate(ctx)
+
+	return created, nil
+}
+
+// Update implements news.UseCase.
+func (u *cachedNewsUC) Update(ctx context.Context, news *models.New) (*models.New, error) {
+
+	updated, err := u.UseCase.Update(ctx, news)
+	if err != nil {
+		return nil, err
+	}
+	u.invalidate(ctx, news.ID)
+
+	return updated, nil
+}
+
+// Delete implements news.UseCase.
+func (u *cachedNewsUC) Delete(ctx context.Context, newsID int64) error {
+
+	if err := u.UseCase.Delete(ctx, newsID); err != nil {
+		return err
+	}
+	u.invalidate(ctx, newsID)
+
+	return nil
+}
+
+// Bulk implements news.UseCase.
+func (u *cachedNewsUC) Bulk(ctx context.Context, ops []*models.BulkOperation, atomic bool) ([]*models.BulkResult, error) {
+
+	results, err := u.UseCase.Bulk(ctx, ops, atomic)
+
+	// some operations may be applied even when others failed
+	ids := make([]int64, 0, len(ops))
+	for _, op := range ops {
+		if op.ID > 0 {
+			ids = append(ids, op.ID)
+		}
+	}
+	u.invalidate(ctx, ids...)
+
+	return results, err
+}
+
+// Import implements news.UseCase.
+func (u *cachedNewsUC) Import(ctx context.Context, r io.Reader, format string, dryRun bool) (*models.ImportReport, error) {
+
+	report, err := u.UseCase.Import(ctx, r, format, dryRun)
+	if dryRun {
+		return report, err
+	}
+
+	// imports update news by id in batches, every cached news may be stale
+	if deleteErr := u.loader.InvalidatePrefix(ctx, cacheKeyNew); deleteErr != nil {
+		u.log.FromContext(ctx).Warnf("cachedNewsUC.Import: failed to invalidate news: %v", deleteErr)
+	}
+	u.invalidate(ctx)
+
+	return report, err
+}
+
+// invalidate deletes the cached news of ids and every cached list. Failures are logged,
+// the entries then expire after their ttl.
+func (u *cachedNewsUC) invalidate(ctx context.Context, ids ...int64) {
+
+	keys := make([]string, 0, len(ids))
+	for _, id := range ids {
+		keys = append(keys, newCacheKey(id))
+	}
+	if err := u.loader.Invalidate(ctx, keys...); err != nil {
+		u.log.FromContext(ctx).Warnf("cachedNewsUC.invalidate: failed to delete news %v: %v", ids, err)
+	}
+
+	if err := u.loader.InvalidatePrefix(ctx, cacheKeyList); err != nil {
+		u.log.FromContext(ctx).Warnf("cachedNewsUC.invalidate: failed to delete lists: %v", err)
+	}
+}
+
+// newCacheKey returns the cache key of the news of id
+func newCacheKey(id int64) string {
+	return fmt.Sprintf("%s%d", cacheKeyNew, id)
+}
+
+// listCacheKey returns the cache key of the list of query, the config threshold applies when it is 0
+func listCacheKey(query *utils.Query) string {
+	return fmt.Sprintf(
+		"%slimit=%d:page=%d:sort=%s:fuzzy=%t:threshold=%g:search=%q",
+		cacheKeyList, query.Limit, query.Page, query.Sort, query.Fuzzy, query.Threshold, query.Search,
+	)
+}
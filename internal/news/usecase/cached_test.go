@@ -0,0 +1,197 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/realtemirov/task-for-dell/internal/models"
+	"github.com/realtemirov/task-for-dell/internal/news/mock"
+	"github.com/realtemirov/task-for-dell/pkg/cache"
+	"github.com/realtemirov/task-for-dell/pkg/db/postgres"
+	"github.com/realtemirov/task-for-dell/pkg/domainErrors"
+	"github.com/realtemirov/task-for-dell/pkg/logger"
+	"github.com/realtemirov/task-for-dell/pkg/utils"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestCachedNewsUC_GetByID(t *testing.T) {
+	t.Parallel()
+
+	// Create a new instance of the gomock controller
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// usecase of news behind the cache
+	mockNewsUC := mock.NewMockUseCase(ctrl)
+	newsUC := NewCachedNewsUseCase(mockNewsUC, cache.NewMemory(10), time.Minute, logger.NewApiLogger(nil))
+	ctx := context.Background()
+
+	t.Run("GetByID cached", func(t *testing.T) {
+
+		// only the first call reads the news
+		mockNewsUC.EXPECT().GetByID(gomock.Any(), int64(1)).Return(&models.New{ID: 1, Title: "title"}, nil).Times(1)
+
+		for i := 0; i < 2; i++ {
+			item, err := newsUC.GetByID(ctx, 1)
+			require.NoError(t, err)
+			require.Equal(t, "title", item.Title)
+		}
+	})
+
+	t.Run("GetByID miss read from the primary", func(t *testing.T) {
+
+		// a lagging replica would be cached until the ttl
+		mockNewsUC.EXPECT().GetByID(gomock.Any(), int64(2)).DoAndReturn(
+			func(ctx context.Context, id int64) (*models.New, error) {
+				require.True(t, postgres.UsePrimary(ctx))
+				return &models.New{ID: id}, nil
+			},
+		)
+
+		_, err := newsUC.GetByID(ctx, 2)
+		require.NoError(t, err)
+	})
+
+	t.Run("GetByID copies", func(t *testing.T) {
+
+		// callers may change the news, e.g. to translate it, without changing the cache
+		item, err := newsUC.GetByID(ctx, 1)
+		require.NoError(t, err)
+		item.Title = "translated"
+
+		item, err = newsUC.GetByID(ctx, 1)
+		require.NoError(t, err)
+		require.Equal(t, "title", item.Title)
+	})
+
+	t.Run("GetByID invalidated by Update", func(t *testing.T) {
+
+		updated := &models.New{ID: 1, Title: "updated"}
+		mockNewsUC.EXPECT().Update(gomock.Any(), updated).Return(updated, nil)
+		mockNewsUC.EXPECT().GetByID(gomock.Any(), int64(1)).Return(updated, nil).Times(1)
+
+		_, err := newsUC.Update(ctx, updated)
+		require.NoError(t, err)
+
+		item, err := newsUC.GetByID(ctx, 1)
+		require.NoError(t, err)
+		require.Equal(t, "updated", item.Title)
+	})
+
+	t.Run("GetByID invalidated by Delete", func(t *testing.T) {
+
+		mockNewsUC.EXPECT().Delete(gomock.Any(), int64(1)).Return(nil)
+		mockNewsUC.EXPECT().GetByID(gomock.Any(), int64(1)).Return(nil, domainErrors.NotFound("record not found", nil))
+
+		require.NoError(t, newsUC.Delete(ctx, 1))
+
+		_, err := newsUC.GetByID(ctx, 1)
+		require.Equal(t, domainErrors.KindNotFound, domainErrors.KindOf(err))
+	})
+
+	t.Run("GetByID updated during a slow load", func(t *testing.T) {
+
+		started, release := make(chan struct{}), make(chan struct{})
+		updated := &models.New{ID: 3, Title: "updated"}
+		gomock.InOrder(
+			mockNewsUC.EXPECT().GetByID(gomock.Any(), int64(3)).DoAndReturn(
+				func(ctx context.Context, id int64) (*models.New, error) {
+					close(started)
+					<-release
+					return &models.New{ID: id, Title: "title"}, nil
+				},
+			),
+			mockNewsUC.EXPECT().GetByID(gomock.Any(), int64(3)).Return(updated, nil),
+		)
+		mockNewsUC.EXPECT().Update(gomock.Any(), updated).Return(updated, nil)
+
+		// the load reads the news before the update, which commits meanwhile
+		loaded := make(chan error, 1)
+		go func() {
+			_, err := newsUC.GetByID(ctx, 3)
+			loaded <- err
+		}()
+		<-started
+		_, err := newsUC.Update(ctx, updated)
+		require.NoError(t, err)
+		close(release)
+		require.NoError(t, <-loaded)
+
+		// the news read before the update is not cached
+		item, err := newsUC.GetByID(ctx, 3)
+		require.NoError(t, err)
+		require.Equal(t, "updated", item.Title)
+
+		item, err = newsUC.GetByID(ctx, 3)
+		require.NoError(t, err)
+		require.Equal(t, "updated", item.Title)
+	})
+}
+
+func TestCachedNewsUC_GetAll(t *testing.T) {
+	t.Parallel()
+
+	// Create a new instance of the gomock controller
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// usecase of news behind the cache
+	mockNewsUC := mock.NewMockUseCase(ctrl)
+	newsUC := NewCachedNewsUseCase(mockNewsUC, cache.NewMemory(10), time.Minute, logger.NewApiLogger(nil))
+	ctx := context.Background()
+
+	list := &models.NewsList{TotalCount: 1, News: []*models.New{{ID: 1, Title: "title"}}}
+
+	t.Run("GetAll cached by query", func(t *testing.T) {
+
+		// one read for each distinct query
+		mockNewsUC.EXPECT().GetAll(gomock.Any(), gomock.Any()).Return(list, nil).Times(2)
+
+		for i := 0; i < 2; i++ {
+			for _, page := range []int{1, 2} {
+				result, err := newsUC.GetAll(ctx, &utils.Query{Limit: 10, Page: page})
+				require.NoError(t, err)
+				require.Equal(t, 1, result.TotalCount)
+			}
+		}
+	})
+
+	t.Run("GetAll invalidated by Create", func(t *testing.T) {
+
+		item := &models.New{Title: "new title", Content: "new content"}
+		mockNewsUC.EXPECT().Create(gomock.Any(), item).Return(&models.New{ID: 2}, nil)
+		mockNewsUC.EXPECT().GetAll(gomock.Any(), gomock.Any()).Return(list, nil).Times(1)
+
+		_, err := newsUC.Create(ctx, item)
+		require.NoError(t, err)
+
+		_, err = newsUC.GetAll(ctx, &utils.Query{Limit: 10, Page: 1})
+		require.NoError(t, err)
+	})
+
+	t.Run("GetAll invalidated by Import", func(t *testing.T) {
+
+		mockNewsUC.EXPECT().Import(gomock.Any(), gomock.Any(), utils.FORMAT_CSV, false).Return(&models.ImportReport{}, nil)
+		mockNewsUC.EXPECT().GetAll(gomock.Any(), gomock.Any()).Return(list, nil).Times(1)
+
+		_, err := newsUC.Import(ctx, strings.NewReader(""), utils.FORMAT_CSV, false)
+		require.NoError(t, err)
+
+		_, err = newsUC.GetAll(ctx, &utils.Query{Limit: 10, Page: 1})
+		require.NoError(t, err)
+	})
+
+	t.Run("GetAll by available locale not cached", func(t *testing.T) {
+
+		query := &utils.Query{Limit: 10, AvailableLocale: utils.LOCALE_RU}
+		mockNewsUC.EXPECT().GetAll(gomock.Any(), query).Return(list, nil).Times(2)
+
+		for i := 0; i < 2; i++ {
+			_, err := newsUC.GetAll(ctx, query)
+			require.NoError(t, err)
+		}
+	})
+}
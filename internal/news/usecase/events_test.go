@@ -0,0 +1,212 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/realtemirov/task-for-dell/internal/models"
+	"github.com/realtemirov/task-for-dell/internal/news/mock"
+	outboxMock "github.com/realtemirov/task-for-dell/internal/outbox/mock"
+	"github.com/realtemirov/task-for-dell/pkg/domainErrors"
+	"github.com/realtemirov/task-for-dell/pkg/utils"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// txManager runs fn directly, counting the transactions
+type txManager struct {
+	calls int
+}
+
+func (m *txManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	m.calls++
+	return fn(ctx)
+}
+
+// eventTypes returns the types of events
+func eventTypes(events []*models.Event) []string {
+	types := make([]string, 0, len(events))
+	for _, event := range events {
+		types = append(types, event.Type)
+	}
+
+	return types
+}
+
+func TestEventsNewsUC(t *testing.T) {
+	t.Parallel()
+
+	// Create a new instance of the gomock controller
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// usecase of news writing events to the outbox
+	mockNewsUC := mock.NewMockUseCase(ctrl)
+	mockOutboxRepo := outboxMock.NewMockRepository(ctrl)
+	tx := &txManager{}
+	newsUC := NewEventsNewsUseCase(mockNewsUC, tx, mockOutboxRepo)
+	ctx := context.Background()
+
+	t.Run("Create", func(t *testing.T) {
+
+		news := &models.New{Title: "title", Content: "content"}
+		created := &models.New{ID: 1, Title: "title", Content: "content"}
+		mockNewsUC.EXPECT().Create(gomock.Any(), news).Return(created, nil)
+		mockOutboxRepo.EXPECT().Add(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, events ...*models.Event) error {
+				require.Equal(t, []string{models.EventContentCreated, models.EventContentPublished}, eventTypes(events))
+				require.Equal(t, models.ContentTypeNews, events[0].ContentType)
+				require.EqualValues(t, 1, events[0].ContentID)
+
+				// the payload is the news created
+				var payload models.New
+				require.NoError(t, json.Unmarshal(events[0].Payload, &payload))
+				require.Equal(t, *created, payload)
+				return nil
+			},
+		)
+
+		calls := tx.calls
+		result, err := newsUC.Create(ctx, news)
+		require.NoError(t, err)
+		require.Equal(t, created, result)
+		require.Equal(t, calls+1, tx.calls)
+	})
+
+	t.Run("Update", func(t *testing.T) {
+
+		news := &models.New{ID: 1, Title: "updated"}
+		mockNewsUC.EXPECT().Update(gomock.Any(), news).Return(news, nil)
+		mockOutboxRepo.EXPECT().Add(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, events ...*models.Event) error {
+				require.Equal(t, []string{models.EventContentUpdated}, eventTypes(events))
+				return nil
+			},
+		)
+
+		_, err := newsUC.Update(ctx, news)
+		require.NoError(t, err)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+
+		mockNewsUC.EXPECT().Delete(gomock.Any(), int64(1)).Return(nil)
+		mockOutboxRepo.EXPECT().Add(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, events ...*models.Event) error {
+				require.Equal(t, []string{models.EventContentDeleted}, eventTypes(events))
+				require.JSONEq(t, `{"id":1}`, string(events[0].Payload))
+				return nil
+			},
+		)
+
+		require.NoError(t, newsUC.Delete(ctx, 1))
+	})
+
+	t.Run("Delete failed", func(t *testing.T) {
+
+		// no event of a change which failed
+		mockNewsUC.EXPECT().Delete(gomock.Any(), int64(2)).Return(domainErrors.NotFound("record not found", nil))
+
+		require.Equal(t, domainErrors.KindNotFound, domainErrors.KindOf(newsUC.Delete(ctx, 2)))
+	})
+
+	t.Run("Create outbox failed", func(t *testing.T) {
+
+		// the change is rolled back with its events
+		news := &models.New{Title: "title"}
+		mockNewsUC.EXPECT().Create(gomock.Any(), news).Return(&models.New{ID: 3, Title: "title"}, nil)
+		mockOutboxRepo.EXPECT().Add(gomock.Any(), gomock.Any()).Return(errors.New("outbox"))
+
+		result, err := newsUC.Create(ctx, news)
+		require.Error(t, err)
+		require.Nil(t, result)
+	})
+
+	t.Run("Bulk", func(t *testing.T) {
+
+		ops := []*models.BulkOperation{
+			{Op: models.BulkOpCreate, Title: "title", Content: "content"},
+			{Op: models.BulkOpUpdate, ID: 9, Title: "title"},
+			{Op: models.BulkOpDelete, ID: 1},
+		}
+		results := []*models.BulkResult{
+			{Index: 0, Op: models.BulkOpCreate, ID: 4, Data: &models.New{ID: 4, Title: "title"}},
+			{Index: 1, Op: models.BulkOpUpdate, ID: 9, Err: domainErrors.NotFound("record not found", nil)},
+			{Index: 2, Op: models.BulkOpDelete, ID: 1},
+		}
+		mockNewsUC.EXPECT().Bulk(gomock.Any(), ops, false).Return(results, nil)
+		mockOutboxRepo.EXPECT().Add(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, events ...*models.Event) error {
+				// only the operations applied
+				require.Equal(t, []string{
+					models.EventContentCreated, models.EventContentPublished, models.EventContentDeleted,
+				}, eventTypes(events))
+				require.EqualValues(t, 4, events[0].ContentID)
+				require.EqualValues(t, 1, events[2].ContentID)
+				return nil
+			},
+		)
+
+		result, err := newsUC.Bulk(ctx, ops, false)
+		require.NoError(t, err)
+		require.Equal(t, results, result)
+	})
+	t.Run("Import", func(t *testing.T) {
+
+		// the import writes two batches, the second one failing
+		report := &models.ImportReport{Total: 3, Accepted: 2, Rejected: 1}
+		errBatch := errors.New("duplicate key")
+		mockNewsUC.EXPECT().Import(gomock.Any(), gomock.Any(), utils.FORMAT_NDJSON, false).DoAndReturn(
+			func(ctx context.Context, r io.Reader, format string, dryRun bool) (*models.ImportReport, error) {
+				err := models.WriteImportBatch(ctx, func(ctx context.Context) ([]*models.BulkResult, error) {
+					return []*models.BulkResult{
+						{Op: models.BulkOpCreate, ID: 4, Data: &models.New{ID: 4, Title: "title"}},
+						{Op: models.BulkOpUpdate, ID: 9, Data: &models.New{ID: 9, Title: "title"}},
+					}, nil
+				})
+				require.NoError(t, err)
+
+				err = models.WriteImportBatch(ctx, func(ctx context.Context) ([]*models.BulkResult, error) {
+					return nil, errBatch
+				})
+				require.ErrorIs(t, err, errBatch)
+
+				return report, nil
+			},
+		)
+		mockOutboxRepo.EXPECT().Add(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, events ...*models.Event) error {
+				// the rows of the batch, in its transaction
+				require.Equal(t, []string{
+					models.EventContentCreated, models.EventContentPublished, models.EventContentUpdated,
+				}, eventTypes(events))
+				require.EqualValues(t, 4, events[0].ContentID)
+				require.EqualValues(t, 9, events[2].ContentID)
+				return nil
+			},
+		)
+
+		calls := tx.calls
+		result, err := newsUC.Import(ctx, strings.NewReader(""), utils.FORMAT_NDJSON, false)
+		require.NoError(t, err)
+		require.Equal(t, report, result)
+		require.Equal(t, calls+2, tx.calls)
+	})
+
+	t.Run("Import Dry Run", func(t *testing.T) {
+
+		// nothing is written, no transaction nor event
+		report := &models.ImportReport{DryRun: true, Total: 1, Accepted: 1}
+		mockNewsUC.EXPECT().Import(gomock.Any(), gomock.Any(), utils.FORMAT_NDJSON, true).Return(report, nil)
+
+		calls := tx.calls
+		result, err := newsUC.Import(ctx, strings.NewReader(""), utils.FORMAT_NDJSON, true)
+		require.NoError(t, err)
+		require.Equal(t, report, result)
+		require.Equal(t, calls, tx.calls)
+	})
+}
@@ -0,0 +1,84 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/realtemirov/task-for-dell/internal/models"
+	"github.com/realtemirov/task-for-dell/internal/news"
+	"github.com/realtemirov/task-for-dell/internal/translations"
+	"github.com/realtemirov/task-for-dell/pkg/utils"
+)
+
+// localizedNewsUC translates the news it reads to the locale of the request
+type localizedNewsUC struct {
+	news.UseCase
+	translationsUC translations.UseCase
+}
+
+// Localized News UseCase constructor, news without a translation stay in the default locale
+func NewLocalizedNewsUseCase(uc news.UseCase, translationsUC translations.UseCase) news.UseCase {
+	return &localizedNewsUC{
+		UseCase:        uc,
+		translationsUC: translationsUC,
+	}
+}
+
+// GetByID implements news.UseCase.
+func (u *localizedNewsUC) GetByID(ctx context.Context, newsID int64) (*models.New, error) {
+
+	item, err := u.UseCase.GetByID(ctx, newsID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = u.localize(ctx, []*models.New{item}); err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
+
+// GetAll implements news.UseCase.
+func (u *localizedNewsUC) GetAll(ctx context.Context, query *utils.Query) (*models.NewsList, error) {
+
+	result, err := u.UseCase.GetAll(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = u.localize(ctx, result.News); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// localize replaces title and content of the news with their translations
+func (u *localizedNewsUC) localize(ctx context.Context, newsList []*models.New) error {
+
+	if len(newsList) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, 0, len(newsList))
+	for _, item := range newsList {
+		ids = append(ids, item.ID)
+	}
+
+	locale := utils.GetLocale(ctx)
+	translated, err := u.translationsUC.GetMany(ctx, models.ContentTypeNews, ids, locale)
+	if err != nil {
+		return err
+	}
+
+	for _, item := range newsList {
+		item.Locale = utils.DEFAULT_LOCALE
+		if translation, ok := translated[item.ID]; ok {
+			item.Title = translation.Title
+			item.Content = translation.Content
+			item.Locale = locale
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,97 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/realtemirov/task-for-dell/internal/models"
+	"github.com/realtemirov/task-for-dell/internal/news/mock"
+	translationsMock "github.com/realtemirov/task-for-dell/internal/translations/mock"
+	"github.com/realtemirov/task-for-dell/pkg/utils"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestLocalizedNewsUC_GetByID(t *testing.T) {
+	t.Parallel()
+
+	// Create a new instance of the gomock controller
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// usecases of news and translations
+	mockNewsUC := mock.NewMockUseCase(ctrl)
+	mockTranslationsUC := translationsMock.NewMockUseCase(ctrl)
+	newsUC := NewLocalizedNewsUseCase(mockNewsUC, mockTranslationsUC)
+
+	t.Run("GetByID translated", func(t *testing.T) {
+		ctx := utils.WithLocale(context.Background(), utils.LOCALE_RU)
+
+		// mock the news and its russian translation
+		mockNewsUC.EXPECT().GetByID(ctx, int64(1)).Return(&models.New{ID: 1, Title: "title", Content: "content"}, nil)
+		mockTranslationsUC.EXPECT().GetMany(ctx, models.ContentTypeNews, []int64{1}, utils.LOCALE_RU).Return(
+			map[int64]*models.Translation{1: {ContentID: 1, Locale: utils.LOCALE_RU, Title: "заголовок", Content: "содержание"}}, nil,
+		)
+
+		// call the GetByID method of the usecase
+		item, err := newsUC.GetByID(ctx, 1)
+
+		// check the result
+		require.NoError(t, err)
+		require.Equal(t, "заголовок", item.Title)
+		require.Equal(t, "содержание", item.Content)
+		require.Equal(t, utils.LOCALE_RU, item.Locale)
+	})
+
+	t.Run("GetByID fallback", func(t *testing.T) {
+		ctx := utils.WithLocale(context.Background(), utils.LOCALE_UZ)
+
+		// mock the news without uzbek translation
+		mockNewsUC.EXPECT().GetByID(ctx, int64(1)).Return(&models.New{ID: 1, Title: "title", Content: "content"}, nil)
+		mockTranslationsUC.EXPECT().GetMany(ctx, models.ContentTypeNews, []int64{1}, utils.LOCALE_UZ).Return(
+			map[int64]*models.Translation{}, nil,
+		)
+
+		// call the GetByID method of the usecase
+		item, err := newsUC.GetByID(ctx, 1)
+
+		// check the result
+		require.NoError(t, err)
+		require.Equal(t, "title", item.Title)
+		require.Equal(t, utils.DEFAULT_LOCALE, item.Locale)
+	})
+}
+
+func TestLocalizedNewsUC_GetAll(t *testing.T) {
+	t.Parallel()
+
+	// Create a new instance of the gomock controller
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// usecases of news and translations
+	mockNewsUC := mock.NewMockUseCase(ctrl)
+	mockTranslationsUC := translationsMock.NewMockUseCase(ctrl)
+	newsUC := NewLocalizedNewsUseCase(mockNewsUC, mockTranslationsUC)
+
+	ctx := utils.WithLocale(context.Background(), utils.LOCALE_RU)
+	query := &utils.Query{}
+
+	// mock two news, only the second is translated
+	mockNewsUC.EXPECT().GetAll(ctx, query).Return(&models.NewsList{
+		News: []*models.New{{ID: 1, Title: "first"}, {ID: 2, Title: "second"}},
+	}, nil)
+	mockTranslationsUC.EXPECT().GetMany(ctx, models.ContentTypeNews, []int64{1, 2}, utils.LOCALE_RU).Return(
+		map[int64]*models.Translation{2: {ContentID: 2, Locale: utils.LOCALE_RU, Title: "второй"}}, nil,
+	)
+
+	// call the GetAll method of the usecase
+	result, err := newsUC.GetAll(ctx, query)
+
+	// check the result
+	require.NoError(t, err)
+	require.Equal(t, "first", result.News[0].Title)
+	require.Equal(t, utils.DEFAULT_LOCALE, result.News[0].Locale)
+	require.Equal(t, "второй", result.News[1].Title)
+	require.Equal(t, utils.LOCALE_RU, result.News[1].Locale)
+}
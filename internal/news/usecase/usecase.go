@@ -2,11 +2,15 @@ package usecase
 
 import (
 	"context"
+	"errors"
+	"io"
 
+	pkgErrors "github.com/pkg/errors"
 	"github.com/realtemirov/task-for-dell/config"
 	"github.com/realtemirov/task-for-dell/internal/models"
 	"github.com/realtemirov/task-for-dell/internal/news"
 	"github.com/realtemirov/task-for-dell/pkg/logger"
+	"github.com/realtemirov/task-for-dell/pkg/tracing"
 	"github.com/realtemirov/task-for-dell/pkg/utils"
 )
 
@@ -28,25 +32,218 @@ func NewNewsUseCase(cfg *config.Config, repo news.Repository, log logger.Logger)
 
 // Create implements news.UseCase.
 func (u *newsUC) Create(ctx context.Context, news *models.New) (*models.New, error) {
+	ctx, span := tracing.Start(ctx, "newsUC.Create")
+	defer span.End()
+
 	return u.repo.Create(ctx, news)
 }
 
 // Update implements news.UseCase.
 func (u *newsUC) Update(ctx context.Context, news *models.New) (*models.New, error) {
+	ctx, span := tracing.Start(ctx, "newsUC.Update")
+	defer span.End()
+
 	return u.repo.Update(ctx, news)
 }
 
 // Delete implements news.UseCase.
 func (u *newsUC) Delete(ctx context.Context, newsID int64) error {
+	ctx, span := tracing.Start(ctx, "newsUC.Delete")
+	defer span.End()
+
 	return u.repo.Delete(ctx, newsID)
 }
 
 // GetAll implements news.UseCase.
 func (u *newsUC) GetAll(ctx context.Context, query *utils.Query) (*models.NewsList, error) {
+	ctx, span := tracing.Start(ctx, "newsUC.GetAll")
+	defer span.End()
+
+	// similarity threshold for fuzzy search and "did you mean" suggestions
+	if query.Search != "" && query.Threshold == 0 {
+		query.Threshold = u.cfg.Search.SimilarityThreshold
+	}
+
 	return u.repo.GetAll(ctx, query)
 }
 
 // GetByID implements news.UseCase.
 func (u *newsUC) GetByID(ctx context.Context, newsID int64) (*models.New, error) {
+	ctx, span := tracing.Start(ctx, "newsUC.GetByID")
+	defer span.End()
+
 	return u.repo.GetByID(ctx, newsID)
 }
+
+// Bulk implements news.UseCase.
+func (u *newsUC) Bulk(ctx context.Context, ops []*models.BulkOperation, atomic bool) ([]*models.BulkResult, error) {
+	ctx, span := tracing.Start(ctx, "newsUC.Bulk")
+	defer span.End()
+
+	var (
+		results   = make([]*models.BulkResult, len(ops))
+		valid     = make([]*models.BulkOperation, 0, len(ops))
+		positions = make([]int, 0, len(ops))
+	)
+
+	// validate every operation, invalid ones are reported and not executed
+	for i, op := range ops {
+		if err := u.validateBulkOperation(ctx, op); err != nil {
+			results[i] = &models.BulkResult{Index: i, Op: op.Op, ID: op.ID, Err: err}
+			continue
+		}
+
+		valid = append(valid, op)
+		positions = append(positions, i)
+	}
+
+	// in atomic mode one invalid operation aborts the whole batch
+	if atomic && len(valid) != len(ops) {
+		for _, i := range positions {
+			results[i] = &models.BulkResult{Index: i, Op: ops[i].Op, ID: ops[i].ID, Err: utils.ErrBulkAborted}
+		}
+
+		return results, nil
+	}
+
+	if len(valid) == 0 {
+		return results, nil
+	}
+
+	executed, err := u.repo.Bulk(ctx, valid, atomic)
+	if err != nil {
+		return nil, err
+	}
+
+	// place executed results back at the index of their operation
+	for j, result := range executed {
+		result.Index = positions[j]
+		results[positions[j]] = result
+	}
+
+	return results, nil
+}
+
+// validateBulkOperation validates the operation and the news it creates or updates
+func (u *newsUC) validateBulkOperation(ctx context.Context, op *models.BulkOperation) error {
+	if err := utils.ValidateStruct(ctx, op); err != nil {
+		return err
+	}
+
+	if op.Op == models.BulkOpDelete {
+		return nil
+	}
+
+	return utils.ValidateStruct(ctx, &models.New{
+		ID:      op.ID,
+		Title:   op.Title,
+		Content: op.Content,
+	})
+}
+
+// Export implements news.UseCase.
+func (u *newsUC) Export(ctx context.Context, query *utils.Query, fn func(new *models.New) error) error {
+	ctx, span := tracing.Start(ctx, "newsUC.Export")
+	defer span.End()
+
+	// same similarity threshold as GetAll
+	if query.Search != "" && query.Threshold == 0 {
+		query.Threshold = u.cfg.Search.SimilarityThreshold
+	}
+
+	return u.repo.Export(ctx, query, fn)
+}
+
+// Import implements news.UseCase.
+func (u *newsUC) Import(ctx context.Context, r io.Reader, format string, dryRun bool) (*models.ImportReport, error) {
+	ctx, span := tracing.Start(ctx, "newsUC.Import")
+	defer span.End()
+
+	var (
+		report = models.NewImportReport(format, dryRun, utils.GetLocale(ctx))
+		reader = utils.NewImportReader(r, format)
+		batch  = make([]*models.New, 0, utils.IMPORT_BATCH_SIZE)
+		lines  = make([]int, 0, utils.IMPORT_BATCH_SIZE)
+	)
+
+	for {
+		new := &models.New{}
+		line, err := reader.Next(new)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		// a line that can not be decoded is rejected, the others go on
+		var lineErr *utils.ImportLineError
+		if errors.As(err, &lineErr) {
+			report.Total++
+			report.Reject(lineErr.Line, lineErr.Err, lineErr.Raw)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		report.Total++
+		if err = utils.ValidateStruct(ctx, new); err != nil {
+			report.Reject(line, err, "")
+			continue
+		}
+
+		batch = append(batch, new)
+		lines = append(lines, line)
+		if len(batch) < utils.IMPORT_BATCH_SIZE {
+			continue
+		}
+
+		if err = u.importBatch(ctx, report, batch, lines); err != nil {
+			return nil, err
+		}
+		batch, lines = batch[:0], lines[:0]
+	}
+
+	if err := u.importBatch(ctx, report, batch, lines); err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// importBatch upserts a batch of valid news, a failed batch is retried one by one to find the rejected lines
+func (u *newsUC) importBatch(ctx context.Context, report *models.ImportReport, batch []*models.New, lines []int) error {
+	if len(batch) == 0 {
+		return nil
+	}
+
+	// dry run only validates
+	if report.DryRun {
+		report.Accepted += len(batch)
+		return nil
+	}
+
+	err := models.WriteImportBatch(ctx, func(ctx context.Context) ([]*models.BulkResult, error) {
+		return u.repo.Upsert(ctx, batch)
+	})
+	if err == nil {
+		report.Accepted += len(batch)
+		return nil
+	}
+	u.log.FromContext(ctx).Warnf("newsUC.importBatch: batch of %d failed, retrying one by one: %v", len(batch), err)
+
+	for i := range batch {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		err := models.WriteImportBatch(ctx, func(ctx context.Context) ([]*models.BulkResult, error) {
+			return u.repo.Upsert(ctx, batch[i:i+1])
+		})
+		if err != nil {
+			report.Reject(lines[i], pkgErrors.Cause(err), "")
+			continue
+		}
+		report.Accepted++
+	}
+
+	return nil
+}
@@ -2,8 +2,11 @@ package usecase
 
 import (
 	"context"
+	"database/sql"
+	"strings"
 	"testing"
 
+	"github.com/realtemirov/task-for-dell/config"
 	"github.com/realtemirov/task-for-dell/internal/models"
 	"github.com/realtemirov/task-for-dell/internal/news/mock"
 	"github.com/realtemirov/task-for-dell/pkg/logger"
@@ -168,3 +171,232 @@ func TestNewUC_GetAll(t *testing.T) {
 	require.NoError(t, err)
 	require.NotNil(t, newList)
 }
+
+func TestNewUC_GetAllFuzzy(t *testing.T) {
+	t.Parallel()
+
+	// Create a new instance of the gomock controller
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// config with similarity threshold
+	cfg := &config.Config{
+		Search: config.SearchConfig{
+			SimilarityThreshold: 0.5,
+		},
+	}
+
+	// logger, repository, usecase of new
+	logger := logger.NewApiLogger(cfg)
+	mockNewRepo := mock.NewMockRepository(ctrl)
+	newUC := NewNewsUseCase(cfg, mockNewRepo, logger)
+
+	// entity of new list, context, query
+	entity := models.NewsList{}
+	ctx := context.Background()
+	query := utils.Query{
+		Page:   1,
+		Limit:  10,
+		Search: "goverment",
+		Fuzzy:  true,
+	}
+
+	// mock the GetAll method of the repository with configured threshold
+	mockNewRepo.EXPECT().GetAll(
+		ctx,
+		&utils.Query{
+			Page:      1,
+			Limit:     10,
+			Search:    "goverment",
+			Fuzzy:     true,
+			Threshold: 0.5,
+		},
+	).Return(&entity, nil)
+
+	// call the GetAll method of the usecase
+	newList, err := newUC.GetAll(ctx, &query)
+
+	// check the result
+	require.NoError(t, err)
+	require.NotNil(t, newList)
+}
+
+func TestNewUC_Bulk(t *testing.T) {
+	t.Parallel()
+
+	// Create a new instance of the gomock controller
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// logger, repository, usecase of new
+	logger := logger.NewApiLogger(nil)
+	mockNewRepo := mock.NewMockRepository(ctrl)
+	newUC := NewNewsUseCase(nil, mockNewRepo, logger)
+
+	// operations, the second one is invalid
+	ops := []*models.BulkOperation{
+		{Op: models.BulkOpCreate, Title: "test-title", Content: "test-content"},
+		{Op: models.BulkOpUpdate, Title: "t"},
+		{Op: models.BulkOpDelete, ID: 3},
+	}
+	ctx := context.Background()
+
+	t.Run("Bulk Best Effort", func(t *testing.T) {
+
+		// mock the Bulk method of the repository with valid operations only
+		mockNewRepo.EXPECT().Bulk(
+			ctx,
+			[]*models.BulkOperation{ops[0], ops[2]},
+			false,
+		).Return([]*models.BulkResult{
+			{Op: models.BulkOpCreate, ID: 1},
+			{Op: models.BulkOpDelete, ID: 3},
+		}, nil)
+
+		// call the Bulk method of the usecase
+		results, err := newUC.Bulk(ctx, ops, false)
+
+		// check the result
+		require.NoError(t, err)
+		require.Len(t, results, 3)
+		require.NoError(t, results[0].Err)
+		require.Error(t, results[1].Err)
+		require.NotEmpty(t, utils.ValidationMessages(results[1].Err, utils.LOCALE_EN))
+		require.Equal(t, 2, results[2].Index)
+		require.NoError(t, results[2].Err)
+	})
+
+	t.Run("Bulk Atomic Invalid", func(t *testing.T) {
+
+		// call the Bulk method of the usecase, repository is not called
+		results, err := newUC.Bulk(ctx, ops, true)
+
+		// check the result
+		require.NoError(t, err)
+		require.Len(t, results, 3)
+		require.ErrorIs(t, results[0].Err, utils.ErrBulkAborted)
+		require.Error(t, results[1].Err)
+		require.ErrorIs(t, results[2].Err, utils.ErrBulkAborted)
+	})
+}
+
+func TestNewUC_Export(t *testing.T) {
+	t.Parallel()
+
+	// Create a new instance of the gomock controller
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// config with similarity threshold
+	cfg := &config.Config{
+		Search: config.SearchConfig{
+			SimilarityThreshold: 0.5,
+		},
+	}
+
+	// logger, repository, usecase of new
+	logger := logger.NewApiLogger(cfg)
+	mockNewRepo := mock.NewMockRepository(ctrl)
+	newUC := NewNewsUseCase(cfg, mockNewRepo, logger)
+
+	// context, query
+	ctx := context.Background()
+	query := utils.Query{Search: "goverment", Fuzzy: true}
+
+	// mock the Export method of the repository with configured threshold
+	mockNewRepo.EXPECT().Export(
+		ctx,
+		&utils.Query{Search: "goverment", Fuzzy: true, Threshold: 0.5},
+		gomock.Any(),
+	).Return(nil)
+
+	// call the Export method of the usecase
+	err := newUC.Export(ctx, &query, func(new *models.New) error { return nil })
+
+	// check the result
+	require.NoError(t, err)
+}
+
+func TestNewUC_Import(t *testing.T) {
+	t.Parallel()
+
+	// Create a new instance of the gomock controller
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// logger, repository, usecase of new
+	logger := logger.NewApiLogger(nil)
+	mockNewRepo := mock.NewMockRepository(ctrl)
+	newUC := NewNewsUseCase(nil, mockNewRepo, logger)
+
+	// valid line, broken json, invalid new, valid line
+	input := `{"id":1,"title":"test-title","content":"test-content"}
+{"title":
+{"title":"t","content":"test-content"}
+{"title":"test-title-2","content":"test-content"}
+`
+	ctx := context.Background()
+
+	t.Run("Import", func(t *testing.T) {
+
+		// mock the Upsert method of the repository with valid lines only
+		mockNewRepo.EXPECT().Upsert(ctx, gomock.Len(2)).Return(nil, nil)
+
+		// call the Import method of the usecase
+		report, err := newUC.Import(ctx, strings.NewReader(input), utils.FORMAT_NDJSON, false)
+
+		// check the result
+		require.NoError(t, err)
+		require.Equal(t, 4, report.Total)
+		require.Equal(t, 2, report.Accepted)
+		require.Equal(t, 2, report.Rejected)
+		require.Equal(t, 2, report.Errors[0].Line)
+		require.Equal(t, 3, report.Errors[1].Line)
+	})
+
+	t.Run("Import Dry Run", func(t *testing.T) {
+
+		// call the Import method of the usecase, repository is not called
+		report, err := newUC.Import(ctx, strings.NewReader(input), utils.FORMAT_NDJSON, true)
+
+		// check the result
+		require.NoError(t, err)
+		require.True(t, report.DryRun)
+		require.Equal(t, 2, report.Accepted)
+		require.Equal(t, 2, report.Rejected)
+	})
+
+	t.Run("Import Batch Error", func(t *testing.T) {
+
+		// failed batch is retried line by line
+		gomock.InOrder(
+			mockNewRepo.EXPECT().Upsert(ctx, gomock.Len(2)).Return(nil, sql.ErrConnDone),
+			mockNewRepo.EXPECT().Upsert(ctx, gomock.Len(1)).Return(nil, nil),
+			mockNewRepo.EXPECT().Upsert(ctx, gomock.Len(1)).Return(nil, sql.ErrConnDone),
+		)
+
+		// call the Import method of the usecase
+		report, err := newUC.Import(ctx, strings.NewReader(input), utils.FORMAT_NDJSON, false)
+
+		// check the result
+		require.NoError(t, err)
+		require.Equal(t, 1, report.Accepted)
+		require.Equal(t, 3, report.Rejected)
+		require.Equal(t, 4, report.Errors[2].Line)
+	})
+
+	t.Run("Import CSV", func(t *testing.T) {
+
+		// mock the Upsert method of the repository
+		mockNewRepo.EXPECT().Upsert(ctx, gomock.Len(1)).Return(nil, nil)
+
+		// call the Import method of the usecase
+		csv := "title,content\ntest-title,test-content\n"
+		report, err := newUC.Import(ctx, strings.NewReader(csv), utils.FORMAT_CSV, false)
+
+		// check the result
+		require.NoError(t, err)
+		require.Equal(t, 1, report.Total)
+		require.Equal(t, 1, report.Accepted)
+	})
+}
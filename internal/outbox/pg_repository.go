@@ -0,0 +1,19 @@
+package outbox
+
+import (
+	"context"
+	"time"
+
+	"github.com/realtemirov/task-for-dell/internal/models"
+)
+
+type Repository interface {
+	Add(ctx context.Context, events ...*models.Event) error
+	Claim(ctx context.Context, limit int, until time.Time) ([]*models.Event, error)
+	MarkDelivered(ctx context.Context, ids []int64) error
+	MarkFailed(ctx context.Context, id int64, reason string, retryAt time.Time) error
+	DeleteDelivered(ctx context.Context, before time.Time) (int64, error)
+	GetByID(ctx context.Context, id int64) (*models.Event, error)
+	After(ctx context.Context, contentType string, afterID int64, limit int) ([]*models.Event, error)
+	Lookback(ctx context.Context, contentType string, eventID int64, window time.Duration) (int64, error)
+}
@@ -0,0 +1,142 @@
+package relay
+
+import (
+	"context"
+	"time"
+
+	"github.com/realtemirov/task-for-dell/config"
+	"github.com/realtemirov/task-for-dell/internal/models"
+	"github.com/realtemirov/task-for-dell/internal/outbox"
+	"github.com/realtemirov/task-for-dell/pkg/logger"
+	"github.com/realtemirov/task-for-dell/pkg/metrics"
+	"github.com/realtemirov/task-for-dell/pkg/tracing"
+)
+
+// CLEANUP_INTERVAL is how often delivered events older than the retention are deleted
+const CLEANUP_INTERVAL = time.Hour
+
+// Relay delivers the events of the outbox to a sink, at least once, in order of id as they are claimed.
+// A failed event is retried after its backoff, the events after it are not held back meanwhile.
+// Relays of several instances share the work, each event is claimed by the one delivering it.
+type Relay struct {
+	cfg  *config.Config
+	repo outbox.Repository
+	sink outbox.Sink
+	log  logger.Logger
+}
+
+// Relay constructor
+func NewRelay(cfg *config.Config, repo outbox.Repository, sink outbox.Sink, log logger.Logger) *Relay {
+	return &Relay{
+		cfg:  cfg,
+		repo: repo,
+		sink: sink,
+		log:  log,
+	}
+}
+
+// Run delivers the pending events every PollInterval until ctx is done
+func (r *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.cfg.Outbox.PollInterval * time.Second)
+	defer ticker.Stop()
+
+	var cleanedAt time.Time
+	for {
+		// full batches mean more events are pending
+		for {
+			pending, err := r.Deliver(ctx)
+			if err != nil && ctx.Err() == nil {
+				r.log.Errorf("outbox: failed to deliver events: %v", err)
+			}
+			if err != nil || pending < r.cfg.Outbox.BatchSize {
+				break
+			}
+		}
+
+		if time.Since(cleanedAt) >= CLEANUP_INTERVAL {
+			r.cleanup(ctx)
+			cleanedAt = time.Now()
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Deliver publishes one batch of pending events and returns how many were pending.
+// The batch is claimed for ClaimTimeout and published outside of any transaction, the events left when
+// the claim runs out are claimed again later. An event is marked delivered after the sink has it,
+// so a crash in between publishes it again.
+func (r *Relay) Deliver(ctx context.Context) (int, error) {
+	ctx, span := tracing.Start(ctx, "outboxRelay.Deliver")
+	defer span.End()
+
+	claimedUntil := time.Now().Add(r.cfg.Outbox.ClaimTimeout * time.Second)
+	events, err := r.repo.Claim(ctx, r.cfg.Outbox.BatchSize, claimedUntil)
+	if err != nil {
+		return 0, err
+	}
+
+	publishCtx, cancel := context.WithDeadline(ctx, claimedUntil)
+	defer cancel()
+
+	delivered := make([]int64, 0, len(events))
+	for i, event := range events {
+		if publishCtx.Err() != nil {
+			r.log.FromContext(ctx).Warnf("outbox: claim of %d events ran out, %d left", len(events), len(events)-i)
+			break
+		}
+
+		if err = r.sink.Publish(publishCtx, event); err != nil {
+			metrics.ObserveEvent(event.Type, "failed")
+			r.log.FromContext(ctx).Warnf("outbox: failed to publish event %d, attempt %d: %v", event.ID, event.Attempts+1, err)
+
+			// when it is not marked, the event is retried once its claim runs out
+			if err = r.repo.MarkFailed(ctx, event.ID, err.Error(), time.Now().Add(r.backoff(event))); err != nil {
+				r.log.FromContext(ctx).Errorf("outbox: failed to mark event %d failed: %v", event.ID, err)
+			}
+			continue
+		}
+
+		metrics.ObserveEvent(event.Type, "delivered")
+		delivered = append(delivered, event.ID)
+	}
+
+	return len(events), r.repo.MarkDelivered(ctx, delivered)
+}
+
+// backoff returns the wait before the next attempt of event, doubled after every failed attempt
+func (r *Relay) backoff(event *models.Event) time.Duration {
+	backoff := r.cfg.Outbox.RetryBackoff * time.Second
+	maxBackoff := r.cfg.Outbox.MaxRetryBackoff * time.Second
+
+	for i := 0; i < event.Attempts && backoff < maxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > maxBackoff {
+		backoff = maxBackoff
+	}
+
+	return backoff
+}
+
+// cleanup deletes the events delivered before the retention, none when it is 0
+func (r *Relay) cleanup(ctx context.Context) {
+	if r.cfg.Outbox.Retention <= 0 {
+		return
+	}
+
+	deleted, err := r.repo.DeleteDelivered(ctx, time.Now().Add(-r.cfg.Outbox.Retention*time.Second))
+	if err != nil {
+		if ctx.Err() == nil {
+			r.log.Errorf("outbox: failed to delete delivered events: %v", err)
+		}
+		return
+	}
+	if deleted > 0 {
+		r.log.Infof("outbox: deleted %d delivered events", deleted)
+	}
+}
@@ -0,0 +1,157 @@
+package relay
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/realtemirov/task-for-dell/config"
+	"github.com/realtemirov/task-for-dell/internal/models"
+	"github.com/realtemirov/task-for-dell/internal/outbox/mock"
+	"github.com/realtemirov/task-for-dell/pkg/logger"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// newRelay returns a relay on mocks of the outbox and the sink
+func newRelay(t *testing.T) (*Relay, *mock.MockRepository, *mock.MockSink) {
+	ctrl := gomock.NewController(t)
+	t.Cleanup(ctrl.Finish)
+
+	cfg := &config.Config{Outbox: config.OutboxConfig{
+		PollInterval:    1,
+		BatchSize:       10,
+		ClaimTimeout:    30,
+		RetryBackoff:    1,
+		MaxRetryBackoff: 60,
+		Retention:       3600,
+	}}
+	repo := mock.NewMockRepository(ctrl)
+	sink := mock.NewMockSink(ctrl)
+
+	return NewRelay(cfg, repo, sink, logger.NewApiLogger(nil)), repo, sink
+}
+
+func TestRelay_Deliver(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Deliver", func(t *testing.T) {
+		relay, repo, sink := newRelay(t)
+
+		events := []*models.Event{
+			{ID: 1, Type: models.EventContentCreated},
+			{ID: 2, Type: models.EventContentUpdated, Attempts: 3},
+			{ID: 3, Type: models.EventContentDeleted},
+		}
+		// claimed for 30s
+		before := time.Now()
+		repo.EXPECT().Claim(gomock.Any(), 10, gomock.Any()).DoAndReturn(
+			func(ctx context.Context, limit int, until time.Time) ([]*models.Event, error) {
+				require.WithinDuration(t, before.Add(30*time.Second), until, time.Second)
+				return events, nil
+			},
+		)
+		sink.EXPECT().Publish(gomock.Any(), events[0]).Return(nil)
+		sink.EXPECT().Publish(gomock.Any(), events[1]).Return(errors.New("unavailable"))
+		sink.EXPECT().Publish(gomock.Any(), events[2]).Return(nil)
+
+		// the failed event is retried after 1s doubled by its 3 attempts, the next one is published
+		repo.EXPECT().MarkFailed(gomock.Any(), int64(2), "unavailable", gomock.Any()).DoAndReturn(
+			func(ctx context.Context, id int64, reason string, retryAt time.Time) error {
+				require.WithinDuration(t, before.Add(8*time.Second), retryAt, time.Second)
+				return nil
+			},
+		)
+		repo.EXPECT().MarkDelivered(gomock.Any(), []int64{1, 3}).Return(nil)
+
+		pending, err := relay.Deliver(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, 3, pending)
+	})
+
+	t.Run("Deliver none", func(t *testing.T) {
+		relay, repo, _ := newRelay(t)
+
+		repo.EXPECT().Claim(gomock.Any(), 10, gomock.Any()).Return([]*models.Event{}, nil)
+		repo.EXPECT().MarkDelivered(gomock.Any(), []int64{}).Return(nil)
+
+		pending, err := relay.Deliver(context.Background())
+		require.NoError(t, err)
+		require.Zero(t, pending)
+	})
+
+	t.Run("Deliver claim ran out", func(t *testing.T) {
+		relay, repo, _ := newRelay(t)
+
+		events := []*models.Event{
+			{ID: 1, Type: models.EventContentCreated},
+			{ID: 2, Type: models.EventContentUpdated},
+		}
+		repo.EXPECT().Claim(gomock.Any(), 10, gomock.Any()).Return(events, nil)
+
+		// the claim runs out before they are published, they are claimed again later
+		relay.cfg.Outbox.ClaimTimeout = 0
+		repo.EXPECT().MarkDelivered(gomock.Any(), []int64{}).Return(nil)
+
+		pending, err := relay.Deliver(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, 2, pending)
+	})
+
+	t.Run("Deliver failed", func(t *testing.T) {
+		relay, repo, _ := newRelay(t)
+
+		repo.EXPECT().Claim(gomock.Any(), 10, gomock.Any()).Return(nil, errors.New("connection refused"))
+
+		_, err := relay.Deliver(context.Background())
+		require.Error(t, err)
+	})
+}
+
+func TestRelay_backoff(t *testing.T) {
+	t.Parallel()
+
+	relay, _, _ := newRelay(t)
+
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{0, time.Second},
+		{1, 2 * time.Second},
+		{5, 32 * time.Second},
+		{6, time.Minute},
+		{100, time.Minute},
+	}
+
+	for _, tt := range tests {
+		require.Equal(t, tt.want, relay.backoff(&models.Event{Attempts: tt.attempts}), "attempts %d", tt.attempts)
+	}
+}
+
+func TestRelay_cleanup(t *testing.T) {
+	t.Parallel()
+
+	t.Run("cleanup", func(t *testing.T) {
+		relay, repo, _ := newRelay(t)
+
+		before := time.Now().Add(-time.Hour)
+		repo.EXPECT().DeleteDelivered(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, deliveredBefore time.Time) (int64, error) {
+				require.WithinDuration(t, before, deliveredBefore, time.Second)
+				return 2, nil
+			},
+		)
+
+		relay.cleanup(context.Background())
+	})
+
+	t.Run("cleanup disabled", func(t *testing.T) {
+		relay, _, _ := newRelay(t)
+
+		// without retention the events are kept
+		relay.cfg.Outbox.Retention = 0
+		relay.cleanup(context.Background())
+	})
+}
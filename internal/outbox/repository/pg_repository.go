@@ -0,0 +1,176 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/realtemirov/task-for-dell/internal/models"
+	"github.com/realtemirov/task-for-dell/internal/outbox"
+	"github.com/realtemirov/task-for-dell/pkg/db/postgres"
+	"github.com/realtemirov/task-for-dell/pkg/metrics"
+	"github.com/realtemirov/task-for-dell/pkg/tracing"
+)
+
+type outboxRepo struct {
+	db *postgres.DB
+}
+
+// NewOutboxRepository constructor, writes run in the transaction of their ctx, see postgres.TxManager
+func NewOutboxRepository(db *postgres.DB) outbox.Repository {
+	return &outboxRepo{db: db}
+}
+
+// eventRow is an event as scanned, the driver may return the payload as text or bytes
+type eventRow struct {
+	models.Event
+	Payload []byte `db:"payload"`
+}
+
+// Add implements outbox.Repository.
+func (r *outboxRepo) Add(ctx context.Context, events ...*models.Event) error {
+	ctx, span := tracing.Start(ctx, "outboxRepo.Add")
+	defer span.End()
+	defer metrics.ObserveQuery("outbox", "Add", time.Now())
+
+	if len(events) == 0 {
+		return nil
+	}
+
+	args := make([]interface{}, 0, len(events)*5)
+	for _, event := range events {
+		args = append(args, event.Type, event.ContentType, event.ContentID, string(event.Payload), event.CreatedAt)
+	}
+
+	if _, err := r.db.Writer(ctx).ExecContext(
+		ctx,
+		insertManyQuery+postgres.Placeholders(len(events), 5),
+		args...,
+	); err != nil {
+		return errors.Wrap(postgres.MapError(err), "outboxRepo.Add.ExecContext")
+	}
+
+	return nil
+}
+
+// Claim implements outbox.Repository, the events are not pending again before until, nothing stays locked.
+func (r *outboxRepo) Claim(ctx context.Context, limit int, until time.Time) ([]*models.Event, error) {
+	ctx, span := tracing.Start(ctx, "outboxRepo.Claim")
+	defer span.End()
+	defer metrics.ObserveQuery("outbox", "Claim", time.Now())
+
+	rows := make([]*eventRow, 0, limit)
+	if err := r.db.Writer(ctx).SelectContext(ctx, &rows, claimQuery, limit, until); err != nil {
+		return nil, errors.Wrap(postgres.MapError(err), "outboxRepo.Claim.SelectContext")
+	}
+
+	events := make([]*models.Event, 0, len(rows))
+	for _, row := range rows {
+		event := row.Event
+		event.Payload = row.Payload
+		events = append(events, &event)
+	}
+
+	return events, nil
+}
+
+// MarkDelivered implements outbox.Repository.
+func (r *outboxRepo) MarkDelivered(ctx context.Context, ids []int64) error {
+	ctx, span := tracing.Start(ctx, "outboxRepo.MarkDelivered")
+	defer span.End()
+	defer metrics.ObserveQuery("outbox", "MarkDelivered", time.Now())
+
+	if len(ids) == 0 {
+		return nil
+	}
+
+	if _, err := r.db.Writer(ctx).ExecContext(ctx, markDeliveredQuery, postgres.Int64Array(ids)); err != nil {
+		return errors.Wrap(postgres.MapError(err), "outboxRepo.MarkDelivered.ExecContext")
+	}
+
+	return nil
+}
+
+// MarkFailed implements outbox.Repository.
+func (r *outboxRepo) MarkFailed(ctx context.Context, id int64, reason string, retryAt time.Time) error {
+	ctx, span := tracing.Start(ctx, "outboxRepo.MarkFailed")
+	defer span.End()
+	defer metrics.ObserveQuery("outbox", "MarkFailed", time.Now())
+
+	if _, err := r.db.Writer(ctx).ExecContext(ctx, markFailedQuery, id, reason, retryAt); err != nil {
+		return errors.Wrap(postgres.MapError(err), "outboxRepo.MarkFailed.ExecContext")
+	}
+
+	return nil
+}
+
+// DeleteDelivered implements outbox.Repository.
+func (r *outboxRepo) DeleteDelivered(ctx context.Context, before time.Time) (int64, error) {
+	ctx, span := tracing.Start(ctx, "outboxRepo.DeleteDelivered")
+	defer span.End()
+	defer metrics.ObserveQuery("outbox", "DeleteDelivered", time.Now())
+
+	result, err := r.db.Writer(ctx).ExecContext(ctx, deleteDeliveredQuery, before)
+	if err != nil {
+		return 0, errors.Wrap(postgres.MapError(err), "outboxRepo.DeleteDelivered.ExecContext")
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(postgres.MapError(err), "outboxRepo.DeleteDelivered.RowsAffected")
+	}
+
+	return deleted, nil
+}
+
+// GetByID implements outbox.Repository, on the primary as the event was just written.
+func (r *outboxRepo) GetByID(ctx context.Context, id int64) (*models.Event, error) {
+	ctx, span := tracing.Start(ctx, "outboxRepo.GetByID")
+	defer span.End()
+	defer metrics.ObserveQuery("outbox", "GetByID", time.Now())
+
+	row := &eventRow{}
+	if err := r.db.Writer(ctx).QueryRowxContext(ctx, getByIDQuery, id).StructScan(row); err != nil {
+		return nil, errors.Wrap(postgres.MapError(err), "outboxRepo.GetByID.StructScan")
+	}
+
+	event := row.Event
+	event.Payload = row.Payload
+
+	return &event, nil
+}
+
+// After implements outbox.Repository, on the primary so no event committed is skipped.
+func (r *outboxRepo) After(ctx context.Context, contentType string, afterID int64, limit int) ([]*models.Event, error) {
+	ctx, span := tracing.Start(ctx, "outboxRepo.After")
+	defer span.End()
+	defer metrics.ObserveQuery("outbox", "After", time.Now())
+
+	rows := make([]*eventRow, 0, limit)
+	if err := r.db.Writer(ctx).SelectContext(ctx, &rows, afterQuery, contentType, afterID, limit); err != nil {
+		return nil, errors.Wrap(postgres.MapError(err), "outboxRepo.After.SelectContext")
+	}
+
+	events := make([]*models.Event, 0, len(rows))
+	for _, row := range rows {
+		event := row.Event
+		event.Payload = row.Payload
+		events = append(events, &event)
+	}
+
+	return events, nil
+}
+
+// Lookback implements outbox.Repository, on the primary as After.
+func (r *outboxRepo) Lookback(ctx context.Context, contentType string, eventID int64, window time.Duration) (int64, error) {
+	ctx, span := tracing.Start(ctx, "outboxRepo.Lookback")
+	defer span.End()
+	defer metrics.ObserveQuery("outbox", "Lookback", time.Now())
+
+	var afterID int64
+	if err := r.db.Writer(ctx).GetContext(ctx, &afterID, lookbackQuery, contentType, eventID, window.Seconds()); err != nil {
+		return 0, errors.Wrap(postgres.MapError(err), "outboxRepo.Lookback.GetContext")
+	}
+
+	return afterID, nil
+}
@@ -0,0 +1,164 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/realtemirov/task-for-dell/internal/models"
+	"github.com/realtemirov/task-for-dell/pkg/db/postgres"
+	"github.com/stretchr/testify/require"
+)
+
+// newOutboxRepo returns the outbox repository on a mock db, and its db
+func newOutboxRepo(t *testing.T) (*postgres.DB, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	return postgres.NewDB(sqlx.NewDb(db, "sqlmock")), mock
+}
+
+// TestOutboxRepo_Add tests Add method.
+func TestOutboxRepo_Add(t *testing.T) {
+	t.Parallel()
+
+	db, mock := newOutboxRepo(t)
+	repo := NewOutboxRepository(db)
+
+	created, err := models.NewContentEvent(models.EventContentCreated, models.ContentTypeNews, 1, &models.New{ID: 1, Title: "title"})
+	require.NoError(t, err)
+	deleted, err := models.NewContentEvent(models.EventContentDeleted, models.ContentTypeBlog, 2, nil)
+	require.NoError(t, err)
+
+	// Add in the transaction of the change
+	t.Run("Add", func(t *testing.T) {
+
+		mock.ExpectBegin()
+		mock.ExpectExec(insertManyQuery+"($1, $2, $3, $4, $5), ($6, $7, $8, $9, $10)").WithArgs(
+			models.EventContentCreated, models.ContentTypeNews, int64(1), string(created.Payload), created.CreatedAt,
+			models.EventContentDeleted, models.ContentTypeBlog, int64(2), `{"id":2}`, deleted.CreatedAt,
+		).WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectCommit()
+
+		err := db.WithinTx(context.Background(), func(ctx context.Context) error {
+			return repo.Add(ctx, created, deleted)
+		})
+
+		require.NoError(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	// Add without events does nothing
+	t.Run("Add Empty", func(t *testing.T) {
+		require.NoError(t, repo.Add(context.Background()))
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+// TestOutboxRepo_Claim tests Claim, MarkDelivered and MarkFailed methods.
+func TestOutboxRepo_Claim(t *testing.T) {
+	t.Parallel()
+
+	db, mock := newOutboxRepo(t)
+	repo := NewOutboxRepository(db)
+	now := time.Now()
+	claimedUntil := now.Add(30 * time.Second)
+	retryAt := now.Add(time.Minute)
+
+	mock.ExpectQuery(claimQuery).WithArgs(10, claimedUntil).WillReturnRows(
+		sqlmock.NewRows(
+			[]string{"id", "event_type", "content_type", "content_id", "payload", "created_at", "attempts"},
+		).AddRow(
+			int64(1), models.EventContentCreated, models.ContentTypeNews, int64(1), `{"id":1}`, now, 0,
+		).AddRow(
+			int64(2), models.EventContentDeleted, models.ContentTypeNews, int64(1), []byte(`{"id":1}`), now, 2,
+		),
+	)
+	mock.ExpectExec(markFailedQuery).WithArgs(int64(2), "timeout", retryAt).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(markDeliveredQuery).WithArgs("{1}").WillReturnResult(sqlmock.NewResult(0, 1))
+
+	// no transaction, the events are claimed until the relay marks them
+	ctx := context.Background()
+	events, err := repo.Claim(ctx, 10, claimedUntil)
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	require.Equal(t, models.EventContentCreated, events[0].Type)
+	require.Equal(t, json.RawMessage(`{"id":1}`), events[0].Payload)
+	require.Equal(t, json.RawMessage(`{"id":1}`), events[1].Payload)
+	require.Equal(t, 2, events[1].Attempts)
+
+	require.NoError(t, repo.MarkFailed(ctx, 2, "timeout", retryAt))
+	require.NoError(t, repo.MarkDelivered(ctx, []int64{1}))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestOutboxRepo_DeleteDelivered tests DeleteDelivered method.
+func TestOutboxRepo_DeleteDelivered(t *testing.T) {
+	t.Parallel()
+
+	db, mock := newOutboxRepo(t)
+	repo := NewOutboxRepository(db)
+	before := time.Now().Add(-time.Hour)
+
+	mock.ExpectExec(deleteDeliveredQuery).WithArgs(before).WillReturnResult(sqlmock.NewResult(0, 3))
+
+	deleted, err := repo.DeleteDelivered(context.Background(), before)
+
+	require.NoError(t, err)
+	require.EqualValues(t, 3, deleted)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestOutboxRepo_After tests GetByID, After and Lookback methods.
+func TestOutboxRepo_After(t *testing.T) {
+	t.Parallel()
+
+	db, mock := newOutboxRepo(t)
+	repo := NewOutboxRepository(db)
+	now := time.Now()
+	columns := []string{"id", "event_type", "content_type", "content_id", "payload", "created_at", "attempts"}
+
+	t.Run("GetByID", func(t *testing.T) {
+		mock.ExpectQuery(getByIDQuery).WithArgs(int64(7)).WillReturnRows(
+			sqlmock.NewRows(columns).AddRow(int64(7), models.EventContentUpdated, models.ContentTypeBlog, int64(1), `{"id":1}`, now, 0),
+		)
+
+		event, err := repo.GetByID(context.Background(), 7)
+		require.NoError(t, err)
+		require.Equal(t, models.EventContentUpdated, event.Type)
+		require.Equal(t, json.RawMessage(`{"id":1}`), event.Payload)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("After", func(t *testing.T) {
+		mock.ExpectQuery(afterQuery).WithArgs(models.ContentTypeNews, int64(7), 2).WillReturnRows(
+			sqlmock.NewRows(columns).AddRow(
+				int64(8), models.EventContentCreated, models.ContentTypeNews, int64(2), `{"id":2}`, now, 0,
+			).AddRow(
+				int64(9), models.EventContentDeleted, models.ContentTypeNews, int64(2), `{"id":2}`, now, 0,
+			),
+		)
+
+		events, err := repo.After(context.Background(), models.ContentTypeNews, 7, 2)
+		require.NoError(t, err)
+		require.Len(t, events, 2)
+		require.EqualValues(t, 8, events[0].ID)
+		require.EqualValues(t, 9, events[1].ID)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Lookback", func(t *testing.T) {
+		mock.ExpectQuery(lookbackQuery).WithArgs(models.ContentTypeNews, int64(7), float64(60)).WillReturnRows(
+			sqlmock.NewRows([]string{"coalesce"}).AddRow(int64(4)),
+		)
+
+		afterID, err := repo.Lookback(context.Background(), models.ContentTypeNews, 7, time.Minute)
+		require.NoError(t, err)
+		require.EqualValues(t, 4, afterID)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,82 @@
+package repository
+
+var (
+
+	// list of fields of outbox events read by the relay.
+	fieldsOfOutboxTable = `id, event_type, content_type, content_id, payload, created_at, attempts`
+
+	// query prefix for insert many events, followed by one placeholder row per event.
+	insertManyQuery = `
+	INSERT INTO outbox
+	(
+		event_type,
+		content_type,
+		content_id,
+		payload,
+		created_at
+	)
+	VALUES `
+
+	// query for claim the pending events due until a time, in order, skipping the ones claimed meanwhile
+	// by another relay. Their next attempt is moved to that time, so they are due again if not marked by then.
+	claimQuery = `
+	WITH claimed AS (
+		UPDATE outbox SET
+			next_attempt_at = $2
+		WHERE id IN (
+			SELECT id
+			FROM outbox
+			WHERE
+				delivered_at IS NULL AND next_attempt_at <= CURRENT_TIMESTAMP
+			ORDER BY id
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + fieldsOfOutboxTable + `
+	)
+	SELECT
+		` + fieldsOfOutboxTable + `
+	FROM claimed
+	ORDER BY id`
+
+	// query for mark events delivered.
+	markDeliveredQuery = `UPDATE outbox SET delivered_at = CURRENT_TIMESTAMP WHERE id = ANY($1::bigint[])`
+
+	// query for record a failed delivery and when to retry it.
+	markFailedQuery = `
+	UPDATE outbox SET
+		attempts = attempts + 1,
+		last_error = $2,
+		next_attempt_at = $3
+	WHERE id = $1`
+
+	// query for delete the events delivered before a time.
+	deleteDeliveredQuery = `DELETE FROM outbox WHERE delivered_at < $1`
+
+	// query for get an event by id.
+	getByIDQuery = `
+	SELECT
+		` + fieldsOfOutboxTable + `
+	FROM outbox
+	WHERE id = $1`
+
+	// query for the events of a content type after an event, in order, delivered or not.
+	afterQuery = `
+	SELECT
+		` + fieldsOfOutboxTable + `
+	FROM outbox
+	WHERE
+		content_type = $1 AND id > $2
+	ORDER BY id
+	LIMIT $3`
+
+	// query for the id before the first event of a content type written within a window before an event,
+	// the event itself when none is or when it is gone.
+	lookbackQuery = `
+	SELECT
+		COALESCE(MIN(id) - 1, $2)
+	FROM outbox
+	WHERE
+		content_type = $1 AND id < $2 AND
+		created_at >= (SELECT created_at FROM outbox WHERE id = $2) - make_interval(secs => $3)`
+)
@@ -0,0 +1,12 @@
+package outbox
+
+import (
+	"context"
+
+	"github.com/realtemirov/task-for-dell/internal/models"
+)
+
+// Sink delivers the events of the outbox, an event whose Publish fails is published again later
+type Sink interface {
+	Publish(ctx context.Context, event *models.Event) error
+}
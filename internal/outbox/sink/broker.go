@@ -0,0 +1,50 @@
+package sink
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/realtemirov/task-for-dell/internal/models"
+	"github.com/realtemirov/task-for-dell/internal/outbox"
+)
+
+// Publisher publishes messages to a subject of a broker. *nats.Conn implements it,
+// a Kafka producer implements it with the subject as topic.
+type Publisher interface {
+	Publish(subject string, data []byte) error
+
+	// Flush returns once the broker received every message published
+	Flush() error
+}
+
+// brokerSink publishes every event as JSON to <prefix>.<content type>.<action>, e.g. task-for-dell.news.created
+type brokerSink struct {
+	publisher Publisher
+	prefix    string
+}
+
+// NewBrokerSink constructor
+func NewBrokerSink(publisher Publisher, prefix string) outbox.Sink {
+	return &brokerSink{publisher: publisher, prefix: prefix}
+}
+
+// Publish implements outbox.Sink.
+func (s *brokerSink) Publish(ctx context.Context, event *models.Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	if err = s.publisher.Publish(s.subject(event), data); err != nil {
+		return err
+	}
+
+	// an event is delivered once the broker has it, not once it is buffered
+	return s.publisher.Flush()
+}
+
+// subject returns the subject of event
+func (s *brokerSink) subject(event *models.Event) string {
+	return s.prefix + "." + event.ContentType + "." + strings.TrimPrefix(event.Type, "content.")
+}
@@ -0,0 +1,25 @@
+package sink
+
+import (
+	"context"
+
+	"github.com/realtemirov/task-for-dell/internal/models"
+	"github.com/realtemirov/task-for-dell/internal/outbox"
+	"github.com/realtemirov/task-for-dell/pkg/logger"
+)
+
+// logSink writes every event to the log, for development and as a default
+type logSink struct {
+	log logger.Logger
+}
+
+// NewLogSink constructor
+func NewLogSink(log logger.Logger) outbox.Sink {
+	return &logSink{log: log}
+}
+
+// Publish implements outbox.Sink.
+func (s *logSink) Publish(ctx context.Context, event *models.Event) error {
+	s.log.FromContext(ctx).Infof("outbox: event %d %s of %s %d: %s", event.ID, event.Type, event.ContentType, event.ContentID, event.Payload)
+	return nil
+}
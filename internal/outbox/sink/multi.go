@@ -0,0 +1,32 @@
+package sink
+
+import (
+	"context"
+	"errors"
+
+	"github.com/realtemirov/task-for-dell/internal/models"
+	"github.com/realtemirov/task-for-dell/internal/outbox"
+)
+
+// multiSink publishes every event to each of its sinks. An event failing in one of them is retried
+// in all of them, which receive it at least once as any sink does.
+type multiSink struct {
+	sinks []outbox.Sink
+}
+
+// NewMultiSink constructor
+func NewMultiSink(sinks ...outbox.Sink) outbox.Sink {
+	return &multiSink{sinks: sinks}
+}
+
+// Publish implements outbox.Sink.
+func (s *multiSink) Publish(ctx context.Context, event *models.Event) error {
+	var errs []error
+	for _, sink := range s.sinks {
+		if err := sink.Publish(ctx, event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
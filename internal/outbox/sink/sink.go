@@ -0,0 +1,37 @@
+package sink
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/realtemirov/task-for-dell/config"
+	"github.com/realtemirov/task-for-dell/internal/outbox"
+	"github.com/realtemirov/task-for-dell/pkg/logger"
+)
+
+const (
+	SINK_LOG     = "log"
+	SINK_WEBHOOK = "webhook"
+	SINK_NATS    = "nats"
+)
+
+// NewSink constructs the sink of the config and the func closing its connections
+func NewSink(cfg *config.Config, log logger.Logger) (outbox.Sink, func(), error) {
+	switch cfg.Outbox.Sink {
+	case SINK_LOG:
+		return NewLogSink(log), func() {}, nil
+	case SINK_WEBHOOK:
+		client := &http.Client{Timeout: cfg.Outbox.WebhookTimeout * time.Second}
+		return NewWebhookSink(cfg.Outbox.WebhookURL, client), client.CloseIdleConnections, nil
+	case SINK_NATS:
+		conn, err := nats.Connect(cfg.Outbox.NatsURL, nats.Name(cfg.Tracing.ServiceName), nats.MaxReconnects(-1))
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to connect to nats: %w", err)
+		}
+		return NewBrokerSink(conn, cfg.Outbox.Subject), conn.Close, nil
+	default:
+		return nil, nil, fmt.Errorf("unknown outbox sink %q", cfg.Outbox.Sink)
+	}
+}
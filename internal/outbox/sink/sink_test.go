@@ -0,0 +1,121 @@
+package sink
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/realtemirov/task-for-dell/internal/models"
+	"github.com/stretchr/testify/require"
+)
+
+// publisher records the messages published
+type publisher struct {
+	subjects []string
+	messages [][]byte
+	err      error
+}
+
+func (p *publisher) Publish(subject string, data []byte) error {
+	if p.err != nil {
+		return p.err
+	}
+	p.subjects = append(p.subjects, subject)
+	p.messages = append(p.messages, data)
+	return nil
+}
+
+func (p *publisher) Flush() error {
+	return nil
+}
+
+func TestWebhookSink_Publish(t *testing.T) {
+	t.Parallel()
+
+	event := &models.Event{
+		ID:          7,
+		Type:        models.EventContentCreated,
+		ContentType: models.ContentTypeNews,
+		ContentID:   1,
+		Payload:     json.RawMessage(`{"id":1}`),
+	}
+
+	t.Run("Publish", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, http.MethodPost, r.Method)
+			require.Equal(t, "7", r.Header.Get(HEADER_EVENT_ID))
+			require.Equal(t, models.EventContentCreated, r.Header.Get(HEADER_EVENT_TYPE))
+
+			body, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+
+			var received models.Event
+			require.NoError(t, json.Unmarshal(body, &received))
+			require.Equal(t, event.ContentID, received.ContentID)
+			require.JSONEq(t, `{"id":1}`, string(received.Payload))
+
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer server.Close()
+
+		require.NoError(t, NewWebhookSink(server.URL, server.Client()).Publish(context.Background(), event))
+	})
+
+	t.Run("Publish failed", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		require.Error(t, NewWebhookSink(server.URL, server.Client()).Publish(context.Background(), event))
+	})
+}
+
+func TestBrokerSink_Publish(t *testing.T) {
+	t.Parallel()
+
+	event := &models.Event{ID: 7, Type: models.EventContentDeleted, ContentType: models.ContentTypeBlog, ContentID: 1}
+
+	t.Run("Publish", func(t *testing.T) {
+		p := &publisher{}
+
+		require.NoError(t, NewBrokerSink(p, "task-for-dell").Publish(context.Background(), event))
+		require.Equal(t, []string{"task-for-dell.blog.deleted"}, p.subjects)
+
+		var received models.Event
+		require.NoError(t, json.Unmarshal(p.messages[0], &received))
+		require.Equal(t, event.ID, received.ID)
+	})
+
+	t.Run("Publish failed", func(t *testing.T) {
+		p := &publisher{err: errors.New("disconnected")}
+
+		require.Error(t, NewBrokerSink(p, "task-for-dell").Publish(context.Background(), event))
+	})
+}
+
+func TestMultiSink_Publish(t *testing.T) {
+	t.Parallel()
+
+	event := &models.Event{ID: 7, Type: models.EventContentCreated, ContentType: models.ContentTypeNews, ContentID: 1}
+
+	t.Run("Publish", func(t *testing.T) {
+		first, second := &publisher{}, &publisher{}
+
+		require.NoError(t, NewMultiSink(NewBrokerSink(first, "a"), NewBrokerSink(second, "b")).Publish(context.Background(), event))
+		require.Equal(t, []string{"a.news.created"}, first.subjects)
+		require.Equal(t, []string{"b.news.created"}, second.subjects)
+	})
+
+	t.Run("Publish failed", func(t *testing.T) {
+		failed, second := &publisher{err: errors.New("disconnected")}, &publisher{}
+
+		// the other sinks still receive the event
+		require.Error(t, NewMultiSink(NewBrokerSink(failed, "a"), NewBrokerSink(second, "b")).Publish(context.Background(), event))
+		require.Equal(t, []string{"b.news.created"}, second.subjects)
+	})
+}
@@ -0,0 +1,61 @@
+package sink
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/realtemirov/task-for-dell/internal/models"
+	"github.com/realtemirov/task-for-dell/internal/outbox"
+)
+
+const (
+	HEADER_EVENT_ID   = "X-Event-ID"
+	HEADER_EVENT_TYPE = "X-Event-Type"
+)
+
+// webhookSink posts every event as JSON to one url, any status but 2xx is a failed delivery
+type webhookSink struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhookSink constructor, client bounds the time of a delivery with its timeout
+func NewWebhookSink(url string, client *http.Client) outbox.Sink {
+	return &webhookSink{url: url, client: client}
+}
+
+// Publish implements outbox.Sink.
+func (s *webhookSink) Publish(ctx context.Context, event *models.Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HEADER_EVENT_ID, strconv.FormatInt(event.ID, 10))
+	req.Header.Set(HEADER_EVENT_TYPE, event.Type)
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	// drain the body so the connection is reused
+	_, _ = io.Copy(io.Discard, res.Body)
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("webhook answered %s", res.Status)
+	}
+
+	return nil
+}
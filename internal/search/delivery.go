@@ -0,0 +1,7 @@
+package search
+
+import "github.com/labstack/echo/v4"
+
+type Handlers interface {
+	Suggest() echo.HandlerFunc
+}
@@ -0,0 +1,67 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	echo "github.com/labstack/echo/v4"
+	"github.com/realtemirov/task-for-dell/config"
+	"github.com/realtemirov/task-for-dell/internal/models"
+	"github.com/realtemirov/task-for-dell/internal/search"
+
+	"github.com/realtemirov/task-for-dell/pkg/httpErrors"
+	"github.com/realtemirov/task-for-dell/pkg/logger"
+)
+
+type searchHandlers struct {
+	cfg      *config.Config
+	searchUC search.UseCase
+	logger   logger.Logger
+}
+
+// NewSearchHandlers constructs a new searchHandlers.
+func NewSearchHandlers(cfg *config.Config, searchUC search.UseCase, logger logger.Logger) search.Handlers {
+	return &searchHandlers{
+		cfg:      cfg,
+		searchUC: searchUC,
+		logger:   logger,
+	}
+}
+
+// Suggest
+// @Summary Suggest
+// @Description Get titles of blogs and news starting with the typed prefix
+// @Tags Search
+// @Accept  json
+// @Produce  json
+// @Param q query string true "prefix"
+// @Param limit query int false "limit"
+// @Success 200 {object} models.SuggestionList
+// @Failure 400 {object} httpErrors.Problem
+// @Failure 500 {object} httpErrors.Problem
+// @Router /search/suggest [GET]
+func (h *searchHandlers) Suggest() echo.HandlerFunc {
+	return func(c echo.Context) error {
+
+		var (
+			err         error
+			limit       int
+			suggestions *models.SuggestionList = &models.SuggestionList{}
+		)
+
+		// limit is optional, usecase applies default and maximum
+		if limitQuery := c.QueryParam("limit"); limitQuery != "" {
+			limit, err = strconv.Atoi(limitQuery)
+			if err != nil {
+				return httpErrors.ErrResponseWithLog(c, h.logger, err)
+			}
+		}
+
+		suggestions, err = h.searchUC.Suggest(c.Request().Context(), c.QueryParam("q"), limit)
+		if err != nil {
+			return httpErrors.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, suggestions)
+	}
+}
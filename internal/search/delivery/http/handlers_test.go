@@ -0,0 +1,64 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/realtemirov/task-for-dell/config"
+	"github.com/realtemirov/task-for-dell/internal/models"
+	"github.com/realtemirov/task-for-dell/internal/search/mock"
+	"github.com/realtemirov/task-for-dell/pkg/logger"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestSearchHandlers_Suggest(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cfg, err := config.LoadConfig("./../../../../config/config-local")
+	require.NoError(t, err)
+
+	logger := logger.NewApiLogger(cfg)
+	logger.InitLogger()
+
+	mockSearchUC := mock.NewMockUseCase(ctrl)
+	searchHandler := NewSearchHandlers(cfg, mockSearchUC, logger)
+	handler := searchHandler.Suggest()
+
+	t.Run("Suggest succes case", func(t *testing.T) {
+		request := httptest.NewRequest(http.MethodGet, "/v1/search/suggest?q=pre&limit=5", nil)
+		response := httptest.NewRecorder()
+
+		e := echo.New()
+		echoCtx := e.NewContext(request, response)
+
+		mockSearchUC.EXPECT().Suggest(gomock.Any(), "pre", 5).Return(&models.SuggestionList{
+			Query: "pre",
+			Suggestions: []*models.Suggestion{
+				{Type: models.ContentTypeNews, ID: 1, Title: "Press release"},
+			},
+		}, nil)
+
+		err = handler(echoCtx)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, response.Code)
+		require.Contains(t, response.Body.String(), "Press release")
+	})
+
+	t.Run("Suggest Limit error case", func(t *testing.T) {
+		request := httptest.NewRequest(http.MethodGet, "/v1/search/suggest?q=pre&limit=test", nil)
+		response := httptest.NewRecorder()
+
+		e := echo.New()
+		echoCtx := e.NewContext(request, response)
+
+		err = handler(echoCtx)
+		require.NoError(t, err)
+		require.NotEqual(t, http.StatusOK, response.Code)
+	})
+}
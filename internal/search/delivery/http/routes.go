@@ -0,0 +1,11 @@
+package http
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/realtemirov/task-for-dell/internal/search"
+)
+
+// MapSearchRoutes maps routes for search
+func MapSearchRoutes(searchGroup *echo.Group, h search.Handlers) {
+	searchGroup.GET("/suggest", h.Suggest())
+}
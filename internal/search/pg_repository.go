@@ -0,0 +1,11 @@
+package search
+
+import (
+	"context"
+
+	"github.com/realtemirov/task-for-dell/internal/models"
+)
+
+type Repository interface {
+	Suggest(ctx context.Context, prefix string, limit int) ([]*models.Suggestion, error)
+}
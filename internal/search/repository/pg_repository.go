@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/realtemirov/task-for-dell/internal/models"
+	"github.com/realtemirov/task-for-dell/internal/search"
+	"github.com/realtemirov/task-for-dell/pkg/db/postgres"
+	"github.com/realtemirov/task-for-dell/pkg/metrics"
+	"github.com/realtemirov/task-for-dell/pkg/tracing"
+	"github.com/realtemirov/task-for-dell/pkg/utils"
+)
+
+type searchRepo struct {
+	db *postgres.DB
+}
+
+// NewSearchRepository constructor
+func NewSearchRepository(db *postgres.DB) search.Repository {
+	return &searchRepo{db: db}
+}
+
+// Suggest implements search.Repository.
+func (r *searchRepo) Suggest(ctx context.Context, prefix string, limit int) ([]*models.Suggestion, error) {
+	ctx, span := tracing.Start(ctx, "searchRepo.Suggest")
+	defer span.End()
+	defer metrics.ObserveQuery("search", "Suggest", time.Now())
+
+	// prefix pattern, matched case-insensitively
+	pattern := utils.EscapeLike(strings.ToLower(prefix)) + "%"
+
+	// suggestions for response
+	suggestions := make([]*models.Suggestion, 0, limit)
+
+	// select suggestions and scan result
+	if err := r.db.Reader(ctx).SelectContext(
+		ctx,
+		&suggestions,
+		suggestQuery,
+		pattern,
+		limit,
+	); err != nil {
+		return nil, errors.Wrap(postgres.MapError(err), "searchRepo.Suggest.SelectContext")
+	}
+
+	// if no error, return result
+	return suggestions, nil
+}
@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/realtemirov/task-for-dell/pkg/db/postgres"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSearchRepo_Suggest tests Suggest method.
+func TestSearchRepo_Suggest(t *testing.T) {
+	t.Parallel()
+
+	// create mock db
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer db.Close()
+
+	// create sqlx db with mock db
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	defer sqlxDB.Close()
+
+	// search repository
+	repo := NewSearchRepository(postgres.NewDB(sqlxDB))
+
+	// Suggest success case
+	t.Run("Suggest", func(t *testing.T) {
+
+		// mock rows
+		rows := sqlmock.NewRows(
+			[]string{"type", "id", "title"},
+		).AddRow(
+			"blog",
+			int64(1),
+			"Presidential election",
+		).AddRow(
+			"news",
+			int64(2),
+			"Press release",
+		)
+
+		// mock query with lower-cased prefix pattern
+		mock.ExpectQuery(suggestQuery).WithArgs(
+			"pre%",
+			10,
+		).WillReturnRows(rows)
+
+		// call Suggest method
+		suggestions, err := repo.Suggest(context.Background(), "Pre", 10)
+
+		// check error and result
+		require.NoError(t, err)
+		require.Len(t, suggestions, 2)
+		require.Equal(t, "blog", suggestions[0].Type)
+		require.Equal(t, "Press release", suggestions[1].Title)
+	})
+
+	// Suggest escapes LIKE wildcards
+	t.Run("Suggest Escape", func(t *testing.T) {
+
+		// mock query with escaped pattern
+		mock.ExpectQuery(suggestQuery).WithArgs(
+			`50\%\_%`,
+			10,
+		).WillReturnRows(sqlmock.NewRows([]string{"type", "id", "title"}))
+
+		// call Suggest method
+		suggestions, err := repo.Suggest(context.Background(), "50%_", 10)
+
+		// check error and result
+		require.NoError(t, err)
+		require.Len(t, suggestions, 0)
+	})
+
+	// Suggest error case
+	t.Run("Suggest Error", func(t *testing.T) {
+
+		// mock query and return error
+		mock.ExpectQuery(suggestQuery).WithArgs(
+			"pre%",
+			10,
+		).WillReturnError(sqlmock.ErrCancelled)
+
+		// call Suggest method
+		suggestions, err := repo.Suggest(context.Background(), "pre", 10)
+
+		// check error and result
+		require.Error(t, err)
+		require.Nil(t, suggestions)
+	})
+}
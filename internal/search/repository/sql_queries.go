@@ -0,0 +1,25 @@
+package repository
+
+var (
+
+	// query for get titles of blogs and news starting with a prefix,
+	// each branch can use the lower(title) text_pattern_ops index.
+	suggestQuery = `
+	(
+		SELECT 'blog' AS type, id, title
+		FROM blogs
+		WHERE lower(title) LIKE $1
+		ORDER BY lower(title)
+		LIMIT $2
+	)
+	UNION ALL
+	(
+		SELECT 'news' AS type, id, title
+		FROM news
+		WHERE lower(title) LIKE $1
+		ORDER BY lower(title)
+		LIMIT $2
+	)
+	ORDER BY title, type, id
+	LIMIT $2`
+)
@@ -0,0 +1,11 @@
+package search
+
+import (
+	"context"
+
+	"github.com/realtemirov/task-for-dell/internal/models"
+)
+
+type UseCase interface {
+	Suggest(ctx context.Context, prefix string, limit int) (*models.SuggestionList, error)
+}
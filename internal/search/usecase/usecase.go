@@ -0,0 +1,101 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/realtemirov/task-for-dell/config"
+	"github.com/realtemirov/task-for-dell/internal/models"
+	"github.com/realtemirov/task-for-dell/internal/search"
+	"github.com/realtemirov/task-for-dell/pkg/cache"
+	"github.com/realtemirov/task-for-dell/pkg/logger"
+	"github.com/realtemirov/task-for-dell/pkg/tracing"
+)
+
+const (
+	defaultSuggestLimit     = 10
+	maxSuggestLimit         = 50
+	defaultSuggestCacheSize = 1000
+	defaultSuggestCacheTTL  = 30 * time.Second
+)
+
+// Search Usecase
+type searchUC struct {
+	cfg   *config.Config
+	repo  search.Repository
+	log   logger.Logger
+	cache *cache.LRU
+}
+
+// Search UseCase contructor
+func NewSearchUseCase(cfg *config.Config, repo search.Repository, log logger.Logger) search.UseCase {
+
+	size, ttl := defaultSuggestCacheSize, defaultSuggestCacheTTL
+	if cfg != nil && cfg.Search.SuggestCacheSize > 0 {
+		size = cfg.Search.SuggestCacheSize
+	}
+	if cfg != nil && cfg.Search.SuggestCacheTTL > 0 {
+		ttl = cfg.Search.SuggestCacheTTL * time.Second
+	}
+
+	return &searchUC{
+		cfg:   cfg,
+		repo:  repo,
+		log:   log,
+		cache: cache.NewLRU(size, ttl),
+	}
+}
+
+// Suggest implements search.UseCase.
+func (u *searchUC) Suggest(ctx context.Context, prefix string, limit int) (*models.SuggestionList, error) {
+	ctx, span := tracing.Start(ctx, "searchUC.Suggest")
+	defer span.End()
+
+	// normalize prefix, hot prefixes share one cache entry regardless of case
+	prefix = strings.ToLower(strings.TrimSpace(prefix))
+	limit = u.getLimit(limit)
+
+	// nothing to suggest for an empty prefix
+	if prefix == "" {
+		return &models.SuggestionList{
+			Query:       prefix,
+			Suggestions: make([]*models.Suggestion, 0),
+		}, nil
+	}
+
+	// serve hot prefixes from memory
+	key := fmt.Sprintf("%d:%s", limit, prefix)
+	if cached, ok := u.cache.Get(key); ok {
+		return cached.(*models.SuggestionList), nil
+	}
+
+	suggestions, err := u.repo.Suggest(ctx, prefix, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.SuggestionList{
+		Query:       prefix,
+		Suggestions: suggestions,
+	}
+	u.cache.Set(key, result)
+
+	return result, nil
+}
+
+// getLimit returns the requested limit, bounded and defaulted from config
+func (u *searchUC) getLimit(limit int) int {
+	if limit <= 0 {
+		if u.cfg != nil && u.cfg.Search.SuggestLimit > 0 {
+			return u.cfg.Search.SuggestLimit
+		}
+		return defaultSuggestLimit
+	}
+	if limit > maxSuggestLimit {
+		return maxSuggestLimit
+	}
+
+	return limit
+}
@@ -0,0 +1,105 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/realtemirov/task-for-dell/config"
+	"github.com/realtemirov/task-for-dell/internal/models"
+	"github.com/realtemirov/task-for-dell/internal/search/mock"
+	"github.com/realtemirov/task-for-dell/pkg/logger"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestSearchUC_Suggest(t *testing.T) {
+	t.Parallel()
+
+	// Create a new instance of the gomock controller
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// config with suggestion limit
+	cfg := &config.Config{
+		Search: config.SearchConfig{
+			SuggestLimit: 5,
+		},
+	}
+
+	// logger, repository, usecase of search
+	logger := logger.NewApiLogger(cfg)
+	mockSearchRepo := mock.NewMockRepository(ctrl)
+	searchUC := NewSearchUseCase(cfg, mockSearchRepo, logger)
+
+	// suggestions and context
+	suggestions := []*models.Suggestion{
+		{Type: models.ContentTypeBlog, ID: 1, Title: "Presidential election"},
+	}
+	ctx := context.Background()
+
+	// mock the Suggest method of the repository once, second call is cached
+	mockSearchRepo.EXPECT().Suggest(
+		ctx,
+		"pre",
+		5,
+	).Return(suggestions, nil).Times(1)
+
+	// call the Suggest method of the usecase, prefix is normalized
+	result, err := searchUC.Suggest(ctx, " Pre ", 0)
+	require.NoError(t, err)
+	require.Equal(t, "pre", result.Query)
+	require.Len(t, result.Suggestions, 1)
+
+	// call again, served from cache
+	result, err = searchUC.Suggest(ctx, "pre", 0)
+	require.NoError(t, err)
+	require.Len(t, result.Suggestions, 1)
+}
+
+func TestSearchUC_SuggestEmpty(t *testing.T) {
+	t.Parallel()
+
+	// Create a new instance of the gomock controller
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// logger, repository, usecase of search
+	logger := logger.NewApiLogger(nil)
+	mockSearchRepo := mock.NewMockRepository(ctrl)
+	searchUC := NewSearchUseCase(nil, mockSearchRepo, logger)
+
+	// call the Suggest method of the usecase, empty prefix does not hit repository
+	result, err := searchUC.Suggest(context.Background(), "  ", 0)
+
+	// check the result
+	require.NoError(t, err)
+	require.Len(t, result.Suggestions, 0)
+}
+
+func TestSearchUC_SuggestError(t *testing.T) {
+	t.Parallel()
+
+	// Create a new instance of the gomock controller
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// logger, repository, usecase of search
+	logger := logger.NewApiLogger(nil)
+	mockSearchRepo := mock.NewMockRepository(ctrl)
+	searchUC := NewSearchUseCase(nil, mockSearchRepo, logger)
+
+	// mock the Suggest method of the repository, limit is bounded
+	mockSearchRepo.EXPECT().Suggest(
+		gomock.Any(),
+		"pre",
+		maxSuggestLimit,
+	).Return(nil, errors.New("db error"))
+
+	// call the Suggest method of the usecase
+	result, err := searchUC.Suggest(context.Background(), "pre", 1000)
+
+	// check the result
+	require.Error(t, err)
+	require.Nil(t, result)
+}
@@ -6,11 +6,11 @@ import (
 	"os"
 	"os/signal"
 	"strings"
+	"sync/atomic"
 	"syscall"
 	"time"
 
 	"github.com/go-playground/validator/v10"
-	"github.com/jmoiron/sqlx"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 	"github.com/realtemirov/task-for-dell/config"
@@ -18,11 +18,39 @@ import (
 	blogHttpV1 "github.com/realtemirov/task-for-dell/internal/blogs/delivery/http"
 	blogRepo "github.com/realtemirov/task-for-dell/internal/blogs/repository"
 	blogUseCase "github.com/realtemirov/task-for-dell/internal/blogs/usecase"
+	"github.com/realtemirov/task-for-dell/internal/models"
+	"github.com/realtemirov/task-for-dell/internal/stream"
 
 	newsHttpV1 "github.com/realtemirov/task-for-dell/internal/news/delivery/http"
 	newsRepo "github.com/realtemirov/task-for-dell/internal/news/repository"
 	newsUseCase "github.com/realtemirov/task-for-dell/internal/news/usecase"
+	outboxRepo "github.com/realtemirov/task-for-dell/internal/outbox/repository"
+
+	searchHttpV1 "github.com/realtemirov/task-for-dell/internal/search/delivery/http"
+	searchRepo "github.com/realtemirov/task-for-dell/internal/search/repository"
+	searchUseCase "github.com/realtemirov/task-for-dell/internal/search/usecase"
+
+	translationsHttpV1 "github.com/realtemirov/task-for-dell/internal/translations/delivery/http"
+	translationsRepo "github.com/realtemirov/task-for-dell/internal/translations/repository"
+	translationsUseCase "github.com/realtemirov/task-for-dell/internal/translations/usecase"
+
+	streamHttpV1 "github.com/realtemirov/task-for-dell/internal/stream/delivery/http"
+	"github.com/realtemirov/task-for-dell/internal/stream/listener"
+	streamUseCase "github.com/realtemirov/task-for-dell/internal/stream/usecase"
+
+	webhooksHttpV1 "github.com/realtemirov/task-for-dell/internal/webhooks/delivery/http"
+	webhooksRepo "github.com/realtemirov/task-for-dell/internal/webhooks/repository"
+	webhooksUseCase "github.com/realtemirov/task-for-dell/internal/webhooks/usecase"
+	"github.com/realtemirov/task-for-dell/pkg/cache"
+	"github.com/realtemirov/task-for-dell/pkg/db/migrate"
+	"github.com/realtemirov/task-for-dell/pkg/db/postgres"
+	"github.com/realtemirov/task-for-dell/pkg/domainErrors"
+	"github.com/realtemirov/task-for-dell/pkg/health"
+	"github.com/realtemirov/task-for-dell/pkg/httpErrors"
 	"github.com/realtemirov/task-for-dell/pkg/logger"
+	"github.com/realtemirov/task-for-dell/pkg/metrics"
+	"github.com/realtemirov/task-for-dell/pkg/tracing"
+	"github.com/realtemirov/task-for-dell/pkg/utils"
 	echoSwagger "github.com/swaggo/echo-swagger"
 )
 
@@ -36,18 +64,61 @@ const (
 type server struct {
 	cfg     *config.Config
 	log     logger.Logger
-	psql    *sqlx.DB
+	psql    *postgres.DB
+	cache   cache.Cache
 	echo    *echo.Echo
 	validat *validator.Validate
+	health  *health.Health
+
+	// streams of content events, fed by the listener while the server runs
+	streamUC stream.UseCase
+
+	// settings swapped by Reload while requests are served
+	allowOrigins atomic.Pointer[[]string]
+	suggestions  atomic.Bool
 }
 
-func NewServer(cfg *config.Config, log logger.Logger, psql *sqlx.DB) *server {
-	return &server{
+// NewServer constructs the server, a nil cache reads blogs and news from the database only
+func NewServer(cfg *config.Config, log logger.Logger, psql *postgres.DB, readCache cache.Cache) *server {
+	s := &server{
 		cfg:     cfg,
 		log:     log,
 		psql:    psql,
+		cache:   readCache,
 		echo:    echo.New(),
 		validat: validator.New(),
+		health:  health.NewHealth(cfg.Server.ProbeTimeout * time.Second),
+	}
+	s.Reload(cfg)
+
+	return s
+}
+
+// Reload applies the reloadable settings of the server, see config.Reloader
+func (s *server) Reload(cfg *config.Config) {
+	allowOrigins := cfg.Server.AllowOrigins
+	s.allowOrigins.Store(&allowOrigins)
+	s.suggestions.Store(cfg.Features.Suggestions)
+}
+
+// allowOrigin reports whether the origin is one of the allowed origins, any origin is allowed by "*"
+func (s *server) allowOrigin(origin string) (bool, error) {
+	for _, allowed := range *s.allowOrigins.Load() {
+		if allowed == "*" || allowed == origin {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// suggestionsEnabled answers 404 while the suggestions feature is off
+func (s *server) suggestionsEnabled(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if !s.suggestions.Load() {
+			return httpErrors.ErrResponseWithLog(c, s.log, domainErrors.NotFound("search suggestions are disabled", nil))
+		}
+		return next(c)
 	}
 }
 
@@ -62,9 +133,27 @@ func (s *server) Run() error {
 		Handler:        s.echo,
 	}
 
+	// metrics have their own listener, so they are not exposed with the api
+	metricsServer := metrics.NewServer(s.cfg.Server.MetricsAddr)
+	if err := metrics.RegisterDB(s.psql.DB.DB, s.cfg.Postgres.DBName); err != nil {
+		return err
+	}
+	for addr, replica := range s.psql.Replicas() {
+		if err := metrics.RegisterDB(replica.DB, s.cfg.Postgres.DBName+"@"+addr); err != nil {
+			return err
+		}
+	}
+
+	go func() {
+		s.log.Infof("Metrics are listening on PORT: %s", s.cfg.Server.MetricsAddr)
+		if err := metricsServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			s.log.Errorf("Error starting Metrics Server: %v", err)
+		}
+	}()
+
 	go func() {
 		s.log.Infof("Server is listening on PORT: %s", s.cfg.Server.Port)
-		if err := s.echo.StartServer(server); err != nil {
+		if err := s.echo.StartServer(server); err != nil && err != http.ErrServerClosed {
 			s.log.Fatalf("Error starting Server: ", err)
 		}
 	}()
@@ -73,14 +162,30 @@ func (s *server) Run() error {
 		return err
 	}
 
+	listenCtx, stopListen := context.WithCancel(context.Background())
+	defer stopListen()
+	go listener.NewListener(s.cfg, s.streamUC, s.log).Run(listenCtx)
+
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
 
 	<-quit
 
+	// readiness fails while requests in flight finish, so no new traffic is routed here
+	s.health.SetDraining()
+	s.log.Infof("Server is draining for %s", s.cfg.Server.DrainTime*time.Second)
+	time.Sleep(s.cfg.Server.DrainTime * time.Second)
+
+	// shutdown waits for the streams, they end with the listener
+	stopListen()
+
 	ctx, shutdown := context.WithTimeout(context.Background(), s.cfg.Server.CtxDefaultTime*time.Second)
 	defer shutdown()
 
+	if err := metricsServer.Shutdown(ctx); err != nil {
+		s.log.Errorf("Error shutting down Metrics Server: %v", err)
+	}
+
 	s.log.Info("Server Exited Properly")
 	return s.echo.Server.Shutdown(ctx)
 }
@@ -101,9 +206,30 @@ func (s *server) MapHandlers(e *echo.Echo) error {
 	docs.SwaggerInfo.BasePath = "/v1"
 
 	e.GET("/swagger/*", echoSwagger.WrapHandler)
+
+	// probes of kubernetes, readiness needs the database with every migration applied
+	s.health.AddCheck("postgres", func(ctx context.Context) error {
+		return s.psql.PingContext(ctx)
+	})
+	s.health.AddDetailedCheck("migrations", func(ctx context.Context) (interface{}, error) {
+		status, err := migrate.GetStatus(ctx, s.psql.DB)
+		if err != nil {
+			return status, err
+		}
+		return status, status.Check()
+	})
+	e.GET("/healthz", s.health.Liveness)
+	e.GET("/readyz", s.health.Readiness)
+
+	// span per request, continuing the trace of the traceparent header
+	e.Use(tracing.Middleware())
+
+	// count and latency of requests by route and status
+	e.Use(metrics.Middleware())
+
 	e.Use(middleware.CORSWithConfig(middleware.CORSConfig{
-		AllowOrigins: []string{"*"},
-		AllowHeaders: []string{echo.HeaderOrigin, echo.HeaderContentType, echo.HeaderAccept, echo.HeaderXRequestID},
+		AllowOriginFunc: s.allowOrigin,
+		AllowHeaders:    []string{echo.HeaderOrigin, echo.HeaderContentType, echo.HeaderAccept, echo.HeaderXRequestID},
 	}))
 	e.Use(middleware.RecoverWithConfig(middleware.RecoverConfig{
 		StackSize:         STACK_SIZE,
@@ -112,6 +238,18 @@ func (s *server) MapHandlers(e *echo.Echo) error {
 	}))
 	e.Use(middleware.RequestID())
 
+	// request ID in every log line of the request, and one access log line per request
+	e.Use(logger.Middleware(s.log))
+
+	// reads after a write of the client go to the primary instead of a replica
+	e.Use(postgres.ReadYourWrites(s.cfg.Postgres.ReadYourWritesWindow * time.Second))
+
+	// locale of error and validation messages, from Accept-Language
+	e.Use(utils.Locale())
+
+	// keeps write deadlines of streaming responses reachable behind gzip
+	e.Use(utils.ResponseController())
+
 	e.Use(middleware.GzipWithConfig(middleware.GzipConfig{
 		Level: GZIP_LEVEL,
 		Skipper: func(c echo.Context) bool {
@@ -119,21 +257,67 @@ func (s *server) MapHandlers(e *echo.Echo) error {
 		},
 	}))
 	e.Use(middleware.Secure())
-	e.Use(middleware.BodyLimit(BODY_LIMIT))
+	e.Use(middleware.BodyLimitWithConfig(middleware.BodyLimitConfig{
+		Limit: BODY_LIMIT,
+		// imports have their own, larger, limit on the route
+		Skipper: func(c echo.Context) bool {
+			return strings.HasSuffix(c.Request().URL.Path, "/import")
+		},
+	}))
 
 	v1 := s.echo.Group("/v1")
 
+	// translations of blogs and news
+	translationsPGRepo := translationsRepo.NewTranslationsRepository(s.psql)
+	translationsUC := translationsUseCase.NewTranslationsUseCase(s.cfg, translationsPGRepo, s.log)
+	translationsHandler := translationsHttpV1.NewTranslationsHandlers(s.cfg, translationsUC, s.log)
+
+	// events of blogs and news changes, written in the transaction of the change
+	outboxPGRepo := outboxRepo.NewOutboxRepository(s.psql)
+
+	// streams of the events of every instance, notified by postgres
+	s.streamUC = streamUseCase.NewStreamUseCase(s.cfg, outboxPGRepo, s.log)
+	streamHandler := streamHttpV1.NewStreamHandlers(s.cfg, s.streamUC, s.log)
+
 	// blogs
 	blogPGRepo := blogRepo.NewBlogsRepository(s.psql)
 	blogUC := blogUseCase.NewBlogUseCase(s.cfg, blogPGRepo, s.log)
+	blogUC = blogUseCase.NewEventsBlogUseCase(blogUC, s.psql, outboxPGRepo)
+	if s.cache != nil {
+		blogUC = blogUseCase.NewCachedBlogUseCase(blogUC, s.cache, s.cfg.Cache.TTL*time.Second, s.log)
+	}
+	blogUC = blogUseCase.NewLocalizedBlogUseCase(blogUC, translationsUC)
 	blogHandler := blogHttpV1.NewBlogsHandlers(s.cfg, blogUC, s.log)
-	blogHttpV1.MapBlogsRoutes(v1.Group("/blogs"), blogHandler)
+	blogGroup := v1.Group("/blogs")
+	blogHttpV1.MapBlogsRoutes(blogGroup, blogHandler, s.cfg)
+	translationsHttpV1.MapTranslationsRoutes(blogGroup, models.ContentTypeBlog, translationsHandler)
+	streamHttpV1.MapStreamRoutes(blogGroup, models.ContentTypeBlog, streamHandler)
 
 	// news
 	newsPGRepo := newsRepo.NewNewsRepository(s.psql)
 	newsUC := newsUseCase.NewNewsUseCase(s.cfg, newsPGRepo, s.log)
+	newsUC = newsUseCase.NewEventsNewsUseCase(newsUC, s.psql, outboxPGRepo)
+	if s.cache != nil {
+		newsUC = newsUseCase.NewCachedNewsUseCase(newsUC, s.cache, s.cfg.Cache.TTL*time.Second, s.log)
+	}
+	newsUC = newsUseCase.NewLocalizedNewsUseCase(newsUC, translationsUC)
 	newsHandler := newsHttpV1.NewNewsHandlers(s.cfg, newsUC, s.log)
-	newsHttpV1.MapNewsRoutes(v1.Group("/news"), newsHandler)
+	newsGroup := v1.Group("/news")
+	newsHttpV1.MapNewsRoutes(newsGroup, newsHandler, s.cfg)
+	translationsHttpV1.MapTranslationsRoutes(newsGroup, models.ContentTypeNews, translationsHandler)
+	streamHttpV1.MapStreamRoutes(newsGroup, models.ContentTypeNews, streamHandler)
+
+	// search
+	searchPGRepo := searchRepo.NewSearchRepository(s.psql)
+	searchUC := searchUseCase.NewSearchUseCase(s.cfg, searchPGRepo, s.log)
+	searchHandler := searchHttpV1.NewSearchHandlers(s.cfg, searchUC, s.log)
+	searchHttpV1.MapSearchRoutes(v1.Group("/search", s.suggestionsEnabled), searchHandler)
+
+	// webhook subscriptions to content events
+	webhooksPGRepo := webhooksRepo.NewWebhooksRepository(s.psql)
+	webhooksUC := webhooksUseCase.NewWebhooksUseCase(s.cfg, webhooksPGRepo, s.log)
+	webhooksHandler := webhooksHttpV1.NewWebhooksHandlers(s.cfg, webhooksUC, s.log)
+	webhooksHttpV1.MapWebhooksRoutes(v1.Group("/webhooks"), webhooksHandler)
 
 	v1.GET("/ping", func(c echo.Context) error {
 		return c.JSON(http.StatusOK, map[string]string{
@@ -0,0 +1,7 @@
+package stream
+
+import "github.com/labstack/echo/v4"
+
+type Handlers interface {
+	Stream(contentType string) echo.HandlerFunc
+}
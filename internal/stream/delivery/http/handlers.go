@@ -0,0 +1,157 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	echo "github.com/labstack/echo/v4"
+	"github.com/realtemirov/task-for-dell/config"
+	"github.com/realtemirov/task-for-dell/internal/models"
+	"github.com/realtemirov/task-for-dell/internal/stream"
+
+	"github.com/realtemirov/task-for-dell/pkg/httpErrors"
+	"github.com/realtemirov/task-for-dell/pkg/logger"
+	"github.com/realtemirov/task-for-dell/pkg/utils"
+)
+
+const (
+	// HEADER_LAST_EVENT_ID is sent by EventSource when it reconnects, with the id of the last event it received
+	HEADER_LAST_EVENT_ID = "Last-Event-ID"
+
+	// MIME_EVENT_STREAM is the content type of server-sent events
+	MIME_EVENT_STREAM = "text/event-stream"
+
+	// HEARTBEAT is a comment line, ignored by EventSource
+	HEARTBEAT = ": heartbeat\n\n"
+)
+
+type streamHandlers struct {
+	cfg      *config.Config
+	streamUC stream.UseCase
+	logger   logger.Logger
+}
+
+// NewStreamHandlers constructs a new streamHandlers.
+func NewStreamHandlers(cfg *config.Config, streamUC stream.UseCase, logger logger.Logger) stream.Handlers {
+	return &streamHandlers{
+		cfg:      cfg,
+		streamUC: streamUC,
+		logger:   logger,
+	}
+}
+
+// Stream
+// @Summary Stream content events
+// @Description Server-sent events of the blogs or news created, updated and deleted, the event id resumes the stream from the Last-Event-ID header or the last_event_id query param
+// @Tags Stream
+// @Produce  text/event-stream
+// @Param Last-Event-ID header int false "id of the last event received"
+// @Param last_event_id query int false "id of the last event received, when the header can not be set"
+// @Success 200 {object} models.Event
+// @Failure 400 {object} httpErrors.Problem
+// @Router /blogs/stream [GET]
+// @Router /news/stream [GET]
+func (h *streamHandlers) Stream(contentType string) echo.HandlerFunc {
+	return func(c echo.Context) error {
+
+		var (
+			err         error
+			lastEventID int64
+			ctx         = c.Request().Context()
+			response    = c.Response()
+		)
+
+		lastEventID, err = getLastEventID(c)
+		if err != nil {
+			return httpErrors.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		// subscribed before the replay, so no event is lost in between
+		events := h.streamUC.Subscribe(ctx, contentType)
+
+		// the stream outlives the server write timeout
+		if err = utils.SetWriteDeadline(c, time.Time{}); err != nil {
+			h.logger.FromContext(ctx).Warnf("streamHandlers.Stream.SetWriteDeadline: %v", err)
+		}
+
+		response.Header().Set(echo.HeaderContentType, MIME_EVENT_STREAM)
+		response.Header().Set(echo.HeaderCacheControl, "no-cache")
+		response.Header().Set(echo.HeaderConnection, "keep-alive")
+		response.Header().Set("X-Accel-Buffering", "no")
+		response.WriteHeader(http.StatusOK)
+
+		// errors end the stream instead of answering an error, so EventSource reconnects
+		if _, err = fmt.Fprintf(response, "retry: %d\n\n", (h.cfg.Stream.Retry * time.Second).Milliseconds()); err != nil {
+			return nil
+		}
+		response.Flush()
+
+		sent := make(map[int64]struct{})
+		if lastEventID > 0 {
+			err = h.streamUC.Replay(ctx, contentType, lastEventID, func(event *models.Event) error {
+				sent[event.ID] = struct{}{}
+				return writeEvent(response, event)
+			})
+			if err != nil {
+				h.logger.FromContext(ctx).Errorf("streamHandlers.Stream.Replay, LastEventID: %d, Error: %s", lastEventID, err)
+				return nil
+			}
+		}
+
+		// gzip holds small writes back, every flush sends them
+		heartbeat := time.NewTicker(h.cfg.Stream.Heartbeat * time.Second)
+		defer heartbeat.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+			case event, ok := <-events:
+				if !ok {
+					return nil
+				}
+				if _, replayed := sent[event.ID]; replayed {
+					continue
+				}
+				if err = writeEvent(response, event); err != nil {
+					return nil
+				}
+			case <-heartbeat.C:
+				if _, err = response.Write([]byte(HEARTBEAT)); err != nil {
+					return nil
+				}
+				response.Flush()
+			}
+		}
+	}
+}
+
+// getLastEventID returns the id of the Last-Event-ID header or last_event_id query param, 0 when none is sent
+func getLastEventID(c echo.Context) (int64, error) {
+	lastEventID := c.Request().Header.Get(HEADER_LAST_EVENT_ID)
+	if lastEventID == "" {
+		lastEventID = c.QueryParam("last_event_id")
+	}
+	if lastEventID == "" {
+		return 0, nil
+	}
+
+	return utils.StringToInt64(lastEventID)
+}
+
+// writeEvent sends the event named by its type, with its id so the client can resume after it
+func writeEvent(response *echo.Response, event *models.Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	if _, err = fmt.Fprintf(response, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data); err != nil {
+		return err
+	}
+	response.Flush()
+
+	return nil
+}
@@ -0,0 +1,142 @@
+package http
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/realtemirov/task-for-dell/config"
+	"github.com/realtemirov/task-for-dell/internal/models"
+	"github.com/realtemirov/task-for-dell/internal/outbox/mock"
+	"github.com/realtemirov/task-for-dell/internal/stream"
+	"github.com/realtemirov/task-for-dell/internal/stream/usecase"
+	"github.com/realtemirov/task-for-dell/pkg/logger"
+	"github.com/realtemirov/task-for-dell/pkg/utils"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// newStreamServer returns a server of the news stream behind gzip, its usecase on a mock outbox repository
+func newStreamServer(t *testing.T) (*httptest.Server, stream.UseCase, *mock.MockRepository) {
+	ctrl := gomock.NewController(t)
+	t.Cleanup(ctrl.Finish)
+
+	cfg := &config.Config{Stream: config.StreamConfig{Heartbeat: 1, Retry: 3, BufferSize: 10, ReplayBatch: 10, ReplayLookback: 60}}
+	logger := logger.NewApiLogger(cfg)
+	logger.InitLogger()
+
+	mockOutboxRepo := mock.NewMockRepository(ctrl)
+	streamUC := usecase.NewStreamUseCase(cfg, mockOutboxRepo, logger)
+
+	e := echo.New()
+	e.Use(utils.ResponseController())
+	e.Use(middleware.Gzip())
+	MapStreamRoutes(e.Group("/v1/news"), models.ContentTypeNews, NewStreamHandlers(cfg, streamUC, logger))
+
+	server := httptest.NewServer(e)
+	t.Cleanup(server.Close)
+
+	return server, streamUC, mockOutboxRepo
+}
+
+// readFrame returns the lines of the next frame of the stream
+func readFrame(t *testing.T, reader *bufio.Reader) []string {
+	var lines []string
+	for {
+		line, err := reader.ReadString('\n')
+		require.NoError(t, err)
+
+		line = strings.TrimSuffix(line, "\n")
+		if line == "" {
+			return lines
+		}
+		lines = append(lines, line)
+	}
+}
+
+func TestStreamHandlers_Stream(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Stream resumed", func(t *testing.T) {
+		server, streamUC, mockOutboxRepo := newStreamServer(t)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		request, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/v1/news/stream", nil)
+		require.NoError(t, err)
+		request.Header.Set(HEADER_LAST_EVENT_ID, "7")
+
+		// missed events are replayed, but published
+		mockOutboxRepo.EXPECT().Lookback(gomock.Any(), models.ContentTypeNews, int64(7), time.Minute).Return(int64(7), nil)
+		mockOutboxRepo.EXPECT().After(gomock.Any(), models.ContentTypeNews, int64(7), 10).Return([]*models.Event{
+			{ID: 8, Type: models.EventContentCreated, ContentType: models.ContentTypeNews, ContentID: 1, Payload: json.RawMessage(`{"id":1}`)},
+			{ID: 9, Type: models.EventContentPublished, ContentType: models.ContentTypeNews, ContentID: 1, Payload: json.RawMessage(`{"id":1}`)},
+		}, nil)
+
+		response, err := http.DefaultClient.Do(request)
+		require.NoError(t, err)
+		defer response.Body.Close()
+
+		// gzip is decoded by the client
+		require.Equal(t, http.StatusOK, response.StatusCode)
+		require.Equal(t, MIME_EVENT_STREAM, response.Header.Get(echo.HeaderContentType))
+		require.True(t, response.Uncompressed)
+
+		reader := bufio.NewReader(response.Body)
+		require.Equal(t, []string{"retry: 3000"}, readFrame(t, reader))
+		require.Equal(t, []string{
+			"id: 8",
+			"event: content.created",
+			`data: {"id":8,"type":"content.created","content_type":"news","content_id":1,"payload":{"id":1},"created_at":"0001-01-01T00:00:00Z"}`,
+		}, readFrame(t, reader))
+
+		// then the events notified, the replayed ones are not sent again
+		mockOutboxRepo.EXPECT().GetByID(gomock.Any(), int64(8)).Return(
+			&models.Event{ID: 8, Type: models.EventContentCreated, ContentType: models.ContentTypeNews, ContentID: 1}, nil,
+		)
+		mockOutboxRepo.EXPECT().GetByID(gomock.Any(), int64(10)).Return(
+			&models.Event{ID: 10, Type: models.EventContentDeleted, ContentType: models.ContentTypeNews, ContentID: 1}, nil,
+		)
+		require.NoError(t, streamUC.Notify(ctx, 8))
+		require.NoError(t, streamUC.Notify(ctx, 10))
+
+		frame := readFrame(t, reader)
+		require.Equal(t, []string{"id: 10", "event: content.deleted"}, frame[:2])
+
+		// heartbeats get through gzip while nothing happens
+		require.Equal(t, []string{strings.TrimSpace(HEARTBEAT)}, readFrame(t, reader))
+	})
+
+	t.Run("Stream closed", func(t *testing.T) {
+		server, streamUC, _ := newStreamServer(t)
+
+		response, err := http.Get(server.URL + "/v1/news/stream")
+		require.NoError(t, err)
+		defer response.Body.Close()
+
+		reader := bufio.NewReader(response.Body)
+		require.Equal(t, []string{"retry: 3000"}, readFrame(t, reader))
+
+		// the client reconnects with its Last-Event-ID
+		streamUC.Close()
+		_, err = reader.ReadString('\n')
+		require.Error(t, err)
+	})
+
+	t.Run("Stream bad Last-Event-ID", func(t *testing.T) {
+		server, _, _ := newStreamServer(t)
+
+		response, err := http.Get(server.URL + "/v1/news/stream?last_event_id=abc")
+		require.NoError(t, err)
+		defer response.Body.Close()
+
+		require.Equal(t, http.StatusBadRequest, response.StatusCode)
+	})
+}
@@ -0,0 +1,11 @@
+package http
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/realtemirov/task-for-dell/internal/stream"
+)
+
+// MapStreamRoutes maps the stream of events of the content type, under the group of that content
+func MapStreamRoutes(contentGroup *echo.Group, contentType string, h stream.Handlers) {
+	contentGroup.GET("/stream", h.Stream(contentType))
+}
@@ -0,0 +1,66 @@
+package listener
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/realtemirov/task-for-dell/config"
+	"github.com/realtemirov/task-for-dell/internal/stream"
+	"github.com/realtemirov/task-for-dell/pkg/db/postgres"
+	"github.com/realtemirov/task-for-dell/pkg/logger"
+)
+
+// CHANNEL is notified by the outbox trigger with the id of every event committed, by any instance
+const CHANNEL = "outbox_events"
+
+// Listener feeds the streams of this instance with the events notified by postgres
+type Listener struct {
+	cfg      *config.Config
+	streamUC stream.UseCase
+	log      logger.Logger
+}
+
+// Listener constructor
+func NewListener(cfg *config.Config, streamUC stream.UseCase, log logger.Logger) *Listener {
+	return &Listener{
+		cfg:      cfg,
+		streamUC: streamUC,
+		log:      log,
+	}
+}
+
+// Run listens until ctx is done, then closes every stream. Notifications are lost while the connection is down,
+// so the streams are closed every time it listens again and their clients resume after their Last-Event-ID.
+func (l *Listener) Run(ctx context.Context) {
+	defer l.streamUC.Close()
+
+	for {
+		err := postgres.Listen(ctx, &l.cfg.Postgres, CHANNEL, l.streamUC.Close, func(payload string) {
+			l.notify(ctx, payload)
+		})
+		if ctx.Err() != nil {
+			return
+		}
+		l.log.Errorf("stream: lost the connection listening to %s, reconnecting in %ds: %v", CHANNEL, l.cfg.Stream.ReconnectBackoff, err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(l.cfg.Stream.ReconnectBackoff * time.Second):
+		}
+	}
+}
+
+// notify sends the event of the payload to the streams
+func (l *Listener) notify(ctx context.Context, payload string) {
+	eventID, err := strconv.ParseInt(payload, 10, 64)
+	if err != nil {
+		l.log.Warnf("stream: unexpected notification %q: %v", payload, err)
+		return
+	}
+
+	if err = l.streamUC.Notify(ctx, eventID); err != nil && ctx.Err() == nil {
+		l.log.Errorf("stream: failed to notify event %d: %v", eventID, err)
+	}
+}
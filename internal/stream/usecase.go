@@ -0,0 +1,14 @@
+package stream
+
+import (
+	"context"
+
+	"github.com/realtemirov/task-for-dell/internal/models"
+)
+
+type UseCase interface {
+	Subscribe(ctx context.Context, contentType string) <-chan *models.Event
+	Replay(ctx context.Context, contentType string, lastEventID int64, fn func(event *models.Event) error) error
+	Notify(ctx context.Context, eventID int64) error
+	Close()
+}
@@ -0,0 +1,173 @@
+package usecase
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/realtemirov/task-for-dell/config"
+	"github.com/realtemirov/task-for-dell/internal/models"
+	"github.com/realtemirov/task-for-dell/internal/outbox"
+	"github.com/realtemirov/task-for-dell/internal/stream"
+	"github.com/realtemirov/task-for-dell/pkg/domainErrors"
+	"github.com/realtemirov/task-for-dell/pkg/logger"
+	"github.com/realtemirov/task-for-dell/pkg/tracing"
+)
+
+// subscriber is a stream of the events of a content type, its events are buffered
+// so a slow client does not hold back the others
+type subscriber struct {
+	contentType string
+	events      chan *models.Event
+}
+
+// Stream Usecase, the subscribers of this instance receive the events notified by the listener
+type streamUC struct {
+	cfg        *config.Config
+	outboxRepo outbox.Repository
+	log        logger.Logger
+
+	mu          sync.Mutex
+	subscribers map[*subscriber]struct{}
+}
+
+// Stream UseCase contructor
+func NewStreamUseCase(cfg *config.Config, outboxRepo outbox.Repository, log logger.Logger) stream.UseCase {
+	return &streamUC{
+		cfg:         cfg,
+		outboxRepo:  outboxRepo,
+		log:         log,
+		subscribers: make(map[*subscriber]struct{}),
+	}
+}
+
+// streamed reports whether events of the type are streamed, published follows every created
+func streamed(eventType string) bool {
+	switch eventType {
+	case models.EventContentCreated, models.EventContentUpdated, models.EventContentDeleted:
+		return true
+	default:
+		return false
+	}
+}
+
+// Subscribe implements stream.UseCase, the channel is closed when ctx is done, when the subscriber is
+// more than BufferSize events behind or on Close; the client then resumes with Replay.
+func (u *streamUC) Subscribe(ctx context.Context, contentType string) <-chan *models.Event {
+	sub := &subscriber{
+		contentType: contentType,
+		events:      make(chan *models.Event, u.cfg.Stream.BufferSize),
+	}
+
+	u.mu.Lock()
+	u.subscribers[sub] = struct{}{}
+	u.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+
+		u.mu.Lock()
+		u.unsubscribe(sub)
+		u.mu.Unlock()
+	}()
+
+	return sub.events
+}
+
+// unsubscribe closes the events of sub once, u.mu is held
+func (u *streamUC) unsubscribe(sub *subscriber) {
+	if _, ok := u.subscribers[sub]; ok {
+		delete(u.subscribers, sub)
+		close(sub.events)
+	}
+}
+
+// Replay implements stream.UseCase, fn is called with the streamed events of the content type after
+// lastEventID still in the outbox, in order, ReplayBatch at a time. Ids are taken when events are written,
+// not committed, so an event committed after lastEventID may have a lower id: the ones written up to
+// ReplayLookback before it are replayed as well, the client may have received some of them already.
+func (u *streamUC) Replay(ctx context.Context, contentType string, lastEventID int64, fn func(event *models.Event) error) error {
+	ctx, span := tracing.Start(ctx, "streamUC.Replay")
+	defer span.End()
+
+	afterID, err := u.outboxRepo.Lookback(ctx, contentType, lastEventID, u.cfg.Stream.ReplayLookback*time.Second)
+	if err != nil {
+		return err
+	}
+
+	for {
+		events, err := u.outboxRepo.After(ctx, contentType, afterID, u.cfg.Stream.ReplayBatch)
+		if err != nil {
+			return err
+		}
+
+		for _, event := range events {
+			afterID = event.ID
+			if event.ID == lastEventID || !streamed(event.Type) {
+				continue
+			}
+			if err = fn(event); err != nil {
+				return err
+			}
+		}
+
+		if len(events) < u.cfg.Stream.ReplayBatch {
+			return nil
+		}
+	}
+}
+
+// Notify implements stream.UseCase, the event is sent to the subscribers of its content type.
+// A subscriber whose buffer is full is closed.
+func (u *streamUC) Notify(ctx context.Context, eventID int64) error {
+	ctx, span := tracing.Start(ctx, "streamUC.Notify")
+	defer span.End()
+
+	// nobody to send it to, the event is not read
+	u.mu.Lock()
+	idle := len(u.subscribers) == 0
+	u.mu.Unlock()
+	if idle {
+		return nil
+	}
+
+	event, err := u.outboxRepo.GetByID(ctx, eventID)
+	if domainErrors.Is(err, domainErrors.KindNotFound) {
+		// deleted after its retention meanwhile
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !streamed(event.Type) {
+		return nil
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	for sub := range u.subscribers {
+		if sub.contentType != event.ContentType {
+			continue
+		}
+
+		select {
+		case sub.events <- event:
+		default:
+			u.log.Warnf("stream: a %s subscriber is %d events behind, it is disconnected", sub.contentType, len(sub.events))
+			u.unsubscribe(sub)
+		}
+	}
+
+	return nil
+}
+
+// Close implements stream.UseCase, every subscriber is closed.
+func (u *streamUC) Close() {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	for sub := range u.subscribers {
+		u.unsubscribe(sub)
+	}
+}
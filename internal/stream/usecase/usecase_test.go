@@ -0,0 +1,173 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/realtemirov/task-for-dell/config"
+	"github.com/realtemirov/task-for-dell/internal/models"
+	"github.com/realtemirov/task-for-dell/internal/outbox/mock"
+	"github.com/realtemirov/task-for-dell/pkg/domainErrors"
+	"github.com/realtemirov/task-for-dell/pkg/logger"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// newStreamUC returns the usecase of streams on a mock outbox repository
+func newStreamUC(t *testing.T) (*streamUC, *mock.MockRepository) {
+	ctrl := gomock.NewController(t)
+	t.Cleanup(ctrl.Finish)
+
+	cfg := &config.Config{Stream: config.StreamConfig{BufferSize: 2, ReplayBatch: 2, ReplayLookback: 60}}
+	mockOutboxRepo := mock.NewMockRepository(ctrl)
+
+	return NewStreamUseCase(cfg, mockOutboxRepo, logger.NewApiLogger(nil)).(*streamUC), mockOutboxRepo
+}
+
+func TestStreamUC_Notify(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Notify subscribers of the content type", func(t *testing.T) {
+		streamUC, mockOutboxRepo := newStreamUC(t)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		news := streamUC.Subscribe(ctx, models.ContentTypeNews)
+		blogs := streamUC.Subscribe(ctx, models.ContentTypeBlog)
+
+		event := &models.Event{ID: 7, Type: models.EventContentUpdated, ContentType: models.ContentTypeNews}
+		mockOutboxRepo.EXPECT().GetByID(gomock.Any(), int64(7)).Return(event, nil)
+
+		require.NoError(t, streamUC.Notify(ctx, 7))
+		require.Equal(t, event, <-news)
+		require.Empty(t, blogs)
+	})
+
+	t.Run("Notify skips published and missing events", func(t *testing.T) {
+		streamUC, mockOutboxRepo := newStreamUC(t)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		news := streamUC.Subscribe(ctx, models.ContentTypeNews)
+
+		mockOutboxRepo.EXPECT().GetByID(gomock.Any(), int64(7)).Return(
+			&models.Event{ID: 7, Type: models.EventContentPublished, ContentType: models.ContentTypeNews}, nil,
+		)
+		mockOutboxRepo.EXPECT().GetByID(gomock.Any(), int64(8)).Return(nil, domainErrors.NotFound("record not found", nil))
+
+		require.NoError(t, streamUC.Notify(ctx, 7))
+		require.NoError(t, streamUC.Notify(ctx, 8))
+		require.Empty(t, news)
+	})
+
+	t.Run("Notify without subscribers", func(t *testing.T) {
+		streamUC, _ := newStreamUC(t)
+
+		// the event is not read
+		require.NoError(t, streamUC.Notify(context.Background(), 7))
+	})
+
+	t.Run("Notify disconnects slow subscribers", func(t *testing.T) {
+		streamUC, mockOutboxRepo := newStreamUC(t)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		news := streamUC.Subscribe(ctx, models.ContentTypeNews)
+
+		mockOutboxRepo.EXPECT().GetByID(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, id int64) (*models.Event, error) {
+				return &models.Event{ID: id, Type: models.EventContentCreated, ContentType: models.ContentTypeNews}, nil
+			},
+		).Times(3)
+
+		// the third does not fit in the buffer of 2
+		for id := int64(1); id <= 3; id++ {
+			require.NoError(t, streamUC.Notify(ctx, id))
+		}
+
+		var received []int64
+		for event := range news {
+			received = append(received, event.ID)
+		}
+		require.Equal(t, []int64{1, 2}, received)
+	})
+
+	t.Run("Close and cancel end the subscriptions", func(t *testing.T) {
+		streamUC, _ := newStreamUC(t)
+		ctx, cancel := context.WithCancel(context.Background())
+
+		canceled := streamUC.Subscribe(ctx, models.ContentTypeNews)
+		cancel()
+		_, ok := <-canceled
+		require.False(t, ok)
+
+		closed := streamUC.Subscribe(context.Background(), models.ContentTypeBlog)
+		streamUC.Close()
+		_, ok = <-closed
+		require.False(t, ok)
+	})
+}
+
+func TestStreamUC_Replay(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Replay every batch", func(t *testing.T) {
+		streamUC, mockOutboxRepo := newStreamUC(t)
+
+		// nothing was written shortly before 5
+		gomock.InOrder(
+			mockOutboxRepo.EXPECT().Lookback(gomock.Any(), models.ContentTypeBlog, int64(5), time.Minute).Return(int64(5), nil),
+			mockOutboxRepo.EXPECT().After(gomock.Any(), models.ContentTypeBlog, int64(5), 2).Return([]*models.Event{
+				{ID: 6, Type: models.EventContentCreated},
+				{ID: 7, Type: models.EventContentPublished},
+			}, nil),
+			mockOutboxRepo.EXPECT().After(gomock.Any(), models.ContentTypeBlog, int64(7), 2).Return([]*models.Event{
+				{ID: 9, Type: models.EventContentDeleted},
+			}, nil),
+		)
+
+		var replayed []int64
+		err := streamUC.Replay(context.Background(), models.ContentTypeBlog, 5, func(event *models.Event) error {
+			replayed = append(replayed, event.ID)
+			return nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, []int64{6, 9}, replayed)
+	})
+
+	t.Run("Replay committed late", func(t *testing.T) {
+		streamUC, mockOutboxRepo := newStreamUC(t)
+
+		// 4 was committed after 5 was received, the events written shortly before 5 are replayed but 5
+		gomock.InOrder(
+			mockOutboxRepo.EXPECT().Lookback(gomock.Any(), models.ContentTypeBlog, int64(5), time.Minute).Return(int64(3), nil),
+			mockOutboxRepo.EXPECT().After(gomock.Any(), models.ContentTypeBlog, int64(3), 2).Return([]*models.Event{
+				{ID: 4, Type: models.EventContentUpdated},
+				{ID: 5, Type: models.EventContentUpdated},
+			}, nil),
+			mockOutboxRepo.EXPECT().After(gomock.Any(), models.ContentTypeBlog, int64(5), 2).Return([]*models.Event{}, nil),
+		)
+
+		var replayed []int64
+		err := streamUC.Replay(context.Background(), models.ContentTypeBlog, 5, func(event *models.Event) error {
+			replayed = append(replayed, event.ID)
+			return nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, []int64{4}, replayed)
+	})
+
+	t.Run("Replay failed", func(t *testing.T) {
+		streamUC, mockOutboxRepo := newStreamUC(t)
+
+		mockOutboxRepo.EXPECT().Lookback(gomock.Any(), models.ContentTypeBlog, int64(5), time.Minute).Return(int64(5), nil)
+		mockOutboxRepo.EXPECT().After(gomock.Any(), models.ContentTypeBlog, int64(5), 2).Return(nil, errors.New("connection refused"))
+
+		err := streamUC.Replay(context.Background(), models.ContentTypeBlog, 5, func(event *models.Event) error {
+			return nil
+		})
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,9 @@
+package translations
+
+import "github.com/labstack/echo/v4"
+
+type Handlers interface {
+	Upsert(contentType string) echo.HandlerFunc
+	Delete(contentType string) echo.HandlerFunc
+	GetAll(contentType string) echo.HandlerFunc
+}
@@ -0,0 +1,142 @@
+package http
+
+import (
+	"net/http"
+
+	echo "github.com/labstack/echo/v4"
+	"github.com/realtemirov/task-for-dell/config"
+	"github.com/realtemirov/task-for-dell/internal/models"
+	"github.com/realtemirov/task-for-dell/internal/translations"
+
+	"github.com/realtemirov/task-for-dell/pkg/httpErrors"
+	"github.com/realtemirov/task-for-dell/pkg/logger"
+	"github.com/realtemirov/task-for-dell/pkg/utils"
+)
+
+type translationsHandlers struct {
+	cfg            *config.Config
+	translationsUC translations.UseCase
+	logger         logger.Logger
+}
+
+// NewTranslationsHandlers constructs a new translationsHandlers.
+func NewTranslationsHandlers(cfg *config.Config, translationsUC translations.UseCase, logger logger.Logger) translations.Handlers {
+	return &translationsHandlers{
+		cfg:            cfg,
+		translationsUC: translationsUC,
+		logger:         logger,
+	}
+}
+
+// Upsert
+// @Summary Upsert translation
+// @Description Create or update the translation of a blog or news to a locale
+// @Tags Translations
+// @Accept  json
+// @Produce  json
+// @Param id path int true "blog_id or news_id"
+// @Param locale path string true "ru or uz"
+// @Param body body models.TranslationSwagger true "translation"
+// @Success 200 {object} models.Translation
+// @Failure 400 {object} httpErrors.Problem
+// @Failure 404 {object} httpErrors.Problem
+// @Failure 422 {object} httpErrors.Problem
+// @Failure 500 {object} httpErrors.Problem
+// @Router /blogs/{id}/translations/{locale} [PUT]
+// @Router /news/{id}/translations/{locale} [PUT]
+func (h *translationsHandlers) Upsert(contentType string) echo.HandlerFunc {
+	return func(c echo.Context) error {
+
+		var (
+			err         error
+			translation *models.Translation = &models.Translation{}
+			upserted    *models.Translation = &models.Translation{}
+		)
+
+		// bind request body to translation
+		if err = c.Bind(translation); err != nil {
+			return httpErrors.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		// content and locale come from the path
+		translation.ContentType = contentType
+		translation.Locale = c.Param("locale")
+		translation.ContentID, err = utils.StringToInt64(c.Param("id"))
+		if err != nil {
+			return httpErrors.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		upserted, err = h.translationsUC.Upsert(c.Request().Context(), translation)
+		if err != nil {
+			return httpErrors.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, upserted)
+	}
+}
+
+// Delete
+// @Summary Delete translation
+// @Description Delete the translation of a blog or news to a locale
+// @Tags Translations
+// @Accept  json
+// @Produce  json
+// @Param id path int true "blog_id or news_id"
+// @Param locale path string true "ru or uz"
+// @Success 204 "No Content"
+// @Failure 400 {object} httpErrors.Problem
+// @Failure 404 {object} httpErrors.Problem
+// @Failure 500 {object} httpErrors.Problem
+// @Router /blogs/{id}/translations/{locale} [DELETE]
+// @Router /news/{id}/translations/{locale} [DELETE]
+func (h *translationsHandlers) Delete(contentType string) echo.HandlerFunc {
+	return func(c echo.Context) error {
+
+		contentID, err := utils.StringToInt64(c.Param("id"))
+		if err != nil {
+			return httpErrors.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		err = h.translationsUC.Delete(c.Request().Context(), contentType, contentID, c.Param("locale"))
+		if err != nil {
+			return httpErrors.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+// GetAll
+// @Summary GetAll translations
+// @Description Get every translation of a blog or news
+// @Tags Translations
+// @Accept  json
+// @Produce  json
+// @Param id path int true "blog_id or news_id"
+// @Success 200 {object} models.TranslationList
+// @Failure 400 {object} httpErrors.Problem
+// @Failure 500 {object} httpErrors.Problem
+// @Router /blogs/{id}/translations [GET]
+// @Router /news/{id}/translations [GET]
+func (h *translationsHandlers) GetAll(contentType string) echo.HandlerFunc {
+	return func(c echo.Context) error {
+
+		var (
+			err             error
+			contentID       int64
+			translationList *models.TranslationList = &models.TranslationList{}
+		)
+
+		contentID, err = utils.StringToInt64(c.Param("id"))
+		if err != nil {
+			return httpErrors.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		translationList, err = h.translationsUC.GetAll(c.Request().Context(), contentType, contentID)
+		if err != nil {
+			return httpErrors.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, translationList)
+	}
+}
@@ -0,0 +1,224 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/realtemirov/task-for-dell/config"
+	"github.com/realtemirov/task-for-dell/internal/models"
+	"github.com/realtemirov/task-for-dell/internal/translations/mock"
+	"github.com/realtemirov/task-for-dell/internal/translations/usecase"
+	"github.com/realtemirov/task-for-dell/pkg/domainErrors"
+	"github.com/realtemirov/task-for-dell/pkg/httpErrors"
+	"github.com/realtemirov/task-for-dell/pkg/logger"
+	"github.com/realtemirov/task-for-dell/pkg/utils"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestTranslationsHandlers_Upsert(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cfg, err := config.LoadConfig("./../../../../config/config-local")
+	require.NoError(t, err)
+
+	logger := logger.NewApiLogger(cfg)
+	logger.InitLogger()
+
+	mockTranslationsRepo := mock.NewMockRepository(ctrl)
+	translationsUC := usecase.NewTranslationsUseCase(cfg, mockTranslationsRepo, logger)
+	translationsHandler := NewTranslationsHandlers(cfg, translationsUC, logger)
+	handler := translationsHandler.Upsert(models.ContentTypeBlog)
+
+	t.Run("Upsert success case", func(t *testing.T) {
+		translation := models.Translation{
+			ContentType: models.ContentTypeBlog,
+			ContentID:   1,
+			Locale:      "ru",
+			Title:       "title-test",
+			Content:     "content-test",
+		}
+
+		bufferData, err := utils.AnyToBytesBuffer(models.TranslationSwagger{Title: translation.Title, Content: translation.Content})
+		require.NoError(t, err)
+
+		request := httptest.NewRequest(http.MethodPut, "/v1/blogs/1/translations/ru", strings.NewReader(bufferData.String()))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		response := httptest.NewRecorder()
+
+		e := echo.New()
+		echoCtx := e.NewContext(request, response)
+		echoCtx.SetParamNames("id", "locale")
+		echoCtx.SetParamValues("1", "ru")
+
+		mockTranslationsRepo.EXPECT().Upsert(gomock.Any(), &translation).Return(&translation, nil)
+
+		err = handler(echoCtx)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, response.Code)
+	})
+
+	t.Run("Upsert default locale case", func(t *testing.T) {
+		bufferData, err := utils.AnyToBytesBuffer(models.TranslationSwagger{Title: "title-test", Content: "content-test"})
+		require.NoError(t, err)
+
+		request := httptest.NewRequest(http.MethodPut, "/v1/blogs/1/translations/en", strings.NewReader(bufferData.String()))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		response := httptest.NewRecorder()
+
+		e := echo.New()
+		echoCtx := e.NewContext(request, response)
+		echoCtx.SetParamNames("id", "locale")
+		echoCtx.SetParamValues("1", utils.DEFAULT_LOCALE)
+
+		err = handler(echoCtx)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusUnprocessableEntity, response.Code)
+	})
+
+	t.Run("Upsert not found case", func(t *testing.T) {
+		bufferData, err := utils.AnyToBytesBuffer(models.TranslationSwagger{Title: "title-test", Content: "content-test"})
+		require.NoError(t, err)
+
+		request := httptest.NewRequest(http.MethodPut, "/v1/blogs/1/translations/uz", strings.NewReader(bufferData.String()))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		response := httptest.NewRecorder()
+
+		e := echo.New()
+		echoCtx := e.NewContext(request, response)
+		echoCtx.SetParamNames("id", "locale")
+		echoCtx.SetParamValues("1", "uz")
+
+		mockTranslationsRepo.EXPECT().Upsert(gomock.Any(), gomock.Any()).Return(nil, domainErrors.NotFound("record not found", nil))
+
+		err = handler(echoCtx)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusNotFound, response.Code)
+	})
+
+	t.Run("Upsert bad id case", func(t *testing.T) {
+		request := httptest.NewRequest(http.MethodPut, "/v1/blogs/abc/translations/uz", strings.NewReader(`{}`))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		response := httptest.NewRecorder()
+
+		e := echo.New()
+		echoCtx := e.NewContext(request, response)
+		echoCtx.SetParamNames("id", "locale")
+		echoCtx.SetParamValues("abc", "uz")
+
+		err = handler(echoCtx)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusBadRequest, response.Code)
+	})
+}
+
+func TestTranslationsHandlers_Delete(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cfg, err := config.LoadConfig("./../../../../config/config-local")
+	require.NoError(t, err)
+
+	logger := logger.NewApiLogger(cfg)
+	logger.InitLogger()
+
+	mockTranslationsRepo := mock.NewMockRepository(ctrl)
+	translationsUC := usecase.NewTranslationsUseCase(cfg, mockTranslationsRepo, logger)
+	translationsHandler := NewTranslationsHandlers(cfg, translationsUC, logger)
+	handler := translationsHandler.Delete(models.ContentTypeNews)
+
+	t.Run("Delete success case", func(t *testing.T) {
+		request := httptest.NewRequest(http.MethodDelete, "/v1/news/1/translations/ru", nil)
+		response := httptest.NewRecorder()
+
+		e := echo.New()
+		echoCtx := e.NewContext(request, response)
+		echoCtx.SetParamNames("id", "locale")
+		echoCtx.SetParamValues("1", "ru")
+
+		mockTranslationsRepo.EXPECT().Delete(gomock.Any(), models.ContentTypeNews, int64(1), "ru").Return(nil)
+
+		err = handler(echoCtx)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusNoContent, response.Code)
+	})
+
+	t.Run("Delete not found case", func(t *testing.T) {
+		request := httptest.NewRequest(http.MethodDelete, "/v1/news/1/translations/ru", nil)
+		response := httptest.NewRecorder()
+
+		e := echo.New()
+		echoCtx := e.NewContext(request, response)
+		echoCtx.SetParamNames("id", "locale")
+		echoCtx.SetParamValues("1", "ru")
+
+		mockTranslationsRepo.EXPECT().Delete(gomock.Any(), models.ContentTypeNews, int64(1), "ru").Return(domainErrors.NotFound("record not found", nil))
+
+		err = handler(echoCtx)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusNotFound, response.Code)
+	})
+}
+
+func TestTranslationsHandlers_GetAll(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cfg, err := config.LoadConfig("./../../../../config/config-local")
+	require.NoError(t, err)
+
+	logger := logger.NewApiLogger(cfg)
+	logger.InitLogger()
+
+	mockTranslationsRepo := mock.NewMockRepository(ctrl)
+	translationsUC := usecase.NewTranslationsUseCase(cfg, mockTranslationsRepo, logger)
+	translationsHandler := NewTranslationsHandlers(cfg, translationsUC, logger)
+	handler := translationsHandler.GetAll(models.ContentTypeBlog)
+
+	t.Run("GetAll success case", func(t *testing.T) {
+		request := httptest.NewRequest(http.MethodGet, "/v1/blogs/1/translations", nil)
+		response := httptest.NewRecorder()
+
+		e := echo.New()
+		echoCtx := e.NewContext(request, response)
+		echoCtx.SetParamNames("id")
+		echoCtx.SetParamValues("1")
+
+		mockTranslationsRepo.EXPECT().GetAll(gomock.Any(), models.ContentTypeBlog, int64(1)).Return([]*models.Translation{{Locale: "ru"}}, nil)
+
+		err = handler(echoCtx)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, response.Code)
+
+		result := models.TranslationList{}
+		require.NoError(t, json.Unmarshal(response.Body.Bytes(), &result))
+		require.Len(t, result.Translations, 1)
+		require.Equal(t, "ru", result.Translations[0].Locale)
+	})
+
+	t.Run("GetAll bad id case", func(t *testing.T) {
+		request := httptest.NewRequest(http.MethodGet, "/v1/blogs/abc/translations", nil)
+		response := httptest.NewRecorder()
+
+		e := echo.New()
+		echoCtx := e.NewContext(request, response)
+		echoCtx.SetParamNames("id")
+		echoCtx.SetParamValues("abc")
+
+		err = handler(echoCtx)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusBadRequest, response.Code)
+		require.Equal(t, httpErrors.MIMEApplicationProblemJSON, response.Header().Get(echo.HeaderContentType))
+	})
+}
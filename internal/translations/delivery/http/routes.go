@@ -0,0 +1,13 @@
+package http
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/realtemirov/task-for-dell/internal/translations"
+)
+
+// MapTranslationsRoutes maps routes for translations of the content type, under the group of that content
+func MapTranslationsRoutes(contentGroup *echo.Group, contentType string, h translations.Handlers) {
+	contentGroup.GET("/:id/translations", h.GetAll(contentType))
+	contentGroup.PUT("/:id/translations/:locale", h.Upsert(contentType))
+	contentGroup.DELETE("/:id/translations/:locale", h.Delete(contentType))
+}
@@ -0,0 +1,14 @@
+package translations
+
+import (
+	"context"
+
+	"github.com/realtemirov/task-for-dell/internal/models"
+)
+
+type Repository interface {
+	Upsert(ctx context.Context, translation *models.Translation) (*models.Translation, error)
+	Delete(ctx context.Context, contentType string, contentID int64, locale string) error
+	GetAll(ctx context.Context, contentType string, contentID int64) ([]*models.Translation, error)
+	GetMany(ctx context.Context, contentType string, contentIDs []int64, locale string) (map[int64]*models.Translation, error)
+}
@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/realtemirov/task-for-dell/internal/models"
+	"github.com/realtemirov/task-for-dell/internal/translations"
+	"github.com/realtemirov/task-for-dell/pkg/db/postgres"
+	"github.com/realtemirov/task-for-dell/pkg/domainErrors"
+	"github.com/realtemirov/task-for-dell/pkg/metrics"
+	"github.com/realtemirov/task-for-dell/pkg/tracing"
+)
+
+type translationsRepo struct {
+	db *postgres.DB
+}
+
+// NewTranslationsRepository constructor
+func NewTranslationsRepository(db *postgres.DB) translations.Repository {
+	return &translationsRepo{db: db}
+}
+
+// Upsert implements translations.Repository.
+func (r *translationsRepo) Upsert(ctx context.Context, translation *models.Translation) (*models.Translation, error) {
+	ctx, span := tracing.Start(ctx, "translationsRepo.Upsert")
+	defer span.End()
+	defer metrics.ObserveQuery("translations", "Upsert", time.Now())
+
+	table, ok := models.ContentTable(translation.ContentType)
+	if !ok {
+		return nil, domainErrors.Invalid(fmt.Sprintf("unknown content type %q", translation.ContentType), nil)
+	}
+
+	result := models.Translation{}
+
+	// create or update the translation, no row when the content does not exist
+	if err := r.db.Writer(ctx).QueryRowxContext(
+		ctx,
+		fmt.Sprintf(upsertQuery, table),
+		translation.ContentType,
+		translation.ContentID,
+		translation.Locale,
+		translation.Title,
+		translation.Content,
+	).StructScan(&result); err != nil {
+		return nil, errors.Wrap(postgres.MapError(err), "translationsRepo.Upsert.StructScan")
+	}
+
+	// if no error, return result
+	return &result, nil
+}
+
+// Delete implements translations.Repository.
+func (r *translationsRepo) Delete(ctx context.Context, contentType string, contentID int64, locale string) error {
+	ctx, span := tracing.Start(ctx, "translationsRepo.Delete")
+	defer span.End()
+	defer metrics.ObserveQuery("translations", "Delete", time.Now())
+
+	result, err := r.db.Writer(ctx).ExecContext(ctx, deleteQuery, contentType, contentID, locale)
+	if err != nil {
+		return errors.Wrap(postgres.MapError(err), "translationsRepo.Delete.ExecContext")
+	}
+
+	// if didn't rows affected, return error
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(postgres.MapError(err), "translationsRepo.Delete.RowsAffected")
+	}
+	if rowsAffected == 0 {
+		return errors.Wrap(postgres.MapError(sql.ErrNoRows), "translationsRepo.Delete.RowsAffected")
+	}
+
+	return nil
+}
+
+// GetAll implements translations.Repository.
+func (r *translationsRepo) GetAll(ctx context.Context, contentType string, contentID int64) ([]*models.Translation, error) {
+	ctx, span := tracing.Start(ctx, "translationsRepo.GetAll")
+	defer span.End()
+	defer metrics.ObserveQuery("translations", "GetAll", time.Now())
+
+	result := make([]*models.Translation, 0)
+
+	if err := r.db.Reader(ctx).SelectContext(ctx, &result, getAllQuery, contentType, contentID); err != nil {
+		return nil, errors.Wrap(postgres.MapError(err), "translationsRepo.GetAll.SelectContext")
+	}
+
+	return result, nil
+}
+
+// GetMany implements translations.Repository.
+func (r *translationsRepo) GetMany(ctx context.Context, contentType string, contentIDs []int64, locale string) (map[int64]*models.Translation, error) {
+	ctx, span := tracing.Start(ctx, "translationsRepo.GetMany")
+	defer span.End()
+	defer metrics.ObserveQuery("translations", "GetMany", time.Now())
+
+	result := make(map[int64]*models.Translation, len(contentIDs))
+	if len(contentIDs) == 0 {
+		return result, nil
+	}
+
+	rows := make([]*models.Translation, 0, len(contentIDs))
+	if err := r.db.Reader(ctx).SelectContext(
+		ctx,
+		&rows,
+		getManyQuery,
+		contentType,
+		postgres.Int64Array(contentIDs),
+		locale,
+	); err != nil {
+		return nil, errors.Wrap(postgres.MapError(err), "translationsRepo.GetMany.SelectContext")
+	}
+
+	// translations by content id
+	for _, translation := range rows {
+		result[translation.ContentID] = translation
+	}
+
+	return result, nil
+}
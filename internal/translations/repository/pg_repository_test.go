@@ -0,0 +1,289 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/realtemirov/task-for-dell/internal/models"
+	"github.com/realtemirov/task-for-dell/pkg/db/postgres"
+	"github.com/realtemirov/task-for-dell/pkg/domainErrors"
+	"github.com/stretchr/testify/require"
+)
+
+// TestTranslationsRepo_Upsert tests Upsert method.
+func TestTranslationsRepo_Upsert(t *testing.T) {
+	t.Parallel()
+
+	// create mock db
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer db.Close()
+
+	// create sqlx db with mock db
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	defer sqlxDB.Close()
+
+	// translations repository
+	repo := NewTranslationsRepository(postgres.NewDB(sqlxDB))
+
+	// temprorary translation
+	translation := &models.Translation{
+		ContentType: models.ContentTypeBlog,
+		ContentID:   1,
+		Locale:      "ru",
+		Title:       "test-title",
+		Content:     "test-content",
+	}
+
+	// Upsert translation success case
+	t.Run("Upsert", func(t *testing.T) {
+
+		// mock rows
+		rows := sqlmock.NewRows(
+			[]string{"content_type", "content_id", "locale", "title", "content", "created_at", "updated_at"},
+		).AddRow(
+			translation.ContentType,
+			translation.ContentID,
+			translation.Locale,
+			translation.Title,
+			translation.Content,
+			time.Now(),
+			time.Now(),
+		)
+
+		// mock query with args and return rows
+		mock.ExpectQuery(fmt.Sprintf(upsertQuery, "blogs")).WithArgs(
+			translation.ContentType,
+			translation.ContentID,
+			translation.Locale,
+			translation.Title,
+			translation.Content,
+		).WillReturnRows(rows)
+
+		// call Upsert method
+		upserted, err := repo.Upsert(context.Background(), translation)
+
+		// check error and result
+		require.NoError(t, err)
+		require.NotNil(t, upserted)
+		require.Equal(t, translation.ContentID, upserted.ContentID)
+		require.Equal(t, translation.Locale, upserted.Locale)
+		require.Equal(t, translation.Title, upserted.Title)
+	})
+
+	// Upsert translation of content that does not exist
+	t.Run("Upsert NotFound", func(t *testing.T) {
+
+		// no row is inserted when the content does not exist
+		mock.ExpectQuery(fmt.Sprintf(upsertQuery, "blogs")).WithArgs(
+			translation.ContentType,
+			translation.ContentID,
+			translation.Locale,
+			translation.Title,
+			translation.Content,
+		).WillReturnError(sql.ErrNoRows)
+
+		// call Upsert method
+		upserted, err := repo.Upsert(context.Background(), translation)
+
+		// check error and result
+		require.Error(t, err)
+		require.Nil(t, upserted)
+		require.True(t, domainErrors.Is(err, domainErrors.KindNotFound))
+	})
+
+	// Upsert translation of unknown content type
+	t.Run("Upsert Invalid", func(t *testing.T) {
+
+		// call Upsert method
+		upserted, err := repo.Upsert(context.Background(), &models.Translation{ContentType: "video"})
+
+		// check error and result
+		require.Error(t, err)
+		require.Nil(t, upserted)
+		require.True(t, domainErrors.Is(err, domainErrors.KindInvalid))
+	})
+}
+
+// TestTranslationsRepo_Delete tests Delete method.
+func TestTranslationsRepo_Delete(t *testing.T) {
+	t.Parallel()
+
+	// create mock db
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer db.Close()
+
+	// create sqlx db with mock db
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	defer sqlxDB.Close()
+
+	// translations repository
+	repo := NewTranslationsRepository(postgres.NewDB(sqlxDB))
+
+	// Delete translation success case
+	t.Run("Delete", func(t *testing.T) {
+
+		// mock exec with args and return result
+		mock.ExpectExec(deleteQuery).WithArgs(models.ContentTypeNews, int64(1), "uz").WillReturnResult(sqlmock.NewResult(0, 1))
+
+		// call Delete method
+		err := repo.Delete(context.Background(), models.ContentTypeNews, 1, "uz")
+
+		// check error
+		require.NoError(t, err)
+	})
+
+	// Delete translation that does not exist
+	t.Run("Delete NotFound", func(t *testing.T) {
+
+		// mock exec with args and return result
+		mock.ExpectExec(deleteQuery).WithArgs(models.ContentTypeNews, int64(1), "uz").WillReturnResult(sqlmock.NewResult(0, 0))
+
+		// call Delete method
+		err := repo.Delete(context.Background(), models.ContentTypeNews, 1, "uz")
+
+		// check error
+		require.Error(t, err)
+		require.True(t, domainErrors.Is(err, domainErrors.KindNotFound))
+	})
+
+	// Delete translation error case
+	t.Run("Delete Error", func(t *testing.T) {
+
+		// mock exec with args and return error
+		mock.ExpectExec(deleteQuery).WithArgs(models.ContentTypeNews, int64(1), "uz").WillReturnError(sql.ErrConnDone)
+
+		// call Delete method
+		err := repo.Delete(context.Background(), models.ContentTypeNews, 1, "uz")
+
+		// check error
+		require.Error(t, err)
+		require.True(t, domainErrors.Is(err, domainErrors.KindUnavailable))
+	})
+}
+
+// TestTranslationsRepo_GetAll tests GetAll method.
+func TestTranslationsRepo_GetAll(t *testing.T) {
+	t.Parallel()
+
+	// create mock db
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer db.Close()
+
+	// create sqlx db with mock db
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	defer sqlxDB.Close()
+
+	// translations repository
+	repo := NewTranslationsRepository(postgres.NewDB(sqlxDB))
+
+	// GetAll translations success case
+	t.Run("GetAll", func(t *testing.T) {
+
+		// mock rows
+		rows := sqlmock.NewRows(
+			[]string{"content_type", "content_id", "locale", "title", "content", "created_at", "updated_at"},
+		).AddRow(
+			models.ContentTypeBlog, 1, "ru", "test-title-ru", "test-content-ru", time.Now(), time.Now(),
+		).AddRow(
+			models.ContentTypeBlog, 1, "uz", "test-title-uz", "test-content-uz", time.Now(), time.Now(),
+		)
+
+		// mock query with args and return rows
+		mock.ExpectQuery(getAllQuery).WithArgs(models.ContentTypeBlog, int64(1)).WillReturnRows(rows)
+
+		// call GetAll method
+		result, err := repo.GetAll(context.Background(), models.ContentTypeBlog, 1)
+
+		// check error and result
+		require.NoError(t, err)
+		require.Len(t, result, 2)
+		require.Equal(t, "ru", result[0].Locale)
+		require.Equal(t, "uz", result[1].Locale)
+	})
+
+	// GetAll translations error case
+	t.Run("GetAll Error", func(t *testing.T) {
+
+		// mock query with args and return error
+		mock.ExpectQuery(getAllQuery).WithArgs(models.ContentTypeBlog, int64(1)).WillReturnError(sql.ErrConnDone)
+
+		// call GetAll method
+		result, err := repo.GetAll(context.Background(), models.ContentTypeBlog, 1)
+
+		// check error and result
+		require.Error(t, err)
+		require.Nil(t, result)
+	})
+}
+
+// TestTranslationsRepo_GetMany tests GetMany method.
+func TestTranslationsRepo_GetMany(t *testing.T) {
+	t.Parallel()
+
+	// create mock db
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer db.Close()
+
+	// create sqlx db with mock db
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	defer sqlxDB.Close()
+
+	// translations repository
+	repo := NewTranslationsRepository(postgres.NewDB(sqlxDB))
+
+	// GetMany translations success case
+	t.Run("GetMany", func(t *testing.T) {
+
+		// mock rows, content 2 is not translated
+		rows := sqlmock.NewRows(
+			[]string{"content_type", "content_id", "locale", "title", "content", "created_at", "updated_at"},
+		).AddRow(
+			models.ContentTypeNews, 1, "ru", "test-title-ru", "test-content-ru", time.Now(), time.Now(),
+		)
+
+		// mock query with args and return rows
+		mock.ExpectQuery(getManyQuery).WithArgs(models.ContentTypeNews, "{1,2}", "ru").WillReturnRows(rows)
+
+		// call GetMany method
+		result, err := repo.GetMany(context.Background(), models.ContentTypeNews, []int64{1, 2}, "ru")
+
+		// check error and result
+		require.NoError(t, err)
+		require.Len(t, result, 1)
+		require.Equal(t, "test-title-ru", result[1].Title)
+	})
+
+	// GetMany without contents does not query
+	t.Run("GetMany Empty", func(t *testing.T) {
+
+		// call GetMany method
+		result, err := repo.GetMany(context.Background(), models.ContentTypeNews, nil, "ru")
+
+		// check error and result
+		require.NoError(t, err)
+		require.Empty(t, result)
+	})
+
+	// GetMany translations error case
+	t.Run("GetMany Error", func(t *testing.T) {
+
+		// mock query with args and return error
+		mock.ExpectQuery(getManyQuery).WithArgs(models.ContentTypeNews, "{1}", "ru").WillReturnError(sql.ErrConnDone)
+
+		// call GetMany method
+		result, err := repo.GetMany(context.Background(), models.ContentTypeNews, []int64{1}, "ru")
+
+		// check error and result
+		require.Error(t, err)
+		require.Nil(t, result)
+	})
+}
@@ -0,0 +1,47 @@
+package repository
+
+import "fmt"
+
+var (
+
+	// list of fields from translations table.
+	fieldsOfTranslationsTable = `content_type, content_id, locale, title, content, created_at, updated_at`
+
+	// query for create or update a translation of existing content, formatted with the content table.
+	upsertQuery = `
+	INSERT INTO translations
+	(
+		content_type,
+		content_id,
+		locale,
+		title,
+		content
+	)
+	SELECT $1::varchar, $2::integer, $3::varchar, $4::varchar, $5::text
+	WHERE EXISTS (SELECT 1 FROM %s WHERE id = $2)
+	ON CONFLICT (content_type, content_id, locale) DO UPDATE SET
+		title = EXCLUDED.title,
+		content = EXCLUDED.content,
+		updated_at = CURRENT_TIMESTAMP
+	RETURNING ` + fieldsOfTranslationsTable
+
+	// query for delete a translation.
+	deleteQuery = `DELETE FROM translations WHERE content_type = $1 AND content_id = $2 AND locale = $3`
+
+	// query for get every translation of a content.
+	getAllQuery = fmt.Sprintf(`
+	SELECT
+		%s
+	FROM translations
+	WHERE
+		content_type = $1 AND content_id = $2
+	ORDER BY locale`, fieldsOfTranslationsTable)
+
+	// query for get the translations of many contents to one locale.
+	getManyQuery = fmt.Sprintf(`
+	SELECT
+		%s
+	FROM translations
+	WHERE
+		content_type = $1 AND content_id = ANY($2::integer[]) AND locale = $3`, fieldsOfTranslationsTable)
+)
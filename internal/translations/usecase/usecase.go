@@ -0,0 +1,80 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/realtemirov/task-for-dell/config"
+	"github.com/realtemirov/task-for-dell/internal/models"
+	"github.com/realtemirov/task-for-dell/internal/translations"
+	"github.com/realtemirov/task-for-dell/pkg/domainErrors"
+	"github.com/realtemirov/task-for-dell/pkg/logger"
+	"github.com/realtemirov/task-for-dell/pkg/tracing"
+	"github.com/realtemirov/task-for-dell/pkg/utils"
+)
+
+// Translations Usecase
+type translationsUC struct {
+	cfg  *config.Config
+	repo translations.Repository
+	log  logger.Logger
+}
+
+// Translations UseCase contructor
+func NewTranslationsUseCase(cfg *config.Config, repo translations.Repository, log logger.Logger) translations.UseCase {
+	return &translationsUC{
+		cfg:  cfg,
+		repo: repo,
+		log:  log,
+	}
+}
+
+// Upsert implements translations.UseCase.
+func (u *translationsUC) Upsert(ctx context.Context, translation *models.Translation) (*models.Translation, error) {
+	ctx, span := tracing.Start(ctx, "translationsUC.Upsert")
+	defer span.End()
+
+	// blogs and news themselves are in the default locale
+	if translation.Locale == utils.DEFAULT_LOCALE {
+		return nil, domainErrors.Invalid("content is already in the default locale, update it instead", nil)
+	}
+
+	if err := utils.ValidateStruct(ctx, translation); err != nil {
+		return nil, err
+	}
+
+	return u.repo.Upsert(ctx, translation)
+}
+
+// Delete implements translations.UseCase.
+func (u *translationsUC) Delete(ctx context.Context, contentType string, contentID int64, locale string) error {
+	ctx, span := tracing.Start(ctx, "translationsUC.Delete")
+	defer span.End()
+
+	return u.repo.Delete(ctx, contentType, contentID, locale)
+}
+
+// GetAll implements translations.UseCase.
+func (u *translationsUC) GetAll(ctx context.Context, contentType string, contentID int64) (*models.TranslationList, error) {
+	ctx, span := tracing.Start(ctx, "translationsUC.GetAll")
+	defer span.End()
+
+	result, err := u.repo.GetAll(ctx, contentType, contentID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.TranslationList{Translations: result}, nil
+}
+
+// GetMany implements translations.UseCase.
+func (u *translationsUC) GetMany(ctx context.Context, contentType string, contentIDs []int64, locale string) (map[int64]*models.Translation, error) {
+	ctx, span := tracing.Start(ctx, "translationsUC.GetMany")
+	defer span.End()
+
+	// the default locale is the content itself
+	if locale == utils.DEFAULT_LOCALE || len(contentIDs) == 0 {
+		return map[int64]*models.Translation{}, nil
+	}
+
+	return u.repo.GetMany(ctx, contentType, contentIDs, locale)
+}
@@ -0,0 +1,192 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/realtemirov/task-for-dell/internal/models"
+	"github.com/realtemirov/task-for-dell/internal/translations/mock"
+	"github.com/realtemirov/task-for-dell/pkg/domainErrors"
+	"github.com/realtemirov/task-for-dell/pkg/logger"
+	"github.com/realtemirov/task-for-dell/pkg/utils"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestTranslationsUC_Upsert(t *testing.T) {
+	t.Parallel()
+
+	// Create a new instance of the gomock controller
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// logger, repository, usecase of translations
+	logger := logger.NewApiLogger(nil)
+	mockTranslationsRepo := mock.NewMockRepository(ctrl)
+	translationsUC := NewTranslationsUseCase(nil, mockTranslationsRepo, logger)
+
+	// context
+	ctx := context.Background()
+
+	t.Run("Upsert", func(t *testing.T) {
+		translation := &models.Translation{
+			ContentType: models.ContentTypeBlog,
+			ContentID:   1,
+			Locale:      "ru",
+			Title:       "test-title",
+			Content:     "test-content",
+		}
+
+		// mock the Upsert method of the repository
+		mockTranslationsRepo.EXPECT().Upsert(ctx, gomock.Eq(translation)).Return(translation, nil)
+
+		// call the Upsert method of the usecase
+		upserted, err := translationsUC.Upsert(ctx, translation)
+
+		// check the result
+		require.NoError(t, err)
+		require.Equal(t, translation, upserted)
+	})
+
+	t.Run("Upsert default locale", func(t *testing.T) {
+
+		// the default locale is the content itself, the repository is not called
+		upserted, err := translationsUC.Upsert(ctx, &models.Translation{
+			ContentType: models.ContentTypeBlog,
+			ContentID:   1,
+			Locale:      utils.DEFAULT_LOCALE,
+			Title:       "test-title",
+			Content:     "test-content",
+		})
+
+		// check the result
+		require.Error(t, err)
+		require.Nil(t, upserted)
+		require.True(t, domainErrors.Is(err, domainErrors.KindInvalid))
+	})
+
+	t.Run("Upsert validate error", func(t *testing.T) {
+
+		// unsupported locale and short title fail validation
+		upserted, err := translationsUC.Upsert(ctx, &models.Translation{
+			ContentType: models.ContentTypeBlog,
+			ContentID:   1,
+			Locale:      "de",
+			Title:       "t",
+			Content:     "test-content",
+		})
+
+		// check the result
+		require.Error(t, err)
+		require.Nil(t, upserted)
+		require.Len(t, utils.ValidationErrors(err, utils.DEFAULT_LOCALE), 2)
+	})
+}
+
+func TestTranslationsUC_Delete(t *testing.T) {
+	t.Parallel()
+
+	// Create a new instance of the gomock controller
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// logger, repository, usecase of translations
+	logger := logger.NewApiLogger(nil)
+	mockTranslationsRepo := mock.NewMockRepository(ctrl)
+	translationsUC := NewTranslationsUseCase(nil, mockTranslationsRepo, logger)
+
+	// context
+	ctx := context.Background()
+
+	// mock the Delete method of the repository
+	mockTranslationsRepo.EXPECT().Delete(ctx, models.ContentTypeNews, int64(1), "uz").Return(nil)
+
+	// call the Delete method of the usecase
+	err := translationsUC.Delete(ctx, models.ContentTypeNews, 1, "uz")
+
+	// check the result
+	require.NoError(t, err)
+}
+
+func TestTranslationsUC_GetAll(t *testing.T) {
+	t.Parallel()
+
+	// Create a new instance of the gomock controller
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// logger, repository, usecase of translations
+	logger := logger.NewApiLogger(nil)
+	mockTranslationsRepo := mock.NewMockRepository(ctrl)
+	translationsUC := NewTranslationsUseCase(nil, mockTranslationsRepo, logger)
+
+	// context
+	ctx := context.Background()
+
+	t.Run("GetAll", func(t *testing.T) {
+		translations := []*models.Translation{{Locale: "ru"}, {Locale: "uz"}}
+
+		// mock the GetAll method of the repository
+		mockTranslationsRepo.EXPECT().GetAll(ctx, models.ContentTypeBlog, int64(1)).Return(translations, nil)
+
+		// call the GetAll method of the usecase
+		result, err := translationsUC.GetAll(ctx, models.ContentTypeBlog, 1)
+
+		// check the result
+		require.NoError(t, err)
+		require.Equal(t, translations, result.Translations)
+	})
+
+	t.Run("GetAll error", func(t *testing.T) {
+
+		// mock the GetAll method of the repository
+		mockTranslationsRepo.EXPECT().GetAll(ctx, models.ContentTypeBlog, int64(1)).Return(nil, domainErrors.Unavailable("database is unavailable", nil))
+
+		// call the GetAll method of the usecase
+		result, err := translationsUC.GetAll(ctx, models.ContentTypeBlog, 1)
+
+		// check the result
+		require.Error(t, err)
+		require.Nil(t, result)
+	})
+}
+
+func TestTranslationsUC_GetMany(t *testing.T) {
+	t.Parallel()
+
+	// Create a new instance of the gomock controller
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// logger, repository, usecase of translations
+	logger := logger.NewApiLogger(nil)
+	mockTranslationsRepo := mock.NewMockRepository(ctrl)
+	translationsUC := NewTranslationsUseCase(nil, mockTranslationsRepo, logger)
+
+	// context
+	ctx := context.Background()
+
+	t.Run("GetMany", func(t *testing.T) {
+		translated := map[int64]*models.Translation{1: {ContentID: 1, Locale: "ru"}}
+
+		// mock the GetMany method of the repository
+		mockTranslationsRepo.EXPECT().GetMany(ctx, models.ContentTypeBlog, []int64{1, 2}, "ru").Return(translated, nil)
+
+		// call the GetMany method of the usecase
+		result, err := translationsUC.GetMany(ctx, models.ContentTypeBlog, []int64{1, 2}, "ru")
+
+		// check the result
+		require.NoError(t, err)
+		require.Equal(t, translated, result)
+	})
+
+	t.Run("GetMany default locale", func(t *testing.T) {
+
+		// the default locale is the content itself, the repository is not called
+		result, err := translationsUC.GetMany(ctx, models.ContentTypeBlog, []int64{1, 2}, utils.DEFAULT_LOCALE)
+
+		// check the result
+		require.NoError(t, err)
+		require.Empty(t, result)
+	})
+}
@@ -0,0 +1,11 @@
+package users
+
+import (
+	"context"
+
+	"github.com/realtemirov/task-for-dell/internal/models"
+)
+
+type Repository interface {
+	Create(ctx context.Context, user *models.User) (*models.User, error)
+}
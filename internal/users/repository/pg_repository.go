@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/realtemirov/task-for-dell/internal/models"
+	"github.com/realtemirov/task-for-dell/internal/users"
+	"github.com/realtemirov/task-for-dell/pkg/db/postgres"
+	"github.com/realtemirov/task-for-dell/pkg/metrics"
+	"github.com/realtemirov/task-for-dell/pkg/tracing"
+)
+
+type usersRepo struct {
+	db *postgres.DB
+}
+
+// NewUsersRepository constructor
+func NewUsersRepository(db *postgres.DB) users.Repository {
+	return &usersRepo{db: db}
+}
+
+// Create implements users.Repository.
+func (r *usersRepo) Create(ctx context.Context, user *models.User) (*models.User, error) {
+	ctx, span := tracing.Start(ctx, "usersRepo.Create")
+	defer span.End()
+	defer metrics.ObserveQuery("users", "Create", time.Now())
+
+	// result for response
+	result := models.User{}
+
+	// insert entity and scan result, a taken username is a conflict
+	if err := r.db.Writer(ctx).QueryRowxContext(
+		ctx,
+		createQuery,
+		user.Username,
+		user.PasswordHash,
+		user.Role,
+	).StructScan(&result); err != nil {
+		return nil, errors.Wrap(postgres.MapError(err), "usersRepo.Create.StructScan")
+	}
+
+	// if no error, return result
+	return &result, nil
+}
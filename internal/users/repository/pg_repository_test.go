@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jackc/pgx"
+	"github.com/jmoiron/sqlx"
+	"github.com/realtemirov/task-for-dell/internal/models"
+	"github.com/realtemirov/task-for-dell/pkg/db/postgres"
+	"github.com/realtemirov/task-for-dell/pkg/domainErrors"
+	"github.com/stretchr/testify/require"
+)
+
+// TestUsersRepo_Create tests Create method.
+func TestUsersRepo_Create(t *testing.T) {
+	t.Parallel()
+
+	// create mock db
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer db.Close()
+
+	// create sqlx db with mock db
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	defer sqlxDB.Close()
+
+	// users repository
+	repo := NewUsersRepository(postgres.NewDB(sqlxDB))
+
+	// temprorary user
+	user := &models.User{
+		Username:     "admin",
+		PasswordHash: "hash",
+		Role:         models.RoleAdmin,
+	}
+
+	// Create user success case
+	t.Run("Create", func(t *testing.T) {
+
+		// mock rows
+		rows := sqlmock.NewRows(
+			[]string{"id", "username", "password_hash", "role", "created_at"},
+		).AddRow(1, user.Username, user.PasswordHash, user.Role, time.Now())
+
+		// mock query with args and return rows
+		mock.ExpectQuery(createQuery).WithArgs(user.Username, user.PasswordHash, user.Role).WillReturnRows(rows)
+
+		// call Create method
+		created, err := repo.Create(context.Background(), user)
+
+		// check error and result
+		require.NoError(t, err)
+		require.NotNil(t, created)
+		require.EqualValues(t, 1, created.ID)
+		require.Equal(t, user.Username, created.Username)
+	})
+
+	// Create user whose username is taken
+	t.Run("Create Conflict", func(t *testing.T) {
+
+		// unique violation of the username
+		mock.ExpectQuery(createQuery).WithArgs(user.Username, user.PasswordHash, user.Role).WillReturnError(
+			pgx.PgError{Severity: "ERROR", Code: "23505"},
+		)
+
+		// call Create method
+		created, err := repo.Create(context.Background(), user)
+
+		// check error and result
+		require.Error(t, err)
+		require.Nil(t, created)
+		require.True(t, domainErrors.Is(err, domainErrors.KindConflict))
+	})
+}
@@ -0,0 +1,20 @@
+package repository
+
+import "fmt"
+
+var (
+
+	// list of fields from users table.
+	fieldsOfUsersTable = `id, username, password_hash, role, created_at`
+
+	// query for create new user.
+	createQuery = fmt.Sprintf(`
+	INSERT INTO users
+	(
+		username,
+		password_hash,
+		role
+	)
+	VALUES ($1, $2, $3)
+	RETURNING %s`, fieldsOfUsersTable)
+)
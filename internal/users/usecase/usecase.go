@@ -0,0 +1,48 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/realtemirov/task-for-dell/config"
+	"github.com/realtemirov/task-for-dell/internal/models"
+	"github.com/realtemirov/task-for-dell/internal/users"
+	"github.com/realtemirov/task-for-dell/pkg/logger"
+	"github.com/realtemirov/task-for-dell/pkg/tracing"
+	"github.com/realtemirov/task-for-dell/pkg/utils"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Users Usecase
+type usersUC struct {
+	cfg  *config.Config
+	repo users.Repository
+	log  logger.Logger
+}
+
+// Users UseCase contructor
+func NewUsersUseCase(cfg *config.Config, repo users.Repository, log logger.Logger) users.UseCase {
+	return &usersUC{
+		cfg:  cfg,
+		repo: repo,
+		log:  log,
+	}
+}
+
+// Create implements users.UseCase, only the bcrypt hash of the password is stored.
+func (u *usersUC) Create(ctx context.Context, user *models.User) (*models.User, error) {
+	ctx, span := tracing.Start(ctx, "usersUC.Create")
+	defer span.End()
+
+	if err := utils.ValidateStruct(ctx, user); err != nil {
+		return nil, err
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(user.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, errors.Wrap(err, "usersUC.Create.GenerateFromPassword")
+	}
+	user.PasswordHash = string(hash)
+
+	return u.repo.Create(ctx, user)
+}
@@ -0,0 +1,62 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/realtemirov/task-for-dell/internal/models"
+	"github.com/realtemirov/task-for-dell/internal/users/mock"
+	"github.com/realtemirov/task-for-dell/pkg/logger"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestUsersUC_Create(t *testing.T) {
+	t.Parallel()
+
+	// Create a new instance of the gomock controller
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// logger, repository, usecase of users
+	logger := logger.NewApiLogger(nil)
+	mockUsersRepo := mock.NewMockRepository(ctrl)
+	usersUC := NewUsersUseCase(nil, mockUsersRepo, logger)
+
+	// context
+	ctx := context.Background()
+
+	t.Run("Create", func(t *testing.T) {
+		user := &models.User{
+			Username: "admin",
+			Password: "password",
+			Role:     models.RoleAdmin,
+		}
+
+		// mock the Create method of the repository
+		mockUsersRepo.EXPECT().Create(ctx, gomock.Eq(user)).Return(user, nil)
+
+		// call the Create method of the usecase
+		created, err := usersUC.Create(ctx, user)
+
+		// check the result, only the hash of the password is stored
+		require.NoError(t, err)
+		require.Equal(t, user, created)
+		require.NoError(t, bcrypt.CompareHashAndPassword([]byte(created.PasswordHash), []byte("password")))
+	})
+
+	t.Run("Create validate error", func(t *testing.T) {
+
+		// short password and unknown role fail validation, the repository is not called
+		created, err := usersUC.Create(ctx, &models.User{
+			Username: "admin",
+			Password: "short",
+			Role:     "owner",
+		})
+
+		// check the result
+		require.Error(t, err)
+		require.Nil(t, created)
+	})
+}
@@ -0,0 +1,13 @@
+package webhooks
+
+import "github.com/labstack/echo/v4"
+
+type Handlers interface {
+	Create() echo.HandlerFunc
+	Update() echo.HandlerFunc
+	Delete() echo.HandlerFunc
+	GetByID() echo.HandlerFunc
+	GetAll() echo.HandlerFunc
+	GetDeliveries() echo.HandlerFunc
+	Redeliver() echo.HandlerFunc
+}
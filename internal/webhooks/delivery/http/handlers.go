@@ -0,0 +1,279 @@
+package http
+
+import (
+	"net/http"
+
+	echo "github.com/labstack/echo/v4"
+	"github.com/realtemirov/task-for-dell/config"
+	"github.com/realtemirov/task-for-dell/internal/models"
+	"github.com/realtemirov/task-for-dell/internal/webhooks"
+
+	"github.com/realtemirov/task-for-dell/pkg/httpErrors"
+	"github.com/realtemirov/task-for-dell/pkg/logger"
+	"github.com/realtemirov/task-for-dell/pkg/utils"
+)
+
+type webhooksHandlers struct {
+	cfg        *config.Config
+	webhooksUC webhooks.UseCase
+	logger     logger.Logger
+}
+
+// NewWebhooksHandlers constructs a new webhooksHandlers.
+func NewWebhooksHandlers(cfg *config.Config, webhooksUC webhooks.UseCase, logger logger.Logger) webhooks.Handlers {
+	return &webhooksHandlers{
+		cfg:        cfg,
+		webhooksUC: webhooksUC,
+		logger:     logger,
+	}
+}
+
+// Create
+// @Summary Create webhook subscription
+// @Description Subscribe a url to content events, the secret signing them is generated unless given and only returned here
+// @Tags Webhooks
+// @Accept  json
+// @Produce  json
+// @Param body body models.WebhookSubscriptionSwagger true "subscription"
+// @Success 201 {object} models.WebhookSubscription
+// @Failure 400 {object} httpErrors.Problem
+// @Failure 500 {object} httpErrors.Problem
+// @Router /webhooks [POST]
+func (h *webhooksHandlers) Create() echo.HandlerFunc {
+	return func(c echo.Context) error {
+
+		var (
+			err          error
+			subscription *models.WebhookSubscription = &models.WebhookSubscription{}
+			created      *models.WebhookSubscription = &models.WebhookSubscription{}
+		)
+
+		// bind request body to subscription
+		if err = c.Bind(subscription); err != nil {
+			return httpErrors.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		created, err = h.webhooksUC.Create(c.Request().Context(), subscription)
+		if err != nil {
+			return httpErrors.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusCreated, created)
+	}
+}
+
+// Update
+// @Summary Update webhook subscription
+// @Description Update webhook subscription, an empty secret keeps the current one
+// @Tags Webhooks
+// @Accept  json
+// @Produce  json
+// @Param id path int true "subscription_id"
+// @Param body body models.WebhookSubscriptionSwagger true "subscription"
+// @Success 200 {object} models.WebhookSubscription
+// @Failure 400 {object} httpErrors.Problem
+// @Failure 404 {object} httpErrors.Problem
+// @Failure 500 {object} httpErrors.Problem
+// @Router /webhooks/{id} [PUT]
+func (h *webhooksHandlers) Update() echo.HandlerFunc {
+	return func(c echo.Context) error {
+
+		var (
+			err            error
+			subscriptionID int64
+			subscription   *models.WebhookSubscription = &models.WebhookSubscription{}
+			updated        *models.WebhookSubscription = &models.WebhookSubscription{}
+		)
+
+		// get subscription id from url
+		subscriptionID, err = utils.StringToInt64(c.Param("id"))
+		if err != nil {
+			return httpErrors.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		// bind request body to subscription
+		if err = c.Bind(subscription); err != nil {
+			return httpErrors.ErrResponseWithLog(c, h.logger, err)
+		}
+		subscription.ID = subscriptionID
+
+		updated, err = h.webhooksUC.Update(c.Request().Context(), subscription)
+		if err != nil {
+			return httpErrors.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, updated)
+	}
+}
+
+// Delete
+// @Summary Delete webhook subscription
+// @Description Delete webhook subscription with its deliveries
+// @Tags Webhooks
+// @Accept  json
+// @Produce  json
+// @Param id path int true "subscription_id"
+// @Success 204 "No Content"
+// @Failure 400 {object} httpErrors.Problem
+// @Failure 404 {object} httpErrors.Problem
+// @Failure 500 {object} httpErrors.Problem
+// @Router /webhooks/{id} [DELETE]
+func (h *webhooksHandlers) Delete() echo.HandlerFunc {
+	return func(c echo.Context) error {
+
+		subscriptionID, err := utils.StringToInt64(c.Param("id"))
+		if err != nil {
+			return httpErrors.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		err = h.webhooksUC.Delete(c.Request().Context(), subscriptionID)
+		if err != nil {
+			return httpErrors.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+// GetByID
+// @Summary GetByID webhook subscription
+// @Description Getting webhook subscription by id
+// @Tags Webhooks
+// @Accept  json
+// @Produce  json
+// @Param id path int true "subscription_id"
+// @Success 200 {object} models.WebhookSubscription
+// @Failure 400 {object} httpErrors.Problem
+// @Failure 404 {object} httpErrors.Problem
+// @Failure 500 {object} httpErrors.Problem
+// @Router /webhooks/{id} [GET]
+func (h *webhooksHandlers) GetByID() echo.HandlerFunc {
+	return func(c echo.Context) error {
+
+		var (
+			err            error
+			subscriptionID int64
+			subscription   *models.WebhookSubscription = &models.WebhookSubscription{}
+		)
+
+		subscriptionID, err = utils.StringToInt64(c.Param("id"))
+		if err != nil {
+			return httpErrors.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		subscription, err = h.webhooksUC.GetByID(c.Request().Context(), subscriptionID)
+		if err != nil {
+			return httpErrors.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, subscription)
+	}
+}
+
+// GetAll
+// @Summary GetAll webhook subscriptions
+// @Description Get every webhook subscription
+// @Tags Webhooks
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} models.WebhookSubscriptionList
+// @Failure 500 {object} httpErrors.Problem
+// @Router /webhooks [GET]
+func (h *webhooksHandlers) GetAll() echo.HandlerFunc {
+	return func(c echo.Context) error {
+
+		subscriptionList, err := h.webhooksUC.GetAll(c.Request().Context())
+		if err != nil {
+			return httpErrors.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, subscriptionList)
+	}
+}
+
+// GetDeliveries
+// @Summary GetDeliveries of webhook subscription
+// @Description Get the delivery log of a webhook subscription with pagination, latest first
+// @Tags Webhooks
+// @Accept  json
+// @Produce  json
+// @Param id path int true "subscription_id"
+// @Param status query string false "pending, delivered or dead"
+// @Param query query utils.Query true "query"
+// @Success 200 {object} models.WebhookDeliveryList
+// @Failure 400 {object} httpErrors.Problem
+// @Failure 404 {object} httpErrors.Problem
+// @Failure 422 {object} httpErrors.Problem
+// @Failure 500 {object} httpErrors.Problem
+// @Router /webhooks/{id}/deliveries [GET]
+func (h *webhooksHandlers) GetDeliveries() echo.HandlerFunc {
+	return func(c echo.Context) error {
+
+		var (
+			err            error
+			subscriptionID int64
+			query          *utils.Query                = &utils.Query{}
+			deliveryList   *models.WebhookDeliveryList = &models.WebhookDeliveryList{}
+		)
+
+		subscriptionID, err = utils.StringToInt64(c.Param("id"))
+		if err != nil {
+			return httpErrors.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		query, err = utils.GetPaginationFromCtx(c)
+		if err != nil {
+			return httpErrors.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		deliveryList, err = h.webhooksUC.GetDeliveries(c.Request().Context(), subscriptionID, c.QueryParam("status"), query)
+		if err != nil {
+			return httpErrors.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		return c.JSON(http.StatusOK, deliveryList)
+	}
+}
+
+// Redeliver
+// @Summary Redeliver webhook delivery
+// @Description Send a delivery of a webhook subscription again, with every attempt, whatever its status
+// @Tags Webhooks
+// @Accept  json
+// @Produce  json
+// @Param id path int true "subscription_id"
+// @Param delivery_id path int true "delivery_id"
+// @Success 202 {object} models.WebhookDelivery
+// @Failure 400 {object} httpErrors.Problem
+// @Failure 404 {object} httpErrors.Problem
+// @Failure 500 {object} httpErrors.Problem
+// @Router /webhooks/{id}/deliveries/{delivery_id}/redeliver [POST]
+func (h *webhooksHandlers) Redeliver() echo.HandlerFunc {
+	return func(c echo.Context) error {
+
+		var (
+			err            error
+			subscriptionID int64
+			deliveryID     int64
+			delivery       *models.WebhookDelivery = &models.WebhookDelivery{}
+		)
+
+		subscriptionID, err = utils.StringToInt64(c.Param("id"))
+		if err != nil {
+			return httpErrors.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		deliveryID, err = utils.StringToInt64(c.Param("delivery_id"))
+		if err != nil {
+			return httpErrors.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		delivery, err = h.webhooksUC.Redeliver(c.Request().Context(), subscriptionID, deliveryID)
+		if err != nil {
+			return httpErrors.ErrResponseWithLog(c, h.logger, err)
+		}
+
+		// the dispatcher sends it on its next poll
+		return c.JSON(http.StatusAccepted, delivery)
+	}
+}
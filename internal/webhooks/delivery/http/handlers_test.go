@@ -0,0 +1,191 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/realtemirov/task-for-dell/config"
+	"github.com/realtemirov/task-for-dell/internal/models"
+	"github.com/realtemirov/task-for-dell/internal/webhooks"
+	"github.com/realtemirov/task-for-dell/internal/webhooks/mock"
+	"github.com/realtemirov/task-for-dell/internal/webhooks/usecase"
+	"github.com/realtemirov/task-for-dell/pkg/domainErrors"
+	"github.com/realtemirov/task-for-dell/pkg/logger"
+	"github.com/realtemirov/task-for-dell/pkg/utils"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// newWebhooksHandlers returns the handlers of webhooks on a mock repository
+func newWebhooksHandlers(t *testing.T) (webhooks.Handlers, *mock.MockRepository) {
+	ctrl := gomock.NewController(t)
+	t.Cleanup(ctrl.Finish)
+
+	cfg, err := config.LoadConfig("./../../../../config/config-local")
+	require.NoError(t, err)
+
+	logger := logger.NewApiLogger(cfg)
+	logger.InitLogger()
+
+	mockWebhooksRepo := mock.NewMockRepository(ctrl)
+	webhooksUC := usecase.NewWebhooksUseCase(cfg, mockWebhooksRepo, logger)
+
+	return NewWebhooksHandlers(cfg, webhooksUC, logger), mockWebhooksRepo
+}
+
+func TestWebhooksHandlers_Create(t *testing.T) {
+	t.Parallel()
+
+	webhooksHandler, mockWebhooksRepo := newWebhooksHandlers(t)
+	handler := webhooksHandler.Create()
+
+	t.Run("Create success case", func(t *testing.T) {
+		bufferData, err := utils.AnyToBytesBuffer(models.WebhookSubscriptionSwagger{
+			URL:        "https://partner.example.com/webhooks",
+			EventTypes: []string{models.EventContentPublished},
+		})
+		require.NoError(t, err)
+
+		request := httptest.NewRequest(http.MethodPost, "/v1/webhooks", strings.NewReader(bufferData.String()))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		response := httptest.NewRecorder()
+
+		e := echo.New()
+		echoCtx := e.NewContext(request, response)
+
+		mockWebhooksRepo.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ interface{}, subscription *models.WebhookSubscription) (*models.WebhookSubscription, error) {
+				subscription.ID = 1
+				return subscription, nil
+			},
+		)
+
+		err = handler(echoCtx)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusCreated, response.Code)
+
+		// the secret is returned once, on create
+		var created models.WebhookSubscription
+		require.NoError(t, json.Unmarshal(response.Body.Bytes(), &created))
+		require.NotEmpty(t, created.Secret)
+	})
+
+	t.Run("Create invalid case", func(t *testing.T) {
+		request := httptest.NewRequest(http.MethodPost, "/v1/webhooks", strings.NewReader(`{"url":"https://partner.example.com/webhooks"}`))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		response := httptest.NewRecorder()
+
+		e := echo.New()
+		echoCtx := e.NewContext(request, response)
+
+		err := handler(echoCtx)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusBadRequest, response.Code)
+	})
+}
+
+func TestWebhooksHandlers_Delete(t *testing.T) {
+	t.Parallel()
+
+	webhooksHandler, mockWebhooksRepo := newWebhooksHandlers(t)
+	handler := webhooksHandler.Delete()
+
+	t.Run("Delete not found case", func(t *testing.T) {
+		request := httptest.NewRequest(http.MethodDelete, "/v1/webhooks/1", nil)
+		response := httptest.NewRecorder()
+
+		e := echo.New()
+		echoCtx := e.NewContext(request, response)
+		echoCtx.SetParamNames("id")
+		echoCtx.SetParamValues("1")
+
+		mockWebhooksRepo.EXPECT().Delete(gomock.Any(), int64(1)).Return(domainErrors.NotFound("record not found", nil))
+
+		err := handler(echoCtx)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusNotFound, response.Code)
+	})
+}
+
+func TestWebhooksHandlers_GetDeliveries(t *testing.T) {
+	t.Parallel()
+
+	webhooksHandler, mockWebhooksRepo := newWebhooksHandlers(t)
+	handler := webhooksHandler.GetDeliveries()
+
+	t.Run("GetDeliveries success case", func(t *testing.T) {
+		request := httptest.NewRequest(http.MethodGet, "/v1/webhooks/1/deliveries?status=dead&limit=5", nil)
+		response := httptest.NewRecorder()
+
+		e := echo.New()
+		echoCtx := e.NewContext(request, response)
+		echoCtx.SetParamNames("id")
+		echoCtx.SetParamValues("1")
+
+		mockWebhooksRepo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(&models.WebhookSubscription{ID: 1}, nil)
+		mockWebhooksRepo.EXPECT().GetDeliveries(gomock.Any(), int64(1), models.WebhookStatusDead, gomock.Any()).Return(
+			&models.WebhookDeliveryList{TotalCount: 1, Deliveries: []*models.WebhookDelivery{{ID: 3, Status: models.WebhookStatusDead}}}, nil,
+		)
+
+		err := handler(echoCtx)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, response.Code)
+	})
+
+	t.Run("GetDeliveries unknown status case", func(t *testing.T) {
+		request := httptest.NewRequest(http.MethodGet, "/v1/webhooks/1/deliveries?status=failed", nil)
+		response := httptest.NewRecorder()
+
+		e := echo.New()
+		echoCtx := e.NewContext(request, response)
+		echoCtx.SetParamNames("id")
+		echoCtx.SetParamValues("1")
+
+		err := handler(echoCtx)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusUnprocessableEntity, response.Code)
+	})
+}
+
+func TestWebhooksHandlers_Redeliver(t *testing.T) {
+	t.Parallel()
+
+	webhooksHandler, mockWebhooksRepo := newWebhooksHandlers(t)
+	handler := webhooksHandler.Redeliver()
+
+	t.Run("Redeliver success case", func(t *testing.T) {
+		request := httptest.NewRequest(http.MethodPost, "/v1/webhooks/1/deliveries/3/redeliver", nil)
+		response := httptest.NewRecorder()
+
+		e := echo.New()
+		echoCtx := e.NewContext(request, response)
+		echoCtx.SetParamNames("id", "delivery_id")
+		echoCtx.SetParamValues("1", "3")
+
+		mockWebhooksRepo.EXPECT().Redeliver(gomock.Any(), int64(1), int64(3)).Return(
+			&models.WebhookDelivery{ID: 3, SubscriptionID: 1, Status: models.WebhookStatusPending}, nil,
+		)
+
+		err := handler(echoCtx)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusAccepted, response.Code)
+	})
+
+	t.Run("Redeliver bad id case", func(t *testing.T) {
+		request := httptest.NewRequest(http.MethodPost, "/v1/webhooks/1/deliveries/abc/redeliver", nil)
+		response := httptest.NewRecorder()
+
+		e := echo.New()
+		echoCtx := e.NewContext(request, response)
+		echoCtx.SetParamNames("id", "delivery_id")
+		echoCtx.SetParamValues("1", "abc")
+
+		err := handler(echoCtx)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusBadRequest, response.Code)
+	})
+}
@@ -0,0 +1,17 @@
+package http
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/realtemirov/task-for-dell/internal/webhooks"
+)
+
+// MapWebhooksRoutes maps routes for webhook subscriptions and their deliveries
+func MapWebhooksRoutes(webhookGroup *echo.Group, h webhooks.Handlers) {
+	webhookGroup.POST("", h.Create())
+	webhookGroup.PUT("/:id", h.Update())
+	webhookGroup.DELETE("/:id", h.Delete())
+	webhookGroup.GET("/:id", h.GetByID())
+	webhookGroup.GET("", h.GetAll())
+	webhookGroup.GET("/:id/deliveries", h.GetDeliveries())
+	webhookGroup.POST("/:id/deliveries/:delivery_id/redeliver", h.Redeliver())
+}
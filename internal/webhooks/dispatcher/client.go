@@ -0,0 +1,32 @@
+package dispatcher
+
+import (
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/realtemirov/task-for-dell/config"
+	"github.com/realtemirov/task-for-dell/pkg/utils"
+)
+
+// NewClient returns the client posting deliveries, each attempt within Timeout. Unless AllowPrivateNetworks,
+// it refuses to connect to addresses which are not public, checked once the host of the url or of a redirect
+// is resolved, and does not go through a proxy which would connect to them instead.
+func NewClient(cfg *config.Config) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if !cfg.Webhooks.AllowPrivateNetworks {
+		dialer := &net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+			Control:   utils.PublicDialControl,
+		}
+		transport.DialContext = dialer.DialContext
+		transport.Proxy = nil
+	}
+
+	return &http.Client{
+		Timeout:   cfg.Webhooks.Timeout * time.Second,
+		Transport: transport,
+	}
+}
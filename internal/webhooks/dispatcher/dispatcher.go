@@ -0,0 +1,202 @@
+package dispatcher
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/realtemirov/task-for-dell/config"
+	"github.com/realtemirov/task-for-dell/internal/models"
+	"github.com/realtemirov/task-for-dell/internal/outbox/sink"
+	"github.com/realtemirov/task-for-dell/internal/webhooks"
+	"github.com/realtemirov/task-for-dell/pkg/logger"
+	"github.com/realtemirov/task-for-dell/pkg/metrics"
+	"github.com/realtemirov/task-for-dell/pkg/tracing"
+)
+
+// RESPONSE_DRAIN_LIMIT is how much of a response body is read, so the connection is reused
+const RESPONSE_DRAIN_LIMIT = 64 << 10
+
+// Dispatcher posts the pending deliveries of webhook subscriptions, signed with their secret.
+// Dispatchers of several instances share the work, each delivery is claimed by the one posting it.
+type Dispatcher struct {
+	cfg    *config.Config
+	repo   webhooks.Repository
+	client *http.Client
+	log    logger.Logger
+}
+
+// Dispatcher constructor, client bounds the time of an attempt with its timeout
+func NewDispatcher(cfg *config.Config, repo webhooks.Repository, client *http.Client, log logger.Logger) *Dispatcher {
+	return &Dispatcher{
+		cfg:    cfg,
+		repo:   repo,
+		client: client,
+		log:    log,
+	}
+}
+
+// Run posts the pending deliveries every PollInterval until ctx is done
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.cfg.Webhooks.PollInterval * time.Second)
+	defer ticker.Stop()
+
+	for {
+		// full batches mean more deliveries are pending
+		for {
+			pending, err := d.Dispatch(ctx)
+			if err != nil && ctx.Err() == nil {
+				d.log.Errorf("webhooks: failed to dispatch deliveries: %v", err)
+			}
+			if err != nil || pending < d.cfg.Webhooks.BatchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Dispatch posts one batch of pending deliveries, Concurrency at a time, and returns how many were pending.
+// The batch is claimed for ClaimTimeout and posted outside of any transaction, the result of every attempt
+// is recorded on its own. The deliveries left when the claim runs out are claimed again later.
+func (d *Dispatcher) Dispatch(ctx context.Context) (int, error) {
+	ctx, span := tracing.Start(ctx, "webhooksDispatcher.Dispatch")
+	defer span.End()
+
+	claimedUntil := time.Now().Add(d.cfg.Webhooks.ClaimTimeout * time.Second)
+	deliveries, err := d.repo.ClaimDeliveries(ctx, d.cfg.Webhooks.BatchSize, claimedUntil)
+	if err != nil {
+		return 0, err
+	}
+
+	postCtx, cancel := context.WithDeadline(ctx, claimedUntil)
+	defer cancel()
+
+	var (
+		wg        sync.WaitGroup
+		left      atomic.Int32
+		semaphore = make(chan struct{}, d.cfg.Webhooks.Concurrency)
+	)
+	for _, delivery := range deliveries {
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func(delivery *models.WebhookDelivery) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+
+			if !d.attempt(postCtx, delivery) {
+				left.Add(1)
+				return
+			}
+
+			// when it is not recorded, the delivery is attempted again once its claim runs out
+			if err := d.repo.UpdateDelivery(ctx, delivery); err != nil {
+				d.log.FromContext(ctx).Errorf("webhooks: failed to record delivery %d: %v", delivery.ID, err)
+			}
+		}(delivery)
+	}
+	wg.Wait()
+
+	if left.Load() > 0 && ctx.Err() == nil {
+		d.log.FromContext(ctx).Warnf("webhooks: claim of %d deliveries ran out, %d left", len(deliveries), left.Load())
+	}
+
+	return len(deliveries), nil
+}
+
+// attempt posts delivery and sets the result: delivered, pending until its next attempt, or dead after MaxAttempts.
+// It returns false, with nothing to record, when ctx is done before the attempt has a result.
+func (d *Dispatcher) attempt(ctx context.Context, delivery *models.WebhookDelivery) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	delivery.Attempts++
+	delivery.ResponseStatus, delivery.LastError = 0, ""
+
+	// a delivery redelivered was delivered before, only the last attempt counts
+	delivery.DeliveredAt = nil
+
+	err := d.post(ctx, delivery)
+	if err != nil && ctx.Err() != nil {
+		return false
+	}
+
+	now := time.Now()
+	switch {
+	case err == nil:
+		delivery.Status = models.WebhookStatusDelivered
+		delivery.DeliveredAt = &now
+		metrics.ObserveWebhook(delivery.EventType, "delivered")
+		return true
+	case delivery.Attempts >= d.cfg.Webhooks.MaxAttempts:
+		delivery.Status = models.WebhookStatusDead
+		metrics.ObserveWebhook(delivery.EventType, "dead")
+		d.log.FromContext(ctx).Warnf("webhooks: delivery %d of subscription %d is dead after %d attempts: %v", delivery.ID, delivery.SubscriptionID, delivery.Attempts, err)
+	default:
+		delivery.Status = models.WebhookStatusPending
+		delivery.NextAttemptAt = now.Add(d.backoff(delivery.Attempts))
+		metrics.ObserveWebhook(delivery.EventType, "failed")
+	}
+	delivery.LastError = err.Error()
+
+	return true
+}
+
+// post sends delivery to the url of its subscription, any status but 2xx is a failure
+func (d *Dispatcher) post(ctx context.Context, delivery *models.WebhookDelivery) error {
+	timestamp := time.Now().Unix()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HEADER_WEBHOOK_ID, strconv.FormatInt(delivery.ID, 10))
+	req.Header.Set(sink.HEADER_EVENT_ID, strconv.FormatInt(delivery.EventID, 10))
+	req.Header.Set(sink.HEADER_EVENT_TYPE, delivery.EventType)
+	req.Header.Set(HEADER_WEBHOOK_TIMESTAMP, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HEADER_WEBHOOK_SIGNATURE, Sign(delivery.Secret, timestamp, delivery.Payload))
+
+	res, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	// drain the body so the connection is reused
+	_, _ = io.Copy(io.Discard, io.LimitReader(res.Body, RESPONSE_DRAIN_LIMIT))
+
+	delivery.ResponseStatus = res.StatusCode
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("webhook answered %s", res.Status)
+	}
+
+	return nil
+}
+
+// backoff returns the wait after the failed attempt of attempts, doubled after every attempt up to MaxRetryBackoff
+func (d *Dispatcher) backoff(attempts int) time.Duration {
+	backoff := d.cfg.Webhooks.RetryBackoff * time.Second
+	maxBackoff := d.cfg.Webhooks.MaxRetryBackoff * time.Second
+
+	for i := 1; i < attempts && backoff < maxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > maxBackoff {
+		backoff = maxBackoff
+	}
+
+	return backoff
+}
@@ -0,0 +1,336 @@
+package dispatcher
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/realtemirov/task-for-dell/config"
+	"github.com/realtemirov/task-for-dell/internal/models"
+	"github.com/realtemirov/task-for-dell/internal/outbox/sink"
+	"github.com/realtemirov/task-for-dell/internal/webhooks/mock"
+	"github.com/realtemirov/task-for-dell/pkg/logger"
+	"github.com/realtemirov/task-for-dell/pkg/utils"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+const secret = "0123456789abcdef"
+
+// receiver is a partner receiving webhooks, it checks their signature and answers status
+type receiver struct {
+	*httptest.Server
+	t        *testing.T
+	status   atomic.Int32
+	received atomic.Int32
+}
+
+func newReceiver(t *testing.T, status int) *receiver {
+	r := &receiver{t: t}
+	r.status.Store(int32(status))
+	r.Server = httptest.NewServer(http.HandlerFunc(r.serveHTTP))
+	t.Cleanup(r.Close)
+
+	return r
+}
+
+func (r *receiver) serveHTTP(w http.ResponseWriter, req *http.Request) {
+	body, err := io.ReadAll(req.Body)
+	require.NoError(r.t, err)
+
+	timestamp, err := strconv.ParseInt(req.Header.Get(HEADER_WEBHOOK_TIMESTAMP), 10, 64)
+	require.NoError(r.t, err)
+	require.InDelta(r.t, time.Now().Unix(), timestamp, 5)
+	require.True(r.t, Verify(secret, timestamp, body, req.Header.Get(HEADER_WEBHOOK_SIGNATURE)))
+	require.Equal(r.t, models.EventContentPublished, req.Header.Get(sink.HEADER_EVENT_TYPE))
+	require.NotEmpty(r.t, req.Header.Get(HEADER_WEBHOOK_ID))
+
+	var event models.Event
+	require.NoError(r.t, json.Unmarshal(body, &event))
+
+	r.received.Add(1)
+	w.WriteHeader(int(r.status.Load()))
+}
+
+// newDispatcher returns a dispatcher on a mock repository
+func newDispatcher(t *testing.T) (*Dispatcher, *mock.MockRepository) {
+	ctrl := gomock.NewController(t)
+	t.Cleanup(ctrl.Finish)
+
+	cfg := &config.Config{Webhooks: config.WebhooksConfig{
+		PollInterval:    1,
+		BatchSize:       10,
+		ClaimTimeout:    30,
+		Concurrency:     2,
+		Timeout:         5,
+		MaxAttempts:     3,
+		RetryBackoff:    10,
+		MaxRetryBackoff: 60,
+	}}
+	repo := mock.NewMockRepository(ctrl)
+
+	return NewDispatcher(cfg, repo, &http.Client{Timeout: time.Second}, logger.NewApiLogger(nil)), repo
+}
+
+// newDelivery returns a pending delivery to url after attempts
+func newDelivery(id int64, url string, attempts int) *models.WebhookDelivery {
+	return &models.WebhookDelivery{
+		ID:             id,
+		SubscriptionID: 1,
+		EventID:        7,
+		EventType:      models.EventContentPublished,
+		Payload:        json.RawMessage(`{"id":7,"type":"content.published","content_type":"news","content_id":1,"payload":{"id":1}}`),
+		Status:         models.WebhookStatusPending,
+		Attempts:       attempts,
+		URL:            url,
+		Secret:         secret,
+	}
+}
+
+func TestDispatcher_Dispatch(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Dispatch delivered", func(t *testing.T) {
+		dispatcher, repo := newDispatcher(t)
+		partner := newReceiver(t, http.StatusOK)
+
+		deliveries := []*models.WebhookDelivery{
+			newDelivery(1, partner.URL, 0),
+			newDelivery(2, partner.URL, 0),
+			newDelivery(3, partner.URL, 0),
+		}
+		repo.EXPECT().ClaimDeliveries(gomock.Any(), 10, gomock.Any()).Return(deliveries, nil)
+		repo.EXPECT().UpdateDelivery(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, delivery *models.WebhookDelivery) error {
+				require.Equal(t, models.WebhookStatusDelivered, delivery.Status)
+				require.Equal(t, 1, delivery.Attempts)
+				require.Equal(t, http.StatusOK, delivery.ResponseStatus)
+				require.Empty(t, delivery.LastError)
+				require.NotNil(t, delivery.DeliveredAt)
+				return nil
+			},
+		).Times(3)
+
+		pending, err := dispatcher.Dispatch(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, 3, pending)
+		require.EqualValues(t, 3, partner.received.Load())
+	})
+
+	t.Run("Dispatch failed", func(t *testing.T) {
+		dispatcher, repo := newDispatcher(t)
+		partner := newReceiver(t, http.StatusServiceUnavailable)
+
+		// retried 10s later and twice longer after its second attempt
+		before := time.Now()
+		redelivered := newDelivery(1, partner.URL, 0)
+		redelivered.DeliveredAt = &before
+		repo.EXPECT().ClaimDeliveries(gomock.Any(), 10, gomock.Any()).Return([]*models.WebhookDelivery{
+			redelivered,
+			newDelivery(2, partner.URL, 1),
+		}, nil)
+		repo.EXPECT().UpdateDelivery(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, delivery *models.WebhookDelivery) error {
+				require.Equal(t, models.WebhookStatusPending, delivery.Status)
+				require.Equal(t, http.StatusServiceUnavailable, delivery.ResponseStatus)
+				require.Equal(t, "webhook answered 503 Service Unavailable", delivery.LastError)
+
+				// a redelivery delivered before is not delivered anymore
+				require.Nil(t, delivery.DeliveredAt)
+
+				wait := map[int]time.Duration{1: 10 * time.Second, 2: 20 * time.Second}[delivery.Attempts]
+				require.WithinDuration(t, before.Add(wait), delivery.NextAttemptAt, time.Second)
+				return nil
+			},
+		).Times(2)
+
+		_, err := dispatcher.Dispatch(context.Background())
+		require.NoError(t, err)
+		require.EqualValues(t, 2, partner.received.Load())
+	})
+
+	t.Run("Dispatch dead", func(t *testing.T) {
+		dispatcher, repo := newDispatcher(t)
+		partner := newReceiver(t, http.StatusInternalServerError)
+
+		// the last of MaxAttempts fails
+		repo.EXPECT().ClaimDeliveries(gomock.Any(), 10, gomock.Any()).Return([]*models.WebhookDelivery{newDelivery(1, partner.URL, 2)}, nil)
+		repo.EXPECT().UpdateDelivery(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, delivery *models.WebhookDelivery) error {
+				require.Equal(t, models.WebhookStatusDead, delivery.Status)
+				require.Equal(t, 3, delivery.Attempts)
+				return nil
+			},
+		)
+
+		_, err := dispatcher.Dispatch(context.Background())
+		require.NoError(t, err)
+	})
+
+	t.Run("Dispatch unreachable", func(t *testing.T) {
+		dispatcher, repo := newDispatcher(t)
+		partner := newReceiver(t, http.StatusOK)
+		partner.Close()
+
+		repo.EXPECT().ClaimDeliveries(gomock.Any(), 10, gomock.Any()).Return([]*models.WebhookDelivery{newDelivery(1, partner.URL, 0)}, nil)
+		repo.EXPECT().UpdateDelivery(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, delivery *models.WebhookDelivery) error {
+				require.Equal(t, models.WebhookStatusPending, delivery.Status)
+				require.Zero(t, delivery.ResponseStatus)
+				require.NotEmpty(t, delivery.LastError)
+				return nil
+			},
+		)
+
+		_, err := dispatcher.Dispatch(context.Background())
+		require.NoError(t, err)
+	})
+
+	t.Run("Dispatch to a private network", func(t *testing.T) {
+		dispatcher, repo := newDispatcher(t)
+		partner := newReceiver(t, http.StatusOK)
+
+		// the partner listens on loopback, the client of the server refuses to connect
+		dispatcher.client = NewClient(dispatcher.cfg)
+
+		repo.EXPECT().ClaimDeliveries(gomock.Any(), 10, gomock.Any()).Return([]*models.WebhookDelivery{newDelivery(1, partner.URL, 0)}, nil)
+		repo.EXPECT().UpdateDelivery(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, delivery *models.WebhookDelivery) error {
+				require.Equal(t, models.WebhookStatusPending, delivery.Status)
+				require.Contains(t, delivery.LastError, utils.ErrPrivateNetwork.Error())
+				return nil
+			},
+		)
+
+		_, err := dispatcher.Dispatch(context.Background())
+		require.NoError(t, err)
+		require.Zero(t, partner.received.Load())
+	})
+
+	t.Run("Dispatch to a private network allowed", func(t *testing.T) {
+		dispatcher, repo := newDispatcher(t)
+		partner := newReceiver(t, http.StatusOK)
+
+		dispatcher.cfg.Webhooks.AllowPrivateNetworks = true
+		dispatcher.client = NewClient(dispatcher.cfg)
+
+		repo.EXPECT().ClaimDeliveries(gomock.Any(), 10, gomock.Any()).Return([]*models.WebhookDelivery{newDelivery(1, partner.URL, 0)}, nil)
+		repo.EXPECT().UpdateDelivery(gomock.Any(), gomock.Any()).Return(nil)
+
+		_, err := dispatcher.Dispatch(context.Background())
+		require.NoError(t, err)
+		require.EqualValues(t, 1, partner.received.Load())
+	})
+
+	t.Run("Dispatch failed to record", func(t *testing.T) {
+		dispatcher, repo := newDispatcher(t)
+		partner := newReceiver(t, http.StatusOK)
+
+		// every result is recorded on its own, the one failing is attempted again once its claim runs out
+		repo.EXPECT().ClaimDeliveries(gomock.Any(), 10, gomock.Any()).Return([]*models.WebhookDelivery{
+			newDelivery(1, partner.URL, 0),
+			newDelivery(2, partner.URL, 0),
+		}, nil)
+		repo.EXPECT().UpdateDelivery(gomock.Any(), gomock.Any()).Return(errors.New("connection refused"))
+		repo.EXPECT().UpdateDelivery(gomock.Any(), gomock.Any()).Return(nil)
+
+		pending, err := dispatcher.Dispatch(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, 2, pending)
+		require.EqualValues(t, 2, partner.received.Load())
+	})
+
+	t.Run("Dispatch claim ran out", func(t *testing.T) {
+		dispatcher, repo := newDispatcher(t)
+		dispatcher.cfg.Webhooks.ClaimTimeout = 1
+		dispatcher.client = &http.Client{Timeout: 5 * time.Second}
+
+		// the partner answers after the claim ran out, nothing is recorded
+		partner := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			select {
+			case <-req.Context().Done():
+			case <-time.After(3 * time.Second):
+			}
+		}))
+		t.Cleanup(partner.Close)
+
+		repo.EXPECT().ClaimDeliveries(gomock.Any(), 10, gomock.Any()).DoAndReturn(
+			func(ctx context.Context, limit int, until time.Time) ([]*models.WebhookDelivery, error) {
+				require.WithinDuration(t, time.Now().Add(time.Second), until, 100*time.Millisecond)
+				return []*models.WebhookDelivery{newDelivery(1, partner.URL, 0)}, nil
+			},
+		)
+
+		pending, err := dispatcher.Dispatch(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, 1, pending)
+	})
+
+	t.Run("Dispatch failed to claim", func(t *testing.T) {
+		dispatcher, repo := newDispatcher(t)
+
+		repo.EXPECT().ClaimDeliveries(gomock.Any(), 10, gomock.Any()).Return(nil, errors.New("connection refused"))
+
+		_, err := dispatcher.Dispatch(context.Background())
+		require.Error(t, err)
+	})
+}
+
+func TestDispatcher_backoff(t *testing.T) {
+	t.Parallel()
+
+	dispatcher, _ := newDispatcher(t)
+
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, 10 * time.Second},
+		{2, 20 * time.Second},
+		{3, 40 * time.Second},
+		{4, time.Minute},
+		{100, time.Minute},
+	}
+
+	for _, tt := range tests {
+		require.Equal(t, tt.want, dispatcher.backoff(tt.attempts), "attempts %d", tt.attempts)
+	}
+}
+
+func TestSign(t *testing.T) {
+	t.Parallel()
+
+	body := []byte(`{"id":1}`)
+	signature := Sign(secret, 1700000000, body)
+
+	// the hex HMAC-SHA256 of "1700000000.{"id":1}"
+	require.Len(t, signature, len(SIGNATURE_PREFIX)+64)
+	require.True(t, Verify(secret, 1700000000, body, signature))
+	require.False(t, Verify("another secret", 1700000000, body, signature))
+	require.False(t, Verify(secret, 1700000001, body, signature))
+	require.False(t, Verify(secret, 1700000000, []byte(`{"id":2}`), signature))
+	require.False(t, Verify(secret, 1700000000, body, signature[len(SIGNATURE_PREFIX):]))
+}
+
+func TestSubscriptionsSink_Publish(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mock.NewMockRepository(ctrl)
+	event := &models.Event{ID: 7, Type: models.EventContentPublished, ContentType: models.ContentTypeNews}
+
+	repo.EXPECT().AddDeliveries(gomock.Any(), event).Return(int64(2), nil)
+	require.NoError(t, NewSubscriptionsSink(repo).Publish(context.Background(), event))
+
+	repo.EXPECT().AddDeliveries(gomock.Any(), event).Return(int64(0), errors.New("connection refused"))
+	require.Error(t, NewSubscriptionsSink(repo).Publish(context.Background(), event))
+}
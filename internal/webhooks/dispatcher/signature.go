@@ -0,0 +1,38 @@
+package dispatcher
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+)
+
+const (
+	HEADER_WEBHOOK_ID        = "X-Webhook-ID"
+	HEADER_WEBHOOK_TIMESTAMP = "X-Webhook-Timestamp"
+	HEADER_WEBHOOK_SIGNATURE = "X-Webhook-Signature"
+
+	// SIGNATURE_PREFIX names the algorithm of a signature
+	SIGNATURE_PREFIX = "sha256="
+)
+
+// Sign returns the signature of body sent at timestamp, in unix seconds: the hex HMAC-SHA256 with secret
+// of "<timestamp>.<body>". Receivers check it, and that timestamp is recent, to reject forged and replayed requests.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte{'.'})
+	mac.Write(body)
+
+	return SIGNATURE_PREFIX + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify reports whether signature is the signature of body sent at timestamp with secret, in constant time
+func Verify(secret string, timestamp int64, body []byte, signature string) bool {
+	if !strings.HasPrefix(signature, SIGNATURE_PREFIX) {
+		return false
+	}
+
+	return hmac.Equal([]byte(Sign(secret, timestamp, body)), []byte(signature))
+}
@@ -0,0 +1,26 @@
+package dispatcher
+
+import (
+	"context"
+
+	"github.com/realtemirov/task-for-dell/internal/models"
+	"github.com/realtemirov/task-for-dell/internal/outbox"
+	"github.com/realtemirov/task-for-dell/internal/webhooks"
+)
+
+// subscriptionsSink adds a delivery of every event relayed to each subscription of it. An event relayed
+// again adds none, so the relay may retry it.
+type subscriptionsSink struct {
+	repo webhooks.Repository
+}
+
+// NewSubscriptionsSink constructor
+func NewSubscriptionsSink(repo webhooks.Repository) outbox.Sink {
+	return &subscriptionsSink{repo: repo}
+}
+
+// Publish implements outbox.Sink.
+func (s *subscriptionsSink) Publish(ctx context.Context, event *models.Event) error {
+	_, err := s.repo.AddDeliveries(ctx, event)
+	return err
+}
@@ -0,0 +1,22 @@
+package webhooks
+
+import (
+	"context"
+	"time"
+
+	"github.com/realtemirov/task-for-dell/internal/models"
+	"github.com/realtemirov/task-for-dell/pkg/utils"
+)
+
+type Repository interface {
+	Create(ctx context.Context, subscription *models.WebhookSubscription) (*models.WebhookSubscription, error)
+	Update(ctx context.Context, subscription *models.WebhookSubscription) (*models.WebhookSubscription, error)
+	Delete(ctx context.Context, subscriptionID int64) error
+	GetByID(ctx context.Context, subscriptionID int64) (*models.WebhookSubscription, error)
+	GetAll(ctx context.Context) ([]*models.WebhookSubscription, error)
+	AddDeliveries(ctx context.Context, event *models.Event) (int64, error)
+	GetDeliveries(ctx context.Context, subscriptionID int64, status string, query *utils.Query) (*models.WebhookDeliveryList, error)
+	Redeliver(ctx context.Context, subscriptionID, deliveryID int64) (*models.WebhookDelivery, error)
+	ClaimDeliveries(ctx context.Context, limit int, until time.Time) ([]*models.WebhookDelivery, error)
+	UpdateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error
+}
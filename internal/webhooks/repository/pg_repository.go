@@ -0,0 +1,330 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/realtemirov/task-for-dell/internal/models"
+	"github.com/realtemirov/task-for-dell/internal/webhooks"
+	"github.com/realtemirov/task-for-dell/pkg/db/postgres"
+	"github.com/realtemirov/task-for-dell/pkg/metrics"
+	"github.com/realtemirov/task-for-dell/pkg/tracing"
+	"github.com/realtemirov/task-for-dell/pkg/utils"
+)
+
+type webhooksRepo struct {
+	db *postgres.DB
+}
+
+// NewWebhooksRepository constructor
+func NewWebhooksRepository(db *postgres.DB) webhooks.Repository {
+	return &webhooksRepo{db: db}
+}
+
+// subscriptionRow is a subscription as scanned, its types are json arrays
+type subscriptionRow struct {
+	models.WebhookSubscription
+	EventTypes   []byte `db:"event_types"`
+	ContentTypes []byte `db:"content_types"`
+}
+
+// subscription returns the subscription of the row
+func (row *subscriptionRow) subscription() (*models.WebhookSubscription, error) {
+	subscription := row.WebhookSubscription
+	if err := json.Unmarshal(row.EventTypes, &subscription.EventTypes); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(row.ContentTypes, &subscription.ContentTypes); err != nil {
+		return nil, err
+	}
+
+	return &subscription, nil
+}
+
+// deliveryRow is a delivery as scanned, the driver may return the payload as text or bytes
+type deliveryRow struct {
+	models.WebhookDelivery
+	Payload []byte `db:"payload"`
+}
+
+// delivery returns the delivery of the row
+func (row *deliveryRow) delivery() *models.WebhookDelivery {
+	delivery := row.WebhookDelivery
+	delivery.Payload = row.Payload
+	return &delivery
+}
+
+// jsonArray returns values as a json array, [] when empty
+func jsonArray(values []string) string {
+	if len(values) == 0 {
+		return "[]"
+	}
+
+	data, _ := json.Marshal(values)
+	return string(data)
+}
+
+// Create implements webhooks.Repository.
+func (r *webhooksRepo) Create(ctx context.Context, subscription *models.WebhookSubscription) (*models.WebhookSubscription, error) {
+	ctx, span := tracing.Start(ctx, "webhooksRepo.Create")
+	defer span.End()
+	defer metrics.ObserveQuery("webhooks", "Create", time.Now())
+
+	row := subscriptionRow{}
+	if err := r.db.Writer(ctx).QueryRowxContext(
+		ctx,
+		createQuery,
+		subscription.URL,
+		subscription.Secret,
+		jsonArray(subscription.EventTypes),
+		jsonArray(subscription.ContentTypes),
+		subscription.Paused,
+	).StructScan(&row); err != nil {
+		return nil, errors.Wrap(postgres.MapError(err), "webhooksRepo.Create.StructScan")
+	}
+
+	result, err := row.subscription()
+	if err != nil {
+		return nil, errors.Wrap(err, "webhooksRepo.Create.subscription")
+	}
+
+	return result, nil
+}
+
+// Update implements webhooks.Repository.
+func (r *webhooksRepo) Update(ctx context.Context, subscription *models.WebhookSubscription) (*models.WebhookSubscription, error) {
+	ctx, span := tracing.Start(ctx, "webhooksRepo.Update")
+	defer span.End()
+	defer metrics.ObserveQuery("webhooks", "Update", time.Now())
+
+	row := subscriptionRow{}
+	if err := r.db.Writer(ctx).QueryRowxContext(
+		ctx,
+		updateQuery,
+		subscription.ID,
+		subscription.URL,
+		subscription.Secret,
+		jsonArray(subscription.EventTypes),
+		jsonArray(subscription.ContentTypes),
+		subscription.Paused,
+	).StructScan(&row); err != nil {
+		return nil, errors.Wrap(postgres.MapError(err), "webhooksRepo.Update.StructScan")
+	}
+
+	result, err := row.subscription()
+	if err != nil {
+		return nil, errors.Wrap(err, "webhooksRepo.Update.subscription")
+	}
+
+	return result, nil
+}
+
+// Delete implements webhooks.Repository.
+func (r *webhooksRepo) Delete(ctx context.Context, subscriptionID int64) error {
+	ctx, span := tracing.Start(ctx, "webhooksRepo.Delete")
+	defer span.End()
+	defer metrics.ObserveQuery("webhooks", "Delete", time.Now())
+
+	result, err := r.db.Writer(ctx).ExecContext(ctx, deleteQuery, subscriptionID)
+	if err != nil {
+		return errors.Wrap(postgres.MapError(err), "webhooksRepo.Delete.ExecContext")
+	}
+
+	// if didn't rows affected, return error
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(postgres.MapError(err), "webhooksRepo.Delete.RowsAffected")
+	}
+	if rowsAffected == 0 {
+		return errors.Wrap(postgres.MapError(sql.ErrNoRows), "webhooksRepo.Delete.RowsAffected")
+	}
+
+	return nil
+}
+
+// GetByID implements webhooks.Repository.
+func (r *webhooksRepo) GetByID(ctx context.Context, subscriptionID int64) (*models.WebhookSubscription, error) {
+	ctx, span := tracing.Start(ctx, "webhooksRepo.GetByID")
+	defer span.End()
+	defer metrics.ObserveQuery("webhooks", "GetByID", time.Now())
+
+	row := subscriptionRow{}
+	if err := r.db.Reader(ctx).QueryRowxContext(ctx, getByIDQuery, subscriptionID).StructScan(&row); err != nil {
+		return nil, errors.Wrap(postgres.MapError(err), "webhooksRepo.GetByID.StructScan")
+	}
+
+	result, err := row.subscription()
+	if err != nil {
+		return nil, errors.Wrap(err, "webhooksRepo.GetByID.subscription")
+	}
+
+	return result, nil
+}
+
+// GetAll implements webhooks.Repository.
+func (r *webhooksRepo) GetAll(ctx context.Context) ([]*models.WebhookSubscription, error) {
+	ctx, span := tracing.Start(ctx, "webhooksRepo.GetAll")
+	defer span.End()
+	defer metrics.ObserveQuery("webhooks", "GetAll", time.Now())
+
+	rows := make([]*subscriptionRow, 0)
+	if err := r.db.Reader(ctx).SelectContext(ctx, &rows, getAllQuery); err != nil {
+		return nil, errors.Wrap(postgres.MapError(err), "webhooksRepo.GetAll.SelectContext")
+	}
+
+	result := make([]*models.WebhookSubscription, 0, len(rows))
+	for _, row := range rows {
+		subscription, err := row.subscription()
+		if err != nil {
+			return nil, errors.Wrap(err, "webhooksRepo.GetAll.subscription")
+		}
+		result = append(result, subscription)
+	}
+
+	return result, nil
+}
+
+// AddDeliveries implements webhooks.Repository, in a savepoint so a failure keeps the transaction of ctx usable.
+func (r *webhooksRepo) AddDeliveries(ctx context.Context, event *models.Event) (int64, error) {
+	ctx, span := tracing.Start(ctx, "webhooksRepo.AddDeliveries")
+	defer span.End()
+	defer metrics.ObserveQuery("webhooks", "AddDeliveries", time.Now())
+
+	// subscribers receive the event as the other sinks do
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return 0, errors.Wrap(err, "webhooksRepo.AddDeliveries.Marshal")
+	}
+
+	var added int64
+	err = r.db.WithinSavepoint(ctx, func(ctx context.Context) error {
+		result, err := r.db.Writer(ctx).ExecContext(
+			ctx,
+			addDeliveriesQuery,
+			event.ID,
+			event.Type,
+			string(payload),
+			event.ContentType,
+		)
+		if err != nil {
+			return errors.Wrap(postgres.MapError(err), "webhooksRepo.AddDeliveries.ExecContext")
+		}
+
+		if added, err = result.RowsAffected(); err != nil {
+			return errors.Wrap(postgres.MapError(err), "webhooksRepo.AddDeliveries.RowsAffected")
+		}
+
+		return nil
+	})
+
+	return added, err
+}
+
+// GetDeliveries implements webhooks.Repository.
+func (r *webhooksRepo) GetDeliveries(ctx context.Context, subscriptionID int64, status string, query *utils.Query) (*models.WebhookDeliveryList, error) {
+	ctx, span := tracing.Start(ctx, "webhooksRepo.GetDeliveries")
+	defer span.End()
+	defer metrics.ObserveQuery("webhooks", "GetDeliveries", time.Now())
+
+	// the count and the rows are read from one database, replicas may lag differently
+	reader := r.db.Reader(ctx)
+
+	// get total count
+	var totalCount int
+	if err := reader.QueryRowContext(
+		ctx,
+		getDeliveriesCountQuery,
+		subscriptionID,
+		status,
+	).Scan(&totalCount); err != nil {
+		return nil, errors.Wrap(postgres.MapError(err), "webhooksRepo.GetDeliveries.QueryRowContext.Scan")
+	}
+
+	rows := make([]*deliveryRow, 0, query.GetLimit())
+	if totalCount > 0 {
+		if err := reader.SelectContext(
+			ctx,
+			&rows,
+			getDeliveriesQuery,
+			subscriptionID,
+			status,
+			query.GetOffset(),
+			query.GetLimit(),
+		); err != nil {
+			return nil, errors.Wrap(postgres.MapError(err), "webhooksRepo.GetDeliveries.SelectContext")
+		}
+	}
+
+	deliveries := make([]*models.WebhookDelivery, 0, len(rows))
+	for _, row := range rows {
+		deliveries = append(deliveries, row.delivery())
+	}
+
+	return &models.WebhookDeliveryList{
+		TotalCount: totalCount,
+		TotalPage:  utils.GetTotalPages(totalCount, query.GetLimit()),
+		Page:       query.GetPage(),
+		Limit:      query.GetLimit(),
+		HasMore:    utils.GetHasMore(query.GetPage(), totalCount, query.GetLimit()),
+		Deliveries: deliveries,
+	}, nil
+}
+
+// Redeliver implements webhooks.Repository.
+func (r *webhooksRepo) Redeliver(ctx context.Context, subscriptionID, deliveryID int64) (*models.WebhookDelivery, error) {
+	ctx, span := tracing.Start(ctx, "webhooksRepo.Redeliver")
+	defer span.End()
+	defer metrics.ObserveQuery("webhooks", "Redeliver", time.Now())
+
+	row := deliveryRow{}
+	if err := r.db.Writer(ctx).QueryRowxContext(ctx, redeliverQuery, deliveryID, subscriptionID).StructScan(&row); err != nil {
+		return nil, errors.Wrap(postgres.MapError(err), "webhooksRepo.Redeliver.StructScan")
+	}
+
+	return row.delivery(), nil
+}
+
+// ClaimDeliveries implements webhooks.Repository, the deliveries are not pending again before until, nothing stays locked.
+func (r *webhooksRepo) ClaimDeliveries(ctx context.Context, limit int, until time.Time) ([]*models.WebhookDelivery, error) {
+	ctx, span := tracing.Start(ctx, "webhooksRepo.ClaimDeliveries")
+	defer span.End()
+	defer metrics.ObserveQuery("webhooks", "ClaimDeliveries", time.Now())
+
+	rows := make([]*deliveryRow, 0, limit)
+	if err := r.db.Writer(ctx).SelectContext(ctx, &rows, claimDeliveriesQuery, limit, until); err != nil {
+		return nil, errors.Wrap(postgres.MapError(err), "webhooksRepo.ClaimDeliveries.SelectContext")
+	}
+
+	deliveries := make([]*models.WebhookDelivery, 0, len(rows))
+	for _, row := range rows {
+		deliveries = append(deliveries, row.delivery())
+	}
+
+	return deliveries, nil
+}
+
+// UpdateDelivery implements webhooks.Repository.
+func (r *webhooksRepo) UpdateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	ctx, span := tracing.Start(ctx, "webhooksRepo.UpdateDelivery")
+	defer span.End()
+	defer metrics.ObserveQuery("webhooks", "UpdateDelivery", time.Now())
+
+	if _, err := r.db.Writer(ctx).ExecContext(
+		ctx,
+		updateDeliveryQuery,
+		delivery.ID,
+		delivery.Status,
+		delivery.Attempts,
+		delivery.ResponseStatus,
+		delivery.LastError,
+		delivery.NextAttemptAt,
+		delivery.DeliveredAt,
+	); err != nil {
+		return errors.Wrap(postgres.MapError(err), "webhooksRepo.UpdateDelivery.ExecContext")
+	}
+
+	return nil
+}
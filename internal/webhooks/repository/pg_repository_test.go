@@ -0,0 +1,275 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/realtemirov/task-for-dell/internal/models"
+	"github.com/realtemirov/task-for-dell/pkg/db/postgres"
+	"github.com/realtemirov/task-for-dell/pkg/domainErrors"
+	"github.com/realtemirov/task-for-dell/pkg/utils"
+	"github.com/stretchr/testify/require"
+)
+
+// columns of the subscriptions and deliveries read
+var (
+	subscriptionColumns = []string{"id", "url", "event_types", "content_types", "paused", "created_at", "updated_at"}
+	deliveryColumns     = []string{
+		"id", "subscription_id", "event_id", "event_type", "payload", "status", "attempts",
+		"response_status", "last_error", "next_attempt_at", "created_at", "updated_at", "delivered_at",
+	}
+)
+
+// newWebhooksRepo returns the webhooks repository on a mock db, and its db
+func newWebhooksRepo(t *testing.T) (*postgres.DB, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	return postgres.NewDB(sqlx.NewDb(db, "sqlmock")), mock
+}
+
+// TestWebhooksRepo_Subscriptions tests Create, Update, Delete, GetByID and GetAll methods.
+func TestWebhooksRepo_Subscriptions(t *testing.T) {
+	t.Parallel()
+
+	db, mock := newWebhooksRepo(t)
+	repo := NewWebhooksRepository(db)
+	now := time.Now()
+
+	subscription := &models.WebhookSubscription{
+		URL:        "https://partner.example.com/webhooks",
+		Secret:     "0123456789abcdef",
+		EventTypes: []string{models.EventContentPublished},
+	}
+
+	t.Run("Create", func(t *testing.T) {
+
+		// the types are json arrays, no content types is every content type
+		mock.ExpectQuery(createQuery).WithArgs(
+			subscription.URL, subscription.Secret, `["content.published"]`, `[]`, false,
+		).WillReturnRows(
+			sqlmock.NewRows(append(subscriptionColumns, "secret")).AddRow(
+				int64(1), subscription.URL, []byte(`["content.published"]`), []byte(`[]`), false, now, now, subscription.Secret,
+			),
+		)
+
+		created, err := repo.Create(context.Background(), subscription)
+		require.NoError(t, err)
+		require.EqualValues(t, 1, created.ID)
+		require.Equal(t, subscription.Secret, created.Secret)
+		require.Equal(t, []string{models.EventContentPublished}, created.EventTypes)
+		require.Empty(t, created.ContentTypes)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Update", func(t *testing.T) {
+
+		updated := &models.WebhookSubscription{
+			ID:           1,
+			URL:          subscription.URL,
+			EventTypes:   []string{models.EventContentCreated, models.EventContentDeleted},
+			ContentTypes: []string{models.ContentTypeNews},
+			Paused:       true,
+		}
+		mock.ExpectQuery(updateQuery).WithArgs(
+			int64(1), updated.URL, "", `["content.created","content.deleted"]`, `["news"]`, true,
+		).WillReturnRows(
+			sqlmock.NewRows(subscriptionColumns).AddRow(
+				int64(1), updated.URL, `["content.created","content.deleted"]`, `["news"]`, true, now, now,
+			),
+		)
+
+		result, err := repo.Update(context.Background(), updated)
+		require.NoError(t, err)
+		require.Equal(t, updated.EventTypes, result.EventTypes)
+		require.Equal(t, updated.ContentTypes, result.ContentTypes)
+		require.True(t, result.Paused)
+		require.Empty(t, result.Secret)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("GetByID not found", func(t *testing.T) {
+
+		mock.ExpectQuery(getByIDQuery).WithArgs(int64(2)).WillReturnError(sql.ErrNoRows)
+
+		_, err := repo.GetByID(context.Background(), 2)
+		require.Equal(t, domainErrors.KindNotFound, domainErrors.KindOf(err))
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("GetAll", func(t *testing.T) {
+
+		mock.ExpectQuery(getAllQuery).WillReturnRows(
+			sqlmock.NewRows(subscriptionColumns).AddRow(
+				int64(1), subscription.URL, `["content.published"]`, `["news"]`, false, now, now,
+			).AddRow(
+				int64(2), subscription.URL, `["content.deleted"]`, `[]`, true, now, now,
+			),
+		)
+
+		result, err := repo.GetAll(context.Background())
+		require.NoError(t, err)
+		require.Len(t, result, 2)
+		require.Equal(t, []string{models.ContentTypeNews}, result[0].ContentTypes)
+		require.Equal(t, []string{models.EventContentDeleted}, result[1].EventTypes)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+
+		mock.ExpectExec(deleteQuery).WithArgs(int64(1)).WillReturnResult(sqlmock.NewResult(0, 1))
+		require.NoError(t, repo.Delete(context.Background(), 1))
+
+		mock.ExpectExec(deleteQuery).WithArgs(int64(2)).WillReturnResult(sqlmock.NewResult(0, 0))
+		err := repo.Delete(context.Background(), 2)
+		require.Equal(t, domainErrors.KindNotFound, domainErrors.KindOf(err))
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+// TestWebhooksRepo_AddDeliveries tests AddDeliveries method.
+func TestWebhooksRepo_AddDeliveries(t *testing.T) {
+	t.Parallel()
+
+	db, mock := newWebhooksRepo(t)
+	repo := NewWebhooksRepository(db)
+
+	event := &models.Event{
+		ID:          7,
+		Type:        models.EventContentPublished,
+		ContentType: models.ContentTypeNews,
+		ContentID:   1,
+		Payload:     json.RawMessage(`{"id":1}`),
+	}
+	payload, err := json.Marshal(event)
+	require.NoError(t, err)
+
+	// in a transaction, a savepoint keeps it usable when the insert fails
+	t.Run("AddDeliveries", func(t *testing.T) {
+
+		mock.ExpectBegin()
+		mock.ExpectExec("SAVEPOINT sp_1").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(addDeliveriesQuery).WithArgs(
+			int64(7), models.EventContentPublished, string(payload), models.ContentTypeNews,
+		).WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectExec("RELEASE SAVEPOINT sp_1").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		var added int64
+		err := db.WithinTx(context.Background(), func(ctx context.Context) error {
+			var err error
+			added, err = repo.AddDeliveries(ctx, event)
+			return err
+		})
+
+		require.NoError(t, err)
+		require.EqualValues(t, 2, added)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+// TestWebhooksRepo_Deliveries tests GetDeliveries, Redeliver, ClaimDeliveries and UpdateDelivery methods.
+func TestWebhooksRepo_Deliveries(t *testing.T) {
+	t.Parallel()
+
+	db, mock := newWebhooksRepo(t)
+	repo := NewWebhooksRepository(db)
+	now := time.Now()
+
+	t.Run("GetDeliveries", func(t *testing.T) {
+
+		query := &utils.Query{Limit: 10, Page: 1}
+		mock.ExpectQuery(getDeliveriesCountQuery).WithArgs(int64(1), models.WebhookStatusDead).WillReturnRows(
+			sqlmock.NewRows([]string{"count"}).AddRow(1),
+		)
+		mock.ExpectQuery(getDeliveriesQuery).WithArgs(int64(1), models.WebhookStatusDead, 0, 10).WillReturnRows(
+			sqlmock.NewRows(deliveryColumns).AddRow(
+				int64(3), int64(1), int64(7), models.EventContentPublished, `{"id":7}`, models.WebhookStatusDead, 8,
+				503, "webhook answered 503 Service Unavailable", now, now, now, nil,
+			),
+		)
+
+		result, err := repo.GetDeliveries(context.Background(), 1, models.WebhookStatusDead, query)
+		require.NoError(t, err)
+		require.Equal(t, 1, result.TotalCount)
+		require.Len(t, result.Deliveries, 1)
+		require.Equal(t, json.RawMessage(`{"id":7}`), result.Deliveries[0].Payload)
+		require.Equal(t, 503, result.Deliveries[0].ResponseStatus)
+		require.Nil(t, result.Deliveries[0].DeliveredAt)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("GetDeliveries empty", func(t *testing.T) {
+
+		query := &utils.Query{Limit: 10, Page: 1}
+		mock.ExpectQuery(getDeliveriesCountQuery).WithArgs(int64(1), "").WillReturnRows(
+			sqlmock.NewRows([]string{"count"}).AddRow(0),
+		)
+
+		result, err := repo.GetDeliveries(context.Background(), 1, "", query)
+		require.NoError(t, err)
+		require.Empty(t, result.Deliveries)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Redeliver", func(t *testing.T) {
+
+		mock.ExpectQuery(redeliverQuery).WithArgs(int64(3), int64(1)).WillReturnRows(
+			sqlmock.NewRows(deliveryColumns).AddRow(
+				int64(3), int64(1), int64(7), models.EventContentPublished, `{"id":7}`, models.WebhookStatusPending, 0,
+				503, "webhook answered 503 Service Unavailable", now, now, now, nil,
+			),
+		)
+
+		result, err := repo.Redeliver(context.Background(), 1, 3)
+		require.NoError(t, err)
+		require.Equal(t, models.WebhookStatusPending, result.Status)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Redeliver not found", func(t *testing.T) {
+
+		mock.ExpectQuery(redeliverQuery).WithArgs(int64(4), int64(1)).WillReturnError(sql.ErrNoRows)
+
+		_, err := repo.Redeliver(context.Background(), 1, 4)
+		require.Equal(t, domainErrors.KindNotFound, domainErrors.KindOf(err))
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("ClaimDeliveries and UpdateDelivery", func(t *testing.T) {
+
+		until := now.Add(2 * time.Minute)
+		mock.ExpectQuery(claimDeliveriesQuery).WithArgs(10, until).WillReturnRows(
+			sqlmock.NewRows(append(deliveryColumns, "url", "secret")).AddRow(
+				int64(3), int64(1), int64(7), models.EventContentPublished, []byte(`{"id":7}`), models.WebhookStatusPending, 0,
+				0, "", until, now, now, nil, "https://partner.example.com/webhooks", "0123456789abcdef",
+			),
+		)
+		mock.ExpectExec(updateDeliveryQuery).WithArgs(
+			int64(3), models.WebhookStatusDelivered, 1, 200, "", now, &now,
+		).WillReturnResult(sqlmock.NewResult(0, 1))
+
+		// claimed and recorded without a transaction
+		deliveries, err := repo.ClaimDeliveries(context.Background(), 10, until)
+		require.NoError(t, err)
+		require.Len(t, deliveries, 1)
+		require.Equal(t, "https://partner.example.com/webhooks", deliveries[0].URL)
+		require.Equal(t, "0123456789abcdef", deliveries[0].Secret)
+		require.Equal(t, until, deliveries[0].NextAttemptAt)
+
+		delivery := deliveries[0]
+		delivery.Status = models.WebhookStatusDelivered
+		delivery.Attempts = 1
+		delivery.ResponseStatus = 200
+		delivery.NextAttemptAt = now
+		delivery.DeliveredAt = &now
+		require.NoError(t, repo.UpdateDelivery(context.Background(), delivery))
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+)
+
+var (
+
+	// list of fields from webhook_subscriptions table, but the secret.
+	fieldsOfSubscriptionsTable = `id, url, event_types, content_types, paused, created_at, updated_at`
+
+	// list of fields from webhook_deliveries table.
+	fieldsOfDeliveriesTable = `id, subscription_id, event_id, event_type, payload, status, attempts, response_status, last_error, next_attempt_at, created_at, updated_at, delivered_at`
+
+	// query for create a subscription, the only one returning its secret.
+	createQuery = `
+	INSERT INTO webhook_subscriptions
+	(
+		url,
+		secret,
+		event_types,
+		content_types,
+		paused
+	)
+	VALUES ($1, $2, $3, $4, $5)
+	RETURNING ` + fieldsOfSubscriptionsTable + `, secret`
+
+	// query for update a subscription, an empty secret keeps the current one.
+	updateQuery = `
+	UPDATE webhook_subscriptions SET
+		url = $2,
+		secret = COALESCE(NULLIF($3, ''), secret),
+		event_types = $4,
+		content_types = $5,
+		paused = $6,
+		updated_at = CURRENT_TIMESTAMP
+	WHERE id = $1
+	RETURNING ` + fieldsOfSubscriptionsTable
+
+	// query for delete a subscription with its deliveries.
+	deleteQuery = `DELETE FROM webhook_subscriptions WHERE id = $1`
+
+	// query for get a subscription by id.
+	getByIDQuery = fmt.Sprintf(`SELECT %s FROM webhook_subscriptions WHERE id = $1`, fieldsOfSubscriptionsTable)
+
+	// query for get every subscription.
+	getAllQuery = fmt.Sprintf(`SELECT %s FROM webhook_subscriptions ORDER BY id`, fieldsOfSubscriptionsTable)
+
+	// query for add a delivery of an event to every subscription of its type and content type which is not paused,
+	// an event relayed again adds none.
+	addDeliveriesQuery = `
+	INSERT INTO webhook_deliveries
+	(
+		subscription_id,
+		event_id,
+		event_type,
+		payload
+	)
+	SELECT id, $1::bigint, $2::varchar, $3::jsonb
+	FROM webhook_subscriptions
+	WHERE
+		NOT paused
+		AND event_types @> jsonb_build_array($2::text)
+		AND (content_types = '[]'::jsonb OR content_types @> jsonb_build_array($4::text))
+	ON CONFLICT (subscription_id, event_id) DO NOTHING`
+
+	// query for get total count of the deliveries of a subscription, of a status unless it is empty.
+	getDeliveriesCountQuery = `
+	SELECT COUNT(*) FROM webhook_deliveries
+	WHERE subscription_id = $1 AND ($2 = '' OR status = $2)`
+
+	// query for get the deliveries of a subscription, latest first.
+	getDeliveriesQuery = fmt.Sprintf(`
+	SELECT
+		%s
+	FROM webhook_deliveries
+	WHERE subscription_id = $1 AND ($2 = '' OR status = $2)
+	ORDER BY id DESC
+	OFFSET $3 LIMIT $4`, fieldsOfDeliveriesTable)
+
+	// query for send a delivery again, with every attempt.
+	redeliverQuery = `
+	UPDATE webhook_deliveries SET
+		status = 'pending',
+		attempts = 0,
+		next_attempt_at = CURRENT_TIMESTAMP,
+		delivered_at = NULL,
+		updated_at = CURRENT_TIMESTAMP
+	WHERE id = $1 AND subscription_id = $2
+	RETURNING ` + fieldsOfDeliveriesTable
+
+	// query for claim the pending deliveries due until a time, with the url and secret of their subscription,
+	// skipping the ones claimed meanwhile by another dispatcher. Their next attempt is moved to that time,
+	// so they are due again if not recorded by then.
+	claimDeliveriesQuery = fmt.Sprintf(`
+	WITH claimed AS (
+		UPDATE webhook_deliveries SET
+			next_attempt_at = $2
+		WHERE id IN (
+			SELECT d.id
+			FROM webhook_deliveries d
+			JOIN webhook_subscriptions s ON s.id = d.subscription_id
+			WHERE
+				d.status = 'pending' AND d.next_attempt_at <= CURRENT_TIMESTAMP AND NOT s.paused
+			ORDER BY d.next_attempt_at, d.id
+			LIMIT $1
+			FOR UPDATE OF d SKIP LOCKED
+		)
+		RETURNING %s
+	)
+	SELECT
+		%s, s.url, s.secret
+	FROM claimed d
+	JOIN webhook_subscriptions s ON s.id = d.subscription_id
+	ORDER BY d.id`, fieldsOfDeliveriesTable, "d."+strings.ReplaceAll(fieldsOfDeliveriesTable, ", ", ", d."))
+
+	// query for record the result of an attempt of a delivery.
+	updateDeliveryQuery = `
+	UPDATE webhook_deliveries SET
+		status = $2,
+		attempts = $3,
+		response_status = $4,
+		last_error = $5,
+		next_attempt_at = $6,
+		delivered_at = $7,
+		updated_at = CURRENT_TIMESTAMP
+	WHERE id = $1`
+)
@@ -0,0 +1,18 @@
+package webhooks
+
+import (
+	"context"
+
+	"github.com/realtemirov/task-for-dell/internal/models"
+	"github.com/realtemirov/task-for-dell/pkg/utils"
+)
+
+type UseCase interface {
+	Create(ctx context.Context, subscription *models.WebhookSubscription) (*models.WebhookSubscription, error)
+	Update(ctx context.Context, subscription *models.WebhookSubscription) (*models.WebhookSubscription, error)
+	Delete(ctx context.Context, subscriptionID int64) error
+	GetByID(ctx context.Context, subscriptionID int64) (*models.WebhookSubscription, error)
+	GetAll(ctx context.Context) (*models.WebhookSubscriptionList, error)
+	GetDeliveries(ctx context.Context, subscriptionID int64, status string, query *utils.Query) (*models.WebhookDeliveryList, error)
+	Redeliver(ctx context.Context, subscriptionID, deliveryID int64) (*models.WebhookDelivery, error)
+}
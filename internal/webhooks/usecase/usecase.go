@@ -0,0 +1,181 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/url"
+
+	"github.com/realtemirov/task-for-dell/config"
+	"github.com/realtemirov/task-for-dell/internal/models"
+	"github.com/realtemirov/task-for-dell/internal/webhooks"
+	"github.com/realtemirov/task-for-dell/pkg/domainErrors"
+	"github.com/realtemirov/task-for-dell/pkg/logger"
+	"github.com/realtemirov/task-for-dell/pkg/tracing"
+	"github.com/realtemirov/task-for-dell/pkg/utils"
+)
+
+// SECRET_SIZE is the number of random bytes of a generated secret, hex encoded
+const SECRET_SIZE = 32
+
+// Webhooks Usecase
+type webhooksUC struct {
+	cfg  *config.Config
+	repo webhooks.Repository
+	log  logger.Logger
+}
+
+// Webhooks UseCase contructor
+func NewWebhooksUseCase(cfg *config.Config, repo webhooks.Repository, log logger.Logger) webhooks.UseCase {
+	return &webhooksUC{
+		cfg:  cfg,
+		repo: repo,
+		log:  log,
+	}
+}
+
+// Create implements webhooks.UseCase, a secret is generated unless one is given.
+func (u *webhooksUC) Create(ctx context.Context, subscription *models.WebhookSubscription) (*models.WebhookSubscription, error) {
+	ctx, span := tracing.Start(ctx, "webhooksUC.Create")
+	defer span.End()
+
+	if err := utils.ValidateStruct(ctx, subscription); err != nil {
+		return nil, err
+	}
+
+	if err := u.checkURL(ctx, subscription.URL); err != nil {
+		return nil, err
+	}
+
+	if subscription.Secret == "" {
+		secret, err := generateSecret()
+		if err != nil {
+			return nil, err
+		}
+		subscription.Secret = secret
+	}
+
+	return u.repo.Create(ctx, subscription)
+}
+
+// Update implements webhooks.UseCase, an empty secret keeps the current one.
+func (u *webhooksUC) Update(ctx context.Context, subscription *models.WebhookSubscription) (*models.WebhookSubscription, error) {
+	ctx, span := tracing.Start(ctx, "webhooksUC.Update")
+	defer span.End()
+
+	if err := utils.ValidateStruct(ctx, subscription); err != nil {
+		return nil, err
+	}
+
+	if err := u.checkURL(ctx, subscription.URL); err != nil {
+		return nil, err
+	}
+
+	return u.repo.Update(ctx, subscription)
+}
+
+// Delete implements webhooks.UseCase.
+func (u *webhooksUC) Delete(ctx context.Context, subscriptionID int64) error {
+	ctx, span := tracing.Start(ctx, "webhooksUC.Delete")
+	defer span.End()
+
+	return u.repo.Delete(ctx, subscriptionID)
+}
+
+// GetByID implements webhooks.UseCase.
+func (u *webhooksUC) GetByID(ctx context.Context, subscriptionID int64) (*models.WebhookSubscription, error) {
+	ctx, span := tracing.Start(ctx, "webhooksUC.GetByID")
+	defer span.End()
+
+	return u.repo.GetByID(ctx, subscriptionID)
+}
+
+// GetAll implements webhooks.UseCase.
+func (u *webhooksUC) GetAll(ctx context.Context) (*models.WebhookSubscriptionList, error) {
+	ctx, span := tracing.Start(ctx, "webhooksUC.GetAll")
+	defer span.End()
+
+	result, err := u.repo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.WebhookSubscriptionList{Subscriptions: result}, nil
+}
+
+// GetDeliveries implements webhooks.UseCase, of every status when status is empty.
+func (u *webhooksUC) GetDeliveries(ctx context.Context, subscriptionID int64, status string, query *utils.Query) (*models.WebhookDeliveryList, error) {
+	ctx, span := tracing.Start(ctx, "webhooksUC.GetDeliveries")
+	defer span.End()
+
+	switch status {
+	case "", models.WebhookStatusPending, models.WebhookStatusDelivered, models.WebhookStatusDead:
+	default:
+		return nil, domainErrors.Invalid(fmt.Sprintf("unknown delivery status %q, expected pending, delivered or dead", status), nil)
+	}
+
+	// the log of an unknown subscription is not found rather than empty
+	if _, err := u.repo.GetByID(ctx, subscriptionID); err != nil {
+		return nil, err
+	}
+
+	return u.repo.GetDeliveries(ctx, subscriptionID, status, query)
+}
+
+// Redeliver implements webhooks.UseCase.
+func (u *webhooksUC) Redeliver(ctx context.Context, subscriptionID, deliveryID int64) (*models.WebhookDelivery, error) {
+	ctx, span := tracing.Start(ctx, "webhooksUC.Redeliver")
+	defer span.End()
+
+	return u.repo.Redeliver(ctx, subscriptionID, deliveryID)
+}
+
+// checkURL rejects urls of hosts in private networks, unless AllowPrivateNetworks, so subscriptions can not
+// reach the services beside the api. The dispatcher checks the address again when it connects, as a host may
+// resolve to another address later.
+func (u *webhooksUC) checkURL(ctx context.Context, rawURL string) error {
+	if u.cfg.Webhooks.AllowPrivateNetworks {
+		return nil
+	}
+
+	target, err := url.Parse(rawURL)
+	if err != nil {
+		return domainErrors.Invalid(fmt.Sprintf("url %q is not valid", rawURL), err)
+	}
+	if target.Scheme != "http" && target.Scheme != "https" {
+		return domainErrors.Invalid(fmt.Sprintf("url %q must be http or https", rawURL), nil)
+	}
+
+	host := target.Hostname()
+	if utils.LocalHost(host) {
+		return domainErrors.Invalid(fmt.Sprintf("url %q targets a private network", rawURL), utils.ErrPrivateNetwork)
+	}
+
+	ips := []net.IP{net.ParseIP(host)}
+	if ips[0] == nil {
+		// a host not resolved yet is checked when it is dialed
+		if ips, err = net.DefaultResolver.LookupIP(ctx, "ip", host); err != nil {
+			return nil
+		}
+	}
+
+	for _, ip := range ips {
+		if !utils.PublicIP(ip) {
+			return domainErrors.Invalid(fmt.Sprintf("url %q targets a private network", rawURL), utils.ErrPrivateNetwork)
+		}
+	}
+
+	return nil
+}
+
+// generateSecret returns SECRET_SIZE random bytes, hex encoded
+func generateSecret() (string, error) {
+	secret := make([]byte, SECRET_SIZE)
+	if _, err := rand.Read(secret); err != nil {
+		return "", fmt.Errorf("failed to generate secret: %w", err)
+	}
+
+	return hex.EncodeToString(secret), nil
+}
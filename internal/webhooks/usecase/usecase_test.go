@@ -0,0 +1,162 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/realtemirov/task-for-dell/config"
+	"github.com/realtemirov/task-for-dell/internal/models"
+	"github.com/realtemirov/task-for-dell/internal/webhooks/mock"
+	"github.com/realtemirov/task-for-dell/pkg/domainErrors"
+	"github.com/realtemirov/task-for-dell/pkg/logger"
+	"github.com/realtemirov/task-for-dell/pkg/utils"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestWebhooksUC_Create(t *testing.T) {
+	t.Parallel()
+
+	// Create a new instance of the gomock controller
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// logger, repository, usecase of webhooks
+	logger := logger.NewApiLogger(nil)
+	mockWebhooksRepo := mock.NewMockRepository(ctrl)
+	webhooksUC := NewWebhooksUseCase(&config.Config{}, mockWebhooksRepo, logger)
+
+	// context
+	ctx := context.Background()
+
+	t.Run("Create generated secret", func(t *testing.T) {
+		subscription := &models.WebhookSubscription{
+			URL:        "https://partner.example.com/webhooks",
+			EventTypes: []string{models.EventContentPublished},
+		}
+
+		mockWebhooksRepo.EXPECT().Create(gomock.Any(), subscription).DoAndReturn(
+			func(ctx context.Context, subscription *models.WebhookSubscription) (*models.WebhookSubscription, error) {
+				require.Len(t, subscription.Secret, 2*SECRET_SIZE)
+				return subscription, nil
+			},
+		)
+
+		created, err := webhooksUC.Create(ctx, subscription)
+		require.NoError(t, err)
+		require.NotEmpty(t, created.Secret)
+	})
+
+	t.Run("Create given secret", func(t *testing.T) {
+		subscription := &models.WebhookSubscription{
+			URL:          "https://partner.example.com/webhooks",
+			Secret:       "0123456789abcdef",
+			EventTypes:   []string{models.EventContentCreated},
+			ContentTypes: []string{models.ContentTypeNews},
+		}
+
+		mockWebhooksRepo.EXPECT().Create(gomock.Any(), subscription).Return(subscription, nil)
+
+		created, err := webhooksUC.Create(ctx, subscription)
+		require.NoError(t, err)
+		require.Equal(t, "0123456789abcdef", created.Secret)
+	})
+
+	t.Run("Create invalid", func(t *testing.T) {
+
+		// the repository is not called
+		for _, subscription := range []*models.WebhookSubscription{
+			{URL: "not a url", EventTypes: []string{models.EventContentPublished}},
+			{URL: "https://partner.example.com/webhooks"},
+			{URL: "https://partner.example.com/webhooks", EventTypes: []string{"content.archived"}},
+			{URL: "https://partner.example.com/webhooks", EventTypes: []string{models.EventContentPublished}, ContentTypes: []string{"page"}},
+			{URL: "https://partner.example.com/webhooks", EventTypes: []string{models.EventContentPublished}, Secret: "short"},
+		} {
+			_, err := webhooksUC.Create(ctx, subscription)
+			require.NotEmpty(t, utils.ValidationErrors(err, utils.DEFAULT_LOCALE), "%+v", subscription)
+		}
+	})
+
+	t.Run("Create private network", func(t *testing.T) {
+
+		// loopback, private, link-local and metadata addresses are not reachable, nor other schemes
+		for _, url := range []string{
+			"http://localhost:8080/webhooks",
+			"http://api.localhost/webhooks",
+			"http://127.0.0.1/webhooks",
+			"http://[::1]/webhooks",
+			"http://10.0.0.5/webhooks",
+			"https://192.168.1.10/webhooks",
+			"http://169.254.169.254/latest/meta-data",
+			"http://0.0.0.0:5000/webhooks",
+			"ftp://partner.example.com/webhooks",
+		} {
+			_, err := webhooksUC.Create(ctx, &models.WebhookSubscription{URL: url, EventTypes: []string{models.EventContentPublished}})
+			require.Equal(t, domainErrors.KindInvalid, domainErrors.KindOf(err), url)
+		}
+	})
+
+	t.Run("Create private network allowed", func(t *testing.T) {
+		webhooksUC := NewWebhooksUseCase(
+			&config.Config{Webhooks: config.WebhooksConfig{AllowPrivateNetworks: true}}, mockWebhooksRepo, logger,
+		)
+		subscription := &models.WebhookSubscription{
+			URL:        "http://localhost:8080/webhooks",
+			EventTypes: []string{models.EventContentPublished},
+		}
+
+		mockWebhooksRepo.EXPECT().Create(gomock.Any(), subscription).Return(subscription, nil)
+
+		_, err := webhooksUC.Create(ctx, subscription)
+		require.NoError(t, err)
+	})
+}
+
+func TestWebhooksUC_GetDeliveries(t *testing.T) {
+	t.Parallel()
+
+	// Create a new instance of the gomock controller
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// logger, repository, usecase of webhooks
+	logger := logger.NewApiLogger(nil)
+	mockWebhooksRepo := mock.NewMockRepository(ctrl)
+	webhooksUC := NewWebhooksUseCase(&config.Config{}, mockWebhooksRepo, logger)
+
+	// context
+	ctx := context.Background()
+	query := &utils.Query{Limit: 10, Page: 1}
+
+	t.Run("GetDeliveries", func(t *testing.T) {
+		deliveryList := &models.WebhookDeliveryList{Deliveries: []*models.WebhookDelivery{{ID: 1}}}
+
+		mockWebhooksRepo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(&models.WebhookSubscription{ID: 1}, nil)
+		mockWebhooksRepo.EXPECT().GetDeliveries(gomock.Any(), int64(1), models.WebhookStatusDead, query).Return(deliveryList, nil)
+
+		result, err := webhooksUC.GetDeliveries(ctx, 1, models.WebhookStatusDead, query)
+		require.NoError(t, err)
+		require.Equal(t, deliveryList, result)
+	})
+
+	t.Run("GetDeliveries unknown subscription", func(t *testing.T) {
+		mockWebhooksRepo.EXPECT().GetByID(gomock.Any(), int64(2)).Return(nil, domainErrors.NotFound("record not found", nil))
+
+		_, err := webhooksUC.GetDeliveries(ctx, 2, "", query)
+		require.Equal(t, domainErrors.KindNotFound, domainErrors.KindOf(err))
+	})
+
+	t.Run("GetDeliveries unknown status", func(t *testing.T) {
+		_, err := webhooksUC.GetDeliveries(ctx, 1, "failed", query)
+		require.Equal(t, domainErrors.KindInvalid, domainErrors.KindOf(err))
+	})
+
+	t.Run("Redeliver", func(t *testing.T) {
+		delivery := &models.WebhookDelivery{ID: 3, SubscriptionID: 1, Status: models.WebhookStatusPending}
+		mockWebhooksRepo.EXPECT().Redeliver(gomock.Any(), int64(1), int64(3)).Return(delivery, nil)
+
+		result, err := webhooksUC.Redeliver(ctx, 1, 3)
+		require.NoError(t, err)
+		require.Equal(t, delivery, result)
+	})
+}
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS news_title_trgm_idx;
+
+DROP INDEX IF EXISTS blogs_title_trgm_idx;
+
+DROP EXTENSION IF EXISTS pg_trgm;
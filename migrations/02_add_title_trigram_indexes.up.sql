@@ -0,0 +1,5 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX IF NOT EXISTS blogs_title_trgm_idx ON blogs USING GIN (title gin_trgm_ops);
+
+CREATE INDEX IF NOT EXISTS news_title_trgm_idx ON news USING GIN (title gin_trgm_ops);
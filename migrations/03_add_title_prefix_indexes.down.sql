@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS news_title_prefix_idx;
+
+DROP INDEX IF EXISTS blogs_title_prefix_idx;
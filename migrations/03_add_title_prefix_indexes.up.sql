@@ -0,0 +1,3 @@
+CREATE INDEX IF NOT EXISTS blogs_title_prefix_idx ON blogs (lower(title) text_pattern_ops);
+
+CREATE INDEX IF NOT EXISTS news_title_prefix_idx ON news (lower(title) text_pattern_ops);
@@ -0,0 +1,7 @@
+DROP TRIGGER IF EXISTS news_delete_translations ON news;
+
+DROP TRIGGER IF EXISTS blogs_delete_translations ON blogs;
+
+DROP FUNCTION IF EXISTS delete_translations();
+
+DROP TABLE IF EXISTS translations;
@@ -0,0 +1,28 @@
+CREATE TABLE IF NOT EXISTS translations
+(
+    content_type    VARCHAR(16)                 NOT NULL    CHECK (content_type IN ('blog', 'news')),
+    content_id      INTEGER                     NOT NULL,
+    locale          VARCHAR(8)                  NOT NULL    CHECK (locale <> ''),
+    title           VARCHAR(255)                NOT NULL    CHECK (title <> ''),
+    content         TEXT                        NOT NULL    CHECK (content <> ''),
+    created_at      TIMESTAMP WITH TIME ZONE    NOT NULL    DEFAULT CURRENT_TIMESTAMP,
+    updated_at      TIMESTAMP WITH TIME ZONE    NOT NULL    DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (content_type, content_id, locale)
+);
+
+-- GetAll filters by available locale
+CREATE INDEX IF NOT EXISTS translations_locale_idx ON translations (content_type, locale, content_id);
+
+-- translations belong to a blog or a news, they are deleted with it
+CREATE OR REPLACE FUNCTION delete_translations() RETURNS TRIGGER AS $$
+BEGIN
+    DELETE FROM translations WHERE content_type = TG_ARGV[0] AND content_id = OLD.id;
+    RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER blogs_delete_translations AFTER DELETE ON blogs
+    FOR EACH ROW EXECUTE PROCEDURE delete_translations('blog');
+
+CREATE TRIGGER news_delete_translations AFTER DELETE ON news
+    FOR EACH ROW EXECUTE PROCEDURE delete_translations('news');
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS users
+(
+    id              SERIAL                      PRIMARY KEY,
+    username        VARCHAR(64)                 NOT NULL    UNIQUE  CHECK (username <> ''),
+    password_hash   VARCHAR(255)                NOT NULL    CHECK (password_hash <> ''),
+    role            VARCHAR(16)                 NOT NULL    DEFAULT 'admin' CHECK (role IN ('admin', 'editor')),
+    created_at      TIMESTAMP WITH TIME ZONE    NOT NULL    DEFAULT CURRENT_TIMESTAMP
+);
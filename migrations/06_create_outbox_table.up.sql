@@ -0,0 +1,20 @@
+-- events of content changes, written in the transaction of the change and delivered by the relay
+CREATE TABLE IF NOT EXISTS outbox
+(
+    id              BIGSERIAL                   PRIMARY KEY,
+    event_type      VARCHAR(32)                 NOT NULL    CHECK (event_type <> ''),
+    content_type    VARCHAR(16)                 NOT NULL    CHECK (content_type IN ('blog', 'news')),
+    content_id      INTEGER                     NOT NULL,
+    payload         JSONB                       NOT NULL,
+    created_at      TIMESTAMP WITH TIME ZONE    NOT NULL    DEFAULT CURRENT_TIMESTAMP,
+    attempts        INTEGER                     NOT NULL    DEFAULT 0,
+    last_error      TEXT                        NOT NULL    DEFAULT '',
+    next_attempt_at TIMESTAMP WITH TIME ZONE    NOT NULL    DEFAULT CURRENT_TIMESTAMP,
+    delivered_at    TIMESTAMP WITH TIME ZONE
+);
+
+-- the relay reads pending events in order
+CREATE INDEX IF NOT EXISTS outbox_pending_idx ON outbox (id) WHERE delivered_at IS NULL;
+
+-- delivered events are deleted after the retention
+CREATE INDEX IF NOT EXISTS outbox_delivered_at_idx ON outbox (delivered_at) WHERE delivered_at IS NOT NULL;
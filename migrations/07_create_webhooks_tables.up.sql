@@ -0,0 +1,37 @@
+-- subscriptions of partners to content events, posted to url and signed with secret
+CREATE TABLE IF NOT EXISTS webhook_subscriptions
+(
+    id              SERIAL                      PRIMARY KEY,
+    url             VARCHAR(2048)               NOT NULL    CHECK (url <> ''),
+    secret          VARCHAR(128)                NOT NULL    CHECK (secret <> ''),
+    event_types     JSONB                       NOT NULL    CHECK (jsonb_array_length(event_types) > 0),
+    content_types   JSONB                       NOT NULL    DEFAULT '[]',
+    paused          BOOLEAN                     NOT NULL    DEFAULT FALSE,
+    created_at      TIMESTAMP WITH TIME ZONE    NOT NULL    DEFAULT CURRENT_TIMESTAMP,
+    updated_at      TIMESTAMP WITH TIME ZONE    NOT NULL    DEFAULT CURRENT_TIMESTAMP
+);
+
+-- one delivery of an event to a subscription, its log is the last attempt
+CREATE TABLE IF NOT EXISTS webhook_deliveries
+(
+    id              BIGSERIAL                   PRIMARY KEY,
+    subscription_id INTEGER                     NOT NULL    REFERENCES webhook_subscriptions (id) ON DELETE CASCADE,
+    event_id        BIGINT                      NOT NULL,
+    event_type      VARCHAR(32)                 NOT NULL    CHECK (event_type <> ''),
+    payload         JSONB                       NOT NULL,
+    status          VARCHAR(16)                 NOT NULL    DEFAULT 'pending' CHECK (status IN ('pending', 'delivered', 'dead')),
+    attempts        INTEGER                     NOT NULL    DEFAULT 0,
+    response_status INTEGER                     NOT NULL    DEFAULT 0,
+    last_error      TEXT                        NOT NULL    DEFAULT '',
+    next_attempt_at TIMESTAMP WITH TIME ZONE    NOT NULL    DEFAULT CURRENT_TIMESTAMP,
+    created_at      TIMESTAMP WITH TIME ZONE    NOT NULL    DEFAULT CURRENT_TIMESTAMP,
+    updated_at      TIMESTAMP WITH TIME ZONE    NOT NULL    DEFAULT CURRENT_TIMESTAMP,
+    delivered_at    TIMESTAMP WITH TIME ZONE,
+    UNIQUE (subscription_id, event_id)
+);
+
+-- the dispatcher reads pending deliveries when they are due
+CREATE INDEX IF NOT EXISTS webhook_deliveries_pending_idx ON webhook_deliveries (next_attempt_at) WHERE status = 'pending';
+
+-- the delivery log of a subscription, latest first
+CREATE INDEX IF NOT EXISTS webhook_deliveries_subscription_idx ON webhook_deliveries (subscription_id, id DESC);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS outbox_content_type_id_idx;
+
+DROP TRIGGER IF EXISTS outbox_notify_event ON outbox;
+
+DROP FUNCTION IF EXISTS notify_outbox_event();
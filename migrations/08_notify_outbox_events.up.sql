@@ -0,0 +1,13 @@
+-- every instance listening to outbox_events is notified of the id of an event once its transaction commits
+CREATE OR REPLACE FUNCTION notify_outbox_event() RETURNS TRIGGER AS $$
+BEGIN
+    PERFORM pg_notify('outbox_events', NEW.id::text);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER outbox_notify_event AFTER INSERT ON outbox
+    FOR EACH ROW EXECUTE PROCEDURE notify_outbox_event();
+
+-- streams resume after the last event id of a client, by content type
+CREATE INDEX IF NOT EXISTS outbox_content_type_id_idx ON outbox (content_type, id);
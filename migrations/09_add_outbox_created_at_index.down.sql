@@ -0,0 +1 @@
+DROP INDEX IF EXISTS outbox_content_type_created_at_idx;
@@ -0,0 +1,2 @@
+-- resumed streams replay the events written shortly before the last one of a client, by content type
+CREATE INDEX IF NOT EXISTS outbox_content_type_created_at_idx ON outbox (content_type, created_at);
@@ -0,0 +1,10 @@
+// Package migrations embeds the SQL migrations of the database, applied by pkg/db/migrate.
+// Files are named <version>_<title>.up.sql and <version>_<title>.down.sql.
+package migrations
+
+import "embed"
+
+// FS holds every migration
+//
+//go:embed *.sql
+var FS embed.FS
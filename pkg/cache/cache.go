@@ -0,0 +1,58 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/realtemirov/task-for-dell/config"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	DRIVER_NONE   = "none"
+	DRIVER_MEMORY = "memory"
+	DRIVER_REDIS  = "redis"
+)
+
+// ErrMiss is returned by Get for keys which are not cached or expired
+var ErrMiss = errors.New("cache: miss")
+
+// Cache stores encoded values by key, Memory and Redis implement it
+type Cache interface {
+
+	// Get returns the value of key, ErrMiss when it is not cached
+	Get(ctx context.Context, key string) ([]byte, error)
+
+	// Set stores value for key, a ttl <= 0 never expires it
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+
+	// Delete removes the keys
+	Delete(ctx context.Context, keys ...string) error
+
+	// DeletePrefix removes every key starting with prefix
+	DeletePrefix(ctx context.Context, prefix string) error
+
+	// Close releases the connections of the cache
+	Close() error
+}
+
+// NewCache constructs the cache of the config driver, nil when caching is off
+func NewCache(cfg *config.Config) (Cache, error) {
+	switch cfg.Cache.Driver {
+	case DRIVER_MEMORY:
+		return NewMemory(cfg.Cache.Size), nil
+	case DRIVER_REDIS:
+		client := redis.NewClient(&redis.Options{
+			Addr:     cfg.Cache.RedisAddr,
+			Password: cfg.Cache.RedisPassword,
+			DB:       cfg.Cache.RedisDB,
+		})
+		return NewRedis(client, cfg.Cache.KeyPrefix), nil
+	case DRIVER_NONE, "":
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("unknown cache driver %q", cfg.Cache.Driver)
+	}
+}
@@ -0,0 +1,105 @@
+package cache
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+)
+
+// newRedis returns a Redis on top of an in-memory redis server and the server
+func newRedis(t *testing.T) (*Redis, *miniredis.Miniredis) {
+	server := miniredis.RunT(t)
+	c := NewRedis(redis.NewClient(&redis.Options{Addr: server.Addr()}), "app:")
+	t.Cleanup(func() { c.Close() })
+
+	return c, server
+}
+
+func TestCache(t *testing.T) {
+	t.Parallel()
+
+	redisCache, _ := newRedis(t)
+	caches := map[string]Cache{
+		"Memory": NewMemory(10),
+		"Redis":  redisCache,
+	}
+
+	for name, c := range caches {
+		c := c
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ctx := context.Background()
+
+			// miss, then hit
+			_, err := c.Get(ctx, "blogs:id:1")
+			require.ErrorIs(t, err, ErrMiss)
+
+			require.NoError(t, c.Set(ctx, "blogs:id:1", []byte("blog"), time.Minute))
+			value, err := c.Get(ctx, "blogs:id:1")
+			require.NoError(t, err)
+			require.Equal(t, []byte("blog"), value)
+
+			// Delete
+			require.NoError(t, c.Delete(ctx, "blogs:id:1", "blogs:id:2"))
+			_, err = c.Get(ctx, "blogs:id:1")
+			require.ErrorIs(t, err, ErrMiss)
+
+			// DeletePrefix keeps the keys of other prefixes, glob characters match as they are
+			require.NoError(t, c.Set(ctx, "blogs:list:a", []byte("a"), 0))
+			require.NoError(t, c.Set(ctx, "blogs:list:b", []byte("b"), 0))
+			require.NoError(t, c.Set(ctx, "blogs:lis*", []byte("c"), 0))
+			require.NoError(t, c.Set(ctx, "news:list:a", []byte("d"), 0))
+			require.NoError(t, c.DeletePrefix(ctx, "blogs:list:"))
+
+			for _, key := range []string{"blogs:list:a", "blogs:list:b"} {
+				_, err = c.Get(ctx, key)
+				require.ErrorIs(t, err, ErrMiss)
+			}
+			for _, key := range []string{"blogs:lis*", "news:list:a"} {
+				_, err = c.Get(ctx, key)
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestRedis(t *testing.T) {
+	t.Parallel()
+
+	c, server := newRedis(t)
+	ctx := context.Background()
+
+	t.Run("Prefix and ttl", func(t *testing.T) {
+		require.NoError(t, c.Set(ctx, "blogs:id:1", []byte("blog"), time.Minute))
+		require.True(t, server.Exists("app:blogs:id:1"))
+		require.Equal(t, time.Minute, server.TTL("app:blogs:id:1"))
+
+		server.FastForward(time.Minute)
+		_, err := c.Get(ctx, "blogs:id:1")
+		require.ErrorIs(t, err, ErrMiss)
+	})
+
+	t.Run("DeletePrefix many", func(t *testing.T) {
+		for i := 0; i < REDIS_SCAN_COUNT*2+1; i++ {
+			require.NoError(t, server.Set("app:news:list:"+strconv.Itoa(i), "x"))
+		}
+		require.NoError(t, server.Set("other:news:list:a", "x"))
+
+		require.NoError(t, c.DeletePrefix(ctx, "news:list:"))
+		require.Equal(t, []string{"other:news:list:a"}, server.Keys())
+	})
+
+	t.Run("Unavailable", func(t *testing.T) {
+		server.SetError("LOADING")
+		defer server.SetError("")
+
+		_, err := c.Get(ctx, "blogs:id:1")
+		require.Error(t, err)
+		require.NotErrorIs(t, err, ErrMiss)
+	})
+}
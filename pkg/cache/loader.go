@@ -0,0 +1,192 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/realtemirov/task-for-dell/pkg/metrics"
+	"golang.org/x/sync/singleflight"
+)
+
+// LOAD_TIMEOUT bounds a load, which no longer ends with the request of the caller which started it
+const LOAD_TIMEOUT = 30 * time.Second
+
+// Loader reads values through a Cache as JSON. Concurrent misses of one key load it once,
+// so a popular entry which expires does not send every waiting request to the database.
+// Entries are invalidated through it, so a load running meanwhile does not cache what it read before.
+type Loader struct {
+	name  string
+	cache Cache
+	ttl   time.Duration
+	group singleflight.Group
+
+	// loads holds the keys being loaded, with the generation bumped by their invalidations
+	mu    sync.Mutex
+	loads map[string]*load
+}
+
+// load of a key, running count times
+type load struct {
+	gen   uint64
+	count int
+}
+
+// NewLoader constructs a Loader caching values for ttl, name labels its metrics
+func NewLoader(name string, cache Cache, ttl time.Duration) *Loader {
+	return &Loader{
+		name:  name,
+		cache: cache,
+		ttl:   ttl,
+		loads: make(map[string]*load),
+	}
+}
+
+// Load decodes the cached value of key into dst. On a miss it stores the result of fn, which must
+// be encodable into dst. A failing cache is skipped, so reads keep working while it is down.
+func (l *Loader) Load(ctx context.Context, key string, dst interface{}, fn func(ctx context.Context) (interface{}, error)) error {
+
+	value, err := l.cache.Get(ctx, key)
+	switch {
+	case err == nil:
+		if json.Unmarshal(value, dst) == nil {
+			metrics.ObserveCache(l.name, "hit")
+			return nil
+		}
+		metrics.ObserveCache(l.name, "error")
+	case errors.Is(err, ErrMiss):
+		metrics.ObserveCache(l.name, "miss")
+	default:
+		metrics.ObserveCache(l.name, "error")
+	}
+
+	// the first caller loads, the others wait for its result. The load outlives the caller
+	// which started it, so its cancel does not fail every other caller of key.
+	loading := l.group.DoChan(key, func() (interface{}, error) {
+		loadCtx, cancel := context.WithTimeout(detach(ctx), LOAD_TIMEOUT)
+		defer cancel()
+
+		gen := l.begin(key)
+		defer l.end(key)
+
+		result, err := fn(loadCtx)
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := json.Marshal(result)
+		if err != nil {
+			return nil, err
+		}
+
+		// a write invalidated key during the load, what it read may be older
+		if l.invalidated(key, gen) {
+			return value, nil
+		}
+
+		// not cached this time, the next miss loads again
+		_ = l.cache.Set(loadCtx, key, value, l.ttl)
+
+		// invalidated between the check and the set, which may have come after its delete
+		if l.invalidated(key, gen) {
+			_ = l.cache.Delete(loadCtx, key)
+		}
+
+		return value, nil
+	})
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case loaded := <-loading:
+		if loaded.Err != nil {
+			return loaded.Err
+		}
+		return json.Unmarshal(loaded.Val.([]byte), dst)
+	}
+}
+
+// Invalidate deletes the cached keys, the loads of keys running meanwhile do not cache their result
+// and the next miss loads again instead of waiting for them
+func (l *Loader) Invalidate(ctx context.Context, keys ...string) error {
+	l.mu.Lock()
+	for _, key := range keys {
+		l.forget(key)
+	}
+	l.mu.Unlock()
+
+	return l.cache.Delete(ctx, keys...)
+}
+
+// InvalidatePrefix deletes every cached key starting with prefix, like Invalidate
+func (l *Loader) InvalidatePrefix(ctx context.Context, prefix string) error {
+	l.mu.Lock()
+	for key := range l.loads {
+		if strings.HasPrefix(key, prefix) {
+			l.forget(key)
+		}
+	}
+	l.mu.Unlock()
+
+	return l.cache.DeletePrefix(ctx, prefix)
+}
+
+// forget bumps the generation of a load of key running, l.mu held
+func (l *Loader) forget(key string) {
+	if load, ok := l.loads[key]; ok {
+		load.gen++
+		l.group.Forget(key)
+	}
+}
+
+// begin registers a load of key and returns its generation
+func (l *Loader) begin(key string) uint64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	current, ok := l.loads[key]
+	if !ok {
+		current = &load{}
+		l.loads[key] = current
+	}
+	current.count++
+
+	return current.gen
+}
+
+// end unregisters a load of key
+func (l *Loader) end(key string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	current := l.loads[key]
+	current.count--
+	if current.count == 0 {
+		delete(l.loads, key)
+	}
+}
+
+// invalidated reports whether key was invalidated since the load of gen began
+func (l *Loader) invalidated(key string, gen uint64) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.loads[key].gen != gen
+}
+
+// detachedContext keeps the values of a context, e.g. its span and request id, without its deadline and cancel
+type detachedContext struct {
+	context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool) { return time.Time{}, false }
+func (detachedContext) Done() <-chan struct{}       { return nil }
+func (detachedContext) Err() error                  { return nil }
+
+// detach returns a context with the values of ctx which is never canceled
+func detach(ctx context.Context) context.Context {
+	return detachedContext{Context: ctx}
+}
@@ -0,0 +1,217 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type loaderValue struct {
+	ID    int64  `json:"id"`
+	Title string `json:"title"`
+}
+
+func TestLoader(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	t.Run("Load", func(t *testing.T) {
+		loader := NewLoader("test", NewMemory(10), time.Minute)
+
+		calls := 0
+		load := func(ctx context.Context) (interface{}, error) {
+			calls++
+			return &loaderValue{ID: 1, Title: "title"}, nil
+		}
+
+		// the miss loads, the hit decodes the cached value
+		for i := 0; i < 2; i++ {
+			var value loaderValue
+			require.NoError(t, loader.Load(ctx, "key", &value, load))
+			require.Equal(t, loaderValue{ID: 1, Title: "title"}, value)
+		}
+		require.Equal(t, 1, calls)
+	})
+
+	t.Run("Load error", func(t *testing.T) {
+		loader := NewLoader("test", NewMemory(10), time.Minute)
+		errLoad := errors.New("load failed")
+
+		var value loaderValue
+		err := loader.Load(ctx, "key", &value, func(ctx context.Context) (interface{}, error) {
+			return nil, errLoad
+		})
+		require.ErrorIs(t, err, errLoad)
+
+		// errors are not cached
+		err = loader.Load(ctx, "key", &value, func(ctx context.Context) (interface{}, error) {
+			return &loaderValue{ID: 1}, nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, int64(1), value.ID)
+	})
+
+	t.Run("Load once for concurrent misses", func(t *testing.T) {
+		loader := NewLoader("test", NewMemory(10), time.Minute)
+
+		var (
+			calls   atomic.Int32
+			release = make(chan struct{})
+			wg      sync.WaitGroup
+		)
+		load := func(ctx context.Context) (interface{}, error) {
+			calls.Add(1)
+			<-release
+			return &loaderValue{ID: 1}, nil
+		}
+
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				var value loaderValue
+				require.NoError(t, loader.Load(ctx, "key", &value, load))
+				require.Equal(t, int64(1), value.ID)
+			}()
+		}
+
+		// let the callers reach the loading one before it returns
+		time.Sleep(20 * time.Millisecond)
+		close(release)
+		wg.Wait()
+
+		require.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("Load outlives its canceled caller", func(t *testing.T) {
+		loader := NewLoader("test", NewMemory(10), time.Minute)
+
+		var (
+			started = make(chan struct{})
+			release = make(chan struct{})
+			loadErr = make(chan error, 1)
+		)
+		load := func(ctx context.Context) (interface{}, error) {
+			close(started)
+			<-release
+			return &loaderValue{ID: 1}, ctx.Err()
+		}
+
+		// the first caller goes away while loading
+		canceledCtx, cancel := context.WithCancel(ctx)
+		go func() {
+			var value loaderValue
+			loadErr <- loader.Load(canceledCtx, "key", &value, load)
+		}()
+		<-started
+		cancel()
+		require.ErrorIs(t, <-loadErr, context.Canceled)
+
+		// the others get the value it loads
+		go func() {
+			var value loaderValue
+			loadErr <- loader.Load(ctx, "key", &value, load)
+		}()
+		close(release)
+		require.NoError(t, <-loadErr)
+
+		var value loaderValue
+		require.NoError(t, loader.Load(ctx, "key", &value, load))
+		require.Equal(t, int64(1), value.ID)
+	})
+
+	t.Run("Load invalidated by a write during the load", func(t *testing.T) {
+		loader := NewLoader("test", NewMemory(10), time.Minute)
+
+		var (
+			started = make(chan struct{})
+			release = make(chan struct{})
+			loaded  = make(chan loaderValue, 1)
+		)
+
+		// a slow load reads the value before the write
+		go func() {
+			var value loaderValue
+			require.NoError(t, loader.Load(ctx, "key", &value, func(ctx context.Context) (interface{}, error) {
+				close(started)
+				<-release
+				return &loaderValue{ID: 1, Title: "before"}, nil
+			}))
+			loaded <- value
+		}()
+		<-started
+
+		// the write invalidates the key, the next miss loads again instead of waiting for the slow load
+		require.NoError(t, loader.Invalidate(ctx, "key"))
+
+		var value loaderValue
+		require.NoError(t, loader.Load(ctx, "key", &value, func(ctx context.Context) (interface{}, error) {
+			return &loaderValue{ID: 1, Title: "after"}, nil
+		}))
+		require.Equal(t, "after", value.Title)
+
+		// the slow load returns to its caller without caching
+		close(release)
+		require.Equal(t, "before", (<-loaded).Title)
+
+		require.NoError(t, loader.Load(ctx, "key", &value, func(ctx context.Context) (interface{}, error) {
+			t.Fatal("cached value not used")
+			return nil, nil
+		}))
+		require.Equal(t, "after", value.Title)
+	})
+
+	t.Run("Load invalidated by prefix during the load", func(t *testing.T) {
+		loader := NewLoader("test", NewMemory(10), time.Minute)
+
+		var (
+			started = make(chan struct{})
+			release = make(chan struct{})
+			done    = make(chan error, 1)
+		)
+		go func() {
+			var value loaderValue
+			done <- loader.Load(ctx, "list:page=1", &value, func(ctx context.Context) (interface{}, error) {
+				close(started)
+				<-release
+				return &loaderValue{ID: 1}, nil
+			})
+		}()
+		<-started
+
+		require.NoError(t, loader.InvalidatePrefix(ctx, "list:"))
+		close(release)
+		require.NoError(t, <-done)
+
+		// not cached, the next read loads
+		calls := 0
+		var value loaderValue
+		require.NoError(t, loader.Load(ctx, "list:page=1", &value, func(ctx context.Context) (interface{}, error) {
+			calls++
+			return &loaderValue{ID: 2}, nil
+		}))
+		require.Equal(t, 1, calls)
+		require.Equal(t, int64(2), value.ID)
+	})
+
+	t.Run("Load with the cache down", func(t *testing.T) {
+		redisCache, server := newRedis(t)
+		loader := NewLoader("test", redisCache, time.Minute)
+		server.Close()
+
+		// reads go on without the cache
+		var value loaderValue
+		err := loader.Load(ctx, "key", &value, func(ctx context.Context) (interface{}, error) {
+			return &loaderValue{ID: 1}, nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, int64(1), value.ID)
+	})
+}
@@ -0,0 +1,68 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLRU(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Evicts least recently used", func(t *testing.T) {
+		lru := NewLRU(2, 0)
+		lru.Set("a", 1)
+		lru.Set("b", 2)
+
+		// touch a, so b becomes the least recently used
+		_, ok := lru.Get("a")
+		require.True(t, ok)
+
+		lru.Set("c", 3)
+
+		_, ok = lru.Get("b")
+		require.False(t, ok)
+
+		value, ok := lru.Get("a")
+		require.True(t, ok)
+		require.Equal(t, 1, value)
+		require.Equal(t, 2, lru.Len())
+	})
+
+	t.Run("Expires after ttl", func(t *testing.T) {
+		lru := NewLRU(2, time.Millisecond)
+		lru.Set("a", 1)
+
+		time.Sleep(5 * time.Millisecond)
+
+		_, ok := lru.Get("a")
+		require.False(t, ok)
+		require.Equal(t, 0, lru.Len())
+	})
+
+	t.Run("Delete and Purge", func(t *testing.T) {
+		lru := NewLRU(3, 0)
+		lru.Set("a", 1)
+		lru.Set("b", 2)
+
+		lru.Delete("a")
+		_, ok := lru.Get("a")
+		require.False(t, ok)
+
+		lru.Purge()
+		require.Equal(t, 0, lru.Len())
+	})
+
+	t.Run("DeletePrefix", func(t *testing.T) {
+		lru := NewLRU(3, 0)
+		lru.Set("blogs:list:1", 1)
+		lru.Set("blogs:list:2", 2)
+		lru.Set("blogs:id:1", 3)
+
+		require.Equal(t, 2, lru.DeletePrefix("blogs:list:"))
+		_, ok := lru.Get("blogs:id:1")
+		require.True(t, ok)
+		require.Equal(t, 1, lru.Len())
+	})
+}
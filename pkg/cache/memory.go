@@ -0,0 +1,53 @@
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+// Memory is a Cache of the process on top of an LRU, every instance of the app has its own
+type Memory struct {
+	lru *LRU
+}
+
+// NewMemory constructs a Memory holding at most size entries
+func NewMemory(size int) *Memory {
+	return &Memory{lru: NewLRU(size, 0)}
+}
+
+// Get implements Cache.
+func (m *Memory) Get(ctx context.Context, key string) ([]byte, error) {
+	value, ok := m.lru.Get(key)
+	if !ok {
+		return nil, ErrMiss
+	}
+
+	return value.([]byte), nil
+}
+
+// Set implements Cache.
+func (m *Memory) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	m.lru.SetWithTTL(key, value, ttl)
+	return nil
+}
+
+// Delete implements Cache.
+func (m *Memory) Delete(ctx context.Context, keys ...string) error {
+	for _, key := range keys {
+		m.lru.Delete(key)
+	}
+
+	return nil
+}
+
+// DeletePrefix implements Cache.
+func (m *Memory) DeletePrefix(ctx context.Context, prefix string) error {
+	m.lru.DeletePrefix(prefix)
+	return nil
+}
+
+// Close implements Cache.
+func (m *Memory) Close() error {
+	m.lru.Purge()
+	return nil
+}
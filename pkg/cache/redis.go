@@ -0,0 +1,91 @@
+package cache
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/redis/go-redis/v9"
+)
+
+// REDIS_SCAN_COUNT is the number of keys DeletePrefix asks Redis to scan per call
+const REDIS_SCAN_COUNT = 500
+
+// globEscaper escapes the characters Redis matches as a pattern
+var globEscaper = strings.NewReplacer(`\`, `\\`, `*`, `\*`, `?`, `\?`, `[`, `\[`, `]`, `\]`)
+
+// Redis is a Cache shared by every instance of the app, its keys start with prefix
+type Redis struct {
+	client redis.UniversalClient
+	prefix string
+}
+
+// NewRedis constructs a Redis prefixing every key with prefix, so apps can share a database
+func NewRedis(client redis.UniversalClient, prefix string) *Redis {
+	return &Redis{client: client, prefix: prefix}
+}
+
+// Get implements Cache.
+func (r *Redis) Get(ctx context.Context, key string) ([]byte, error) {
+	value, err := r.client.Get(ctx, r.prefix+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrMiss
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "cache.Redis.Get")
+	}
+
+	return value, nil
+}
+
+// Set implements Cache.
+func (r *Redis) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if ttl < 0 {
+		ttl = 0
+	}
+
+	return errors.Wrap(r.client.Set(ctx, r.prefix+key, value, ttl).Err(), "cache.Redis.Set")
+}
+
+// Delete implements Cache.
+func (r *Redis) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	prefixed := make([]string, 0, len(keys))
+	for _, key := range keys {
+		prefixed = append(prefixed, r.prefix+key)
+	}
+
+	return errors.Wrap(r.client.Del(ctx, prefixed...).Err(), "cache.Redis.Delete")
+}
+
+// DeletePrefix implements Cache, it scans the keys so Redis is never blocked by one large command.
+func (r *Redis) DeletePrefix(ctx context.Context, prefix string) error {
+	pattern := globEscaper.Replace(r.prefix+prefix) + "*"
+
+	var cursor uint64
+	for {
+		keys, next, err := r.client.Scan(ctx, cursor, pattern, REDIS_SCAN_COUNT).Result()
+		if err != nil {
+			return errors.Wrap(err, "cache.Redis.DeletePrefix.Scan")
+		}
+
+		if len(keys) > 0 {
+			if err = r.client.Del(ctx, keys...).Err(); err != nil {
+				return errors.Wrap(err, "cache.Redis.DeletePrefix.Del")
+			}
+		}
+
+		if cursor = next; cursor == 0 {
+			return nil
+		}
+	}
+}
+
+// Close implements Cache.
+func (r *Redis) Close() error {
+	return r.client.Close()
+}
@@ -0,0 +1,189 @@
+package migrate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"time"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database/postgres"
+	"github.com/golang-migrate/migrate/v4/source"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"github.com/jmoiron/sqlx"
+	pkgErrors "github.com/pkg/errors"
+	"github.com/realtemirov/task-for-dell/migrations"
+)
+
+const (
+	// LOCK_TIMEOUT is how long a runner waits for the advisory lock held by another runner
+	LOCK_TIMEOUT = time.Minute
+
+	// table of the applied version, shared with the migrate cli
+	migrationsTable = "schema_migrations"
+)
+
+// query for get the applied version.
+var getVersionQuery = fmt.Sprintf(`SELECT version, dirty FROM %s LIMIT 1`, migrationsTable)
+
+// Status is the version of the schema
+type Status struct {
+	Version uint `json:"version" example:"4"`
+	Dirty   bool `json:"dirty" example:"false"`
+	Latest  uint `json:"latest" example:"4"`
+}
+
+// Check returns an error when the schema is older than the embedded migrations,
+// or the last migration failed half way
+func (s *Status) Check() error {
+	if s.Dirty {
+		return fmt.Errorf("migration %d is dirty", s.Version)
+	}
+	if s.Version < s.Latest {
+		return fmt.Errorf("migration version is %d, want %d", s.Version, s.Latest)
+	}
+
+	return nil
+}
+
+// Migrator applies the embedded migrations. Runners of several instances are serialized
+// by a postgres advisory lock, a runner waits up to LOCK_TIMEOUT for the others.
+type Migrator struct {
+	migrate *migrate.Migrate
+}
+
+// NewMigrator returns a migrator holding one connection of db until Close
+func NewMigrator(ctx context.Context, db *sqlx.DB) (*Migrator, error) {
+
+	sourceDriver, err := iofs.New(migrations.FS, ".")
+	if err != nil {
+		return nil, pkgErrors.Wrap(err, "migrate.NewMigrator.iofs")
+	}
+
+	// a connection instead of the pool, so Close gives it back without closing the pool
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, pkgErrors.Wrap(err, "migrate.NewMigrator.Conn")
+	}
+
+	databaseDriver, err := postgres.WithConnection(ctx, conn, &postgres.Config{MigrationsTable: migrationsTable})
+	if err != nil {
+		conn.Close()
+		return nil, pkgErrors.Wrap(err, "migrate.NewMigrator.WithConnection")
+	}
+
+	m, err := migrate.NewWithInstance("iofs", sourceDriver, "postgres", databaseDriver)
+	if err != nil {
+		databaseDriver.Close()
+		return nil, pkgErrors.Wrap(err, "migrate.NewMigrator.NewWithInstance")
+	}
+	m.LockTimeout = LOCK_TIMEOUT
+
+	return &Migrator{migrate: m}, nil
+}
+
+// Up applies every migration not applied yet
+func (m *Migrator) Up() error {
+	return ignoreNoChange(m.migrate.Up())
+}
+
+// Down reverts the last steps migrations
+func (m *Migrator) Down(steps int) error {
+	if steps <= 0 {
+		return fmt.Errorf("steps must be positive, got %d", steps)
+	}
+
+	return ignoreNoChange(m.migrate.Steps(-steps))
+}
+
+// Goto applies or reverts migrations up to version
+func (m *Migrator) Goto(version uint) error {
+	return ignoreNoChange(m.migrate.Migrate(version))
+}
+
+// Force sets the version without running migrations, to recover a dirty schema fixed by hand
+func (m *Migrator) Force(version int) error {
+	return m.migrate.Force(version)
+}
+
+// Status returns the applied version and the latest embedded one
+func (m *Migrator) Status() (*Status, error) {
+
+	latest, err := LatestVersion()
+	if err != nil {
+		return nil, err
+	}
+
+	version, dirty, err := m.migrate.Version()
+	if errors.Is(err, migrate.ErrNilVersion) {
+		return &Status{Latest: latest}, nil
+	}
+	if err != nil {
+		return nil, pkgErrors.Wrap(err, "migrate.Status.Version")
+	}
+
+	return &Status{Version: version, Dirty: dirty, Latest: latest}, nil
+}
+
+// Close gives the connection back to the pool
+func (m *Migrator) Close() error {
+	sourceErr, databaseErr := m.migrate.Close()
+	if sourceErr != nil {
+		return sourceErr
+	}
+
+	return databaseErr
+}
+
+// GetStatus reads the applied version without locking, for health checks
+func GetStatus(ctx context.Context, db *sqlx.DB) (*Status, error) {
+
+	latest, err := LatestVersion()
+	if err != nil {
+		return nil, err
+	}
+
+	status := &Status{Latest: latest}
+	if err = db.QueryRowContext(ctx, getVersionQuery).Scan(&status.Version, &status.Dirty); err != nil {
+		return status, pkgErrors.Wrap(err, "migrate.GetStatus.Scan")
+	}
+
+	return status, nil
+}
+
+// LatestVersion returns the version of the last embedded migration
+func LatestVersion() (uint, error) {
+	return latestVersion(migrations.FS)
+}
+
+// latestVersion returns the highest version of the migration files of fsys, other files are skipped
+func latestVersion(fsys fs.FS) (uint, error) {
+
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return 0, pkgErrors.Wrap(err, "migrate.LatestVersion.ReadDir")
+	}
+
+	var latest uint
+	for _, entry := range entries {
+		migration, err := source.DefaultParse(entry.Name())
+		if err != nil {
+			continue
+		}
+		if migration.Version > latest {
+			latest = migration.Version
+		}
+	}
+
+	return latest, nil
+}
+
+// ignoreNoChange treats an already migrated schema as a success
+func ignoreNoChange(err error) error {
+	if errors.Is(err, migrate.ErrNoChange) {
+		return nil
+	}
+
+	return err
+}
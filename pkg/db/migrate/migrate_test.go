@@ -0,0 +1,84 @@
+package migrate
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLatestVersion(t *testing.T) {
+	t.Parallel()
+
+	// versions are compared as numbers, files which are not migrations are skipped
+	fsys := fstest.MapFS{
+		"migrations.go":             {},
+		"01_create_tables.up.sql":   {},
+		"01_create_tables.down.sql": {},
+		"02_add_indexes.up.sql":     {},
+		"02_add_indexes.down.sql":   {},
+		"10_add_column.up.sql":      {},
+		"10_add_column.down.sql":    {},
+		"9_add_other_column.up.sql": {},
+		"README.md":                 {},
+	}
+
+	latest, err := latestVersion(fsys)
+	require.NoError(t, err)
+	require.Equal(t, uint(10), latest)
+
+	latest, err = latestVersion(fstest.MapFS{})
+	require.NoError(t, err)
+	require.Zero(t, latest)
+}
+
+func TestStatus_Check(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, (&Status{Version: 4, Latest: 4}).Check())
+
+	// newer schemas are served by the previous release during a rollout
+	require.NoError(t, (&Status{Version: 5, Latest: 4}).Check())
+
+	require.EqualError(t, (&Status{Version: 3, Latest: 4}).Check(), "migration version is 3, want 4")
+	require.EqualError(t, (&Status{Version: 4, Dirty: true, Latest: 4}).Check(), "migration 4 is dirty")
+}
+
+func TestGetStatus(t *testing.T) {
+	t.Parallel()
+
+	// create mock db
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer db.Close()
+
+	// create sqlx db with mock db
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	defer sqlxDB.Close()
+
+	latest, err := LatestVersion()
+	require.NoError(t, err)
+	require.NotZero(t, latest)
+
+	t.Run("GetStatus", func(t *testing.T) {
+		mock.ExpectQuery(getVersionQuery).WillReturnRows(
+			sqlmock.NewRows([]string{"version", "dirty"}).AddRow(3, false),
+		)
+
+		status, err := GetStatus(context.Background(), sqlxDB)
+		require.NoError(t, err)
+		require.Equal(t, &Status{Version: 3, Latest: latest}, status)
+	})
+
+	t.Run("GetStatus Error", func(t *testing.T) {
+		mock.ExpectQuery(getVersionQuery).WillReturnError(context.DeadlineExceeded)
+
+		// the latest version is reported even when the applied one is unknown
+		status, err := GetStatus(context.Background(), sqlxDB)
+		require.Error(t, err)
+		require.Equal(t, latest, status.Latest)
+	})
+}
@@ -0,0 +1,133 @@
+package postgres
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/realtemirov/task-for-dell/pkg/logger"
+)
+
+// primaryKey marks contexts whose reads must see the writes of the primary
+type primaryKey struct{}
+
+// WithPrimary returns a context whose reads go to the primary, e.g. after a mutation
+// whose result must be read back before replicas catch up
+func WithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryKey{}, true)
+}
+
+// UsePrimary reports whether reads of ctx must go to the primary
+func UsePrimary(ctx context.Context) bool {
+	usePrimary, _ := ctx.Value(primaryKey{}).(bool)
+	return usePrimary
+}
+
+// DB is the primary database, whose methods every write uses, and its read replicas.
+// Read-only repository methods take their database from Reader.
+type DB struct {
+	*sqlx.DB
+
+	replicas []*replica
+	next     atomic.Uint64
+}
+
+// replica is a read replica and the result of its last health check
+type replica struct {
+	addr    string
+	db      *sqlx.DB
+	healthy atomic.Bool
+}
+
+// NewDB constructs a DB of the primary, without replicas every read goes to the primary
+func NewDB(primary *sqlx.DB) *DB {
+	return &DB{DB: primary}
+}
+
+// AddReplica adds a read replica, it receives reads once a health check passed
+func (db *DB) AddReplica(addr string, replicaDB *sqlx.DB) {
+	db.replicas = append(db.replicas, &replica{addr: addr, db: replicaDB})
+}
+
+// Replicas returns the read replicas by address
+func (db *DB) Replicas() map[string]*sqlx.DB {
+	replicas := make(map[string]*sqlx.DB, len(db.replicas))
+	for _, r := range db.replicas {
+		replicas[r.addr] = r.db
+	}
+
+	return replicas
+}
+
+// Reader returns the transaction of ctx, a healthy replica, in turn, or the primary
+// when ctx must read its own writes or no replica is healthy
+func (db *DB) Reader(ctx context.Context) Querier {
+	if state := txFromContext(ctx); state != nil {
+		return state.tx
+	}
+	if len(db.replicas) == 0 || UsePrimary(ctx) {
+		return db.DB
+	}
+
+	start := db.next.Add(1)
+	for i := range db.replicas {
+		r := db.replicas[(start+uint64(i))%uint64(len(db.replicas))]
+		if r.healthy.Load() {
+			return r.db
+		}
+	}
+
+	return db.DB
+}
+
+// CheckReplicas pings every replica, the ones which do not answer within timeout stop receiving reads.
+// log gets the replicas whose health changed.
+func (db *DB) CheckReplicas(ctx context.Context, timeout time.Duration, log logger.Logger) {
+	for _, r := range db.replicas {
+		pingCtx, cancel := context.WithTimeout(ctx, timeout)
+		err := r.db.PingContext(pingCtx)
+		cancel()
+
+		healthy := err == nil
+		if r.healthy.Swap(healthy) != healthy {
+			if healthy {
+				log.Infof("postgres: replica %s is healthy", r.addr)
+			} else {
+				log.Warnf("postgres: replica %s is unhealthy, reads fall back: %v", r.addr, err)
+			}
+		}
+	}
+}
+
+// WatchReplicas checks the replicas now and then every interval until ctx is done
+func (db *DB) WatchReplicas(ctx context.Context, interval time.Duration, log logger.Logger) {
+	if len(db.replicas) == 0 {
+		return
+	}
+
+	db.CheckReplicas(ctx, interval, log)
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				db.CheckReplicas(ctx, interval, log)
+			}
+		}
+	}()
+}
+
+// Close closes the replicas and the primary
+func (db *DB) Close() error {
+	for _, r := range db.replicas {
+		r.db.Close()
+	}
+
+	return db.DB.Close()
+}
@@ -0,0 +1,129 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/labstack/echo/v4"
+	"github.com/realtemirov/task-for-dell/pkg/logger"
+	"github.com/stretchr/testify/require"
+)
+
+// newMockDB returns a sqlx db whose pings are expected on mock
+func newMockDB(t *testing.T) (*sqlx.DB, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	return sqlx.NewDb(db, "sqlmock"), mock
+}
+
+func TestDB_Reader(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	primary, _ := newMockDB(t)
+	replica1, mock1 := newMockDB(t)
+	replica2, mock2 := newMockDB(t)
+
+	db := NewDB(primary)
+	require.Same(t, primary, db.Reader(ctx))
+
+	// replicas receive reads once they are healthy
+	db.AddReplica("replica1:5432", replica1)
+	db.AddReplica("replica2:5432", replica2)
+	require.Same(t, primary, db.Reader(ctx))
+
+	t.Run("Reader healthy", func(t *testing.T) {
+		mock1.ExpectPing()
+		mock2.ExpectPing()
+		db.CheckReplicas(ctx, time.Second, logger.NewApiLogger(nil))
+
+		// replicas take turns
+		readers := map[Querier]int{}
+		for i := 0; i < 4; i++ {
+			readers[db.Reader(ctx)]++
+		}
+		require.Equal(t, map[Querier]int{replica1: 2, replica2: 2}, readers)
+
+		// reads of a request which wrote go to the primary
+		require.Same(t, primary, db.Reader(WithPrimary(ctx)))
+	})
+
+	t.Run("Reader unhealthy", func(t *testing.T) {
+		mock1.ExpectPing().WillReturnError(&net.OpError{Op: "dial", Err: errors.New("connection refused")})
+		mock2.ExpectPing()
+		db.CheckReplicas(ctx, time.Second, logger.NewApiLogger(nil))
+
+		for i := 0; i < 4; i++ {
+			require.Same(t, replica2, db.Reader(ctx))
+		}
+	})
+
+	t.Run("Reader fallback", func(t *testing.T) {
+		mock1.ExpectPing().WillReturnError(errors.New("timeout"))
+		mock2.ExpectPing().WillReturnError(errors.New("timeout"))
+		db.CheckReplicas(ctx, time.Second, logger.NewApiLogger(nil))
+
+		require.Same(t, primary, db.Reader(ctx))
+	})
+
+	require.NoError(t, mock1.ExpectationsWereMet())
+	require.NoError(t, mock2.ExpectationsWereMet())
+}
+
+func TestReadYourWrites(t *testing.T) {
+	t.Parallel()
+
+	// the handler answers whether its reads go to the primary
+	e := echo.New()
+	e.Use(ReadYourWrites(5 * time.Second))
+	handler := func(c echo.Context) error {
+		if c.QueryParam("fail") != "" {
+			return c.NoContent(http.StatusBadRequest)
+		}
+		return c.JSON(http.StatusOK, UsePrimary(c.Request().Context()))
+	}
+	e.GET("/v1/blogs", handler)
+	e.POST("/v1/blogs", handler)
+
+	serve := func(req *http.Request) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("ReadYourWrites read", func(t *testing.T) {
+		rec := serve(httptest.NewRequest(http.MethodGet, "/v1/blogs", nil))
+		require.Equal(t, "false\n", rec.Body.String())
+		require.Empty(t, rec.Result().Cookies())
+	})
+
+	t.Run("ReadYourWrites write", func(t *testing.T) {
+		rec := serve(httptest.NewRequest(http.MethodPost, "/v1/blogs", nil))
+		require.Equal(t, "true\n", rec.Body.String())
+
+		cookies := rec.Result().Cookies()
+		require.Len(t, cookies, 1)
+		require.Equal(t, READ_PRIMARY_COOKIE, cookies[0].Name)
+		require.Equal(t, 5, cookies[0].MaxAge)
+
+		// the next reads of the client go to the primary
+		req := httptest.NewRequest(http.MethodGet, "/v1/blogs", nil)
+		req.AddCookie(cookies[0])
+		require.Equal(t, "true\n", serve(req).Body.String())
+	})
+
+	t.Run("ReadYourWrites failed write", func(t *testing.T) {
+		rec := serve(httptest.NewRequest(http.MethodPost, "/v1/blogs?fail=1", nil))
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+		require.Empty(t, rec.Result().Cookies())
+	})
+}
@@ -0,0 +1,83 @@
+package postgres
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"net"
+	"strings"
+
+	"github.com/realtemirov/task-for-dell/pkg/domainErrors"
+)
+
+// SQLSTATE codes mapped to domain errors
+const (
+	uniqueViolation           = "23505"
+	foreignKeyViolation       = "23503"
+	notNullViolation          = "23502"
+	checkViolation            = "23514"
+	stringDataRightTruncation = "22001"
+	queryCanceled             = "57014"
+	adminShutdown             = "57P01"
+	cannotConnectNow          = "57P03"
+	serializationFailure      = "40001"
+	deadlockDetected          = "40P01"
+
+	// classes of SQLSTATE codes
+	connectionExceptionClass  = "08"
+	insufficientResourceClass = "53"
+)
+
+// sqlStater is implemented by the errors of the pgx driver
+type sqlStater interface {
+	SQLState() string
+}
+
+// MapError converts an error of the database into a domain error, so handlers
+// can answer with a precise status. Errors it does not know are returned as they are.
+func MapError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	// already classified
+	if domainErrors.KindOf(err) != domainErrors.KindUnknown {
+		return err
+	}
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return domainErrors.NotFound("record not found", err)
+	}
+
+	var pgErr sqlStater
+	if errors.As(err, &pgErr) {
+		return mapSQLState(pgErr.SQLState(), err)
+	}
+
+	// the connection is gone, retrying later may work
+	var netErr net.Error
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, sql.ErrConnDone) || errors.As(err, &netErr) {
+		return domainErrors.Unavailable("database is unavailable", err)
+	}
+
+	return err
+}
+
+func mapSQLState(code string, err error) error {
+	switch {
+	case code == uniqueViolation:
+		return domainErrors.Conflict("record already exists", err)
+	case code == foreignKeyViolation:
+		return domainErrors.Conflict("record is referenced or references a missing record", err)
+	case code == serializationFailure, code == deadlockDetected:
+		return domainErrors.Conflict("concurrent update, retry the request", err)
+	case code == checkViolation, code == notNullViolation, code == stringDataRightTruncation:
+		return domainErrors.Invalid("record breaks a constraint", err)
+	case code == queryCanceled, code == adminShutdown, code == cannotConnectNow:
+		return domainErrors.Unavailable("database is unavailable", err)
+	case strings.HasPrefix(code, connectionExceptionClass), strings.HasPrefix(code, insufficientResourceClass):
+		return domainErrors.Unavailable("database is unavailable", err)
+	default:
+		return err
+	}
+}
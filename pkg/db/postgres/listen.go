@@ -0,0 +1,41 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/jackc/pgx"
+	"github.com/realtemirov/task-for-dell/config"
+)
+
+// Listen calls notify with the payload of every notification of channel until ctx is done, on a connection
+// of its own outside of the pool. listening is called once the channel is listened to, notifications sent
+// before are lost. It returns nil when ctx is done, and the error when the connection is lost.
+func Listen(ctx context.Context, cfg *config.PostgresConfig, channel string, listening func(), notify func(payload string)) error {
+	connConfig, err := pgx.ParseConnectionString(DSN(cfg))
+	if err != nil {
+		return err
+	}
+
+	conn, err := pgx.Connect(connConfig)
+	if err != nil {
+		return MapError(err)
+	}
+	defer conn.Close()
+
+	if err = conn.Listen(channel); err != nil {
+		return MapError(err)
+	}
+	listening()
+
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			return MapError(err)
+		}
+
+		notify(notification.Payload)
+	}
+}
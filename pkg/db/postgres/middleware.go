@@ -0,0 +1,53 @@
+package postgres
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// READ_PRIMARY_COOKIE is set after a write, the reads of requests sending it back go to the primary
+const READ_PRIMARY_COOKIE = "read_primary"
+
+// ReadYourWrites sends the reads of mutations, and of the requests of a client during window
+// after its last successful mutation, to the primary, so a client never misses its own writes
+// while replicas catch up. A window of 0 only covers the mutation itself.
+func ReadYourWrites(window time.Duration) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			mutation := isMutation(c.Request().Method)
+			if _, err := c.Cookie(READ_PRIMARY_COOKIE); err == nil || mutation {
+				c.SetRequest(c.Request().WithContext(WithPrimary(c.Request().Context())))
+			}
+
+			// the cookie goes with the headers, before the body of the response
+			if mutation && window > 0 {
+				c.Response().Before(func() {
+					if c.Response().Status < http.StatusBadRequest {
+						c.SetCookie(&http.Cookie{
+							Name:     READ_PRIMARY_COOKIE,
+							Value:    "1",
+							Path:     "/",
+							MaxAge:   int(window.Seconds()),
+							HttpOnly: true,
+							SameSite: http.SameSiteLaxMode,
+						})
+					}
+				})
+			}
+
+			return next(c)
+		}
+	}
+}
+
+// isMutation reports whether requests of the method write
+func isMutation(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return false
+	default:
+		return true
+	}
+}
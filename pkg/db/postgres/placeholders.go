@@ -0,0 +1,42 @@
+package postgres
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Placeholders returns the VALUES list of a multi-row INSERT, e.g. ($1, $2), ($3, $4) for 2 rows of 2 columns.
+func Placeholders(rows, columns int) string {
+	var b strings.Builder
+	for row := 0; row < rows; row++ {
+		if row > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteByte('(')
+		for column := 0; column < columns; column++ {
+			if column > 0 {
+				b.WriteString(", ")
+			}
+			b.WriteByte('$')
+			b.WriteString(strconv.Itoa(row*columns + column + 1))
+		}
+		b.WriteByte(')')
+	}
+
+	return b.String()
+}
+
+// Int64Array returns ids as an array literal, e.g. {1,2,3}, to be cast with $1::integer[] or $1::bigint[].
+func Int64Array(ids []int64) string {
+	var b strings.Builder
+	b.WriteByte('{')
+	for i, id := range ids {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(strconv.FormatInt(id, 10))
+	}
+	b.WriteByte('}')
+
+	return b.String()
+}
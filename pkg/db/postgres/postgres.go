@@ -1,48 +1,140 @@
 package postgres
 
 import (
-	"fmt"
+	"net"
+	"net/url"
+	"strconv"
 	"time"
 
+	"github.com/XSAM/otelsql"
 	_ "github.com/jackc/pgx/stdlib" // pgx driver
 	"github.com/jmoiron/sqlx"
 	"github.com/realtemirov/task-for-dell/config"
+	"github.com/realtemirov/task-for-dell/pkg/domainErrors"
+	"github.com/realtemirov/task-for-dell/pkg/logger"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
 )
 
-const (
-	maxOpenConnections    = 60
-	connectionMaxLifetime = 120
-	maxIdleConnections    = 30
-	connectionMaxIdleTime = 60
-)
+// MAX_CONNECT_BACKOFF caps the wait between two connection attempts at startup
+const MAX_CONNECT_BACKOFF = 30 * time.Second
 
-// NewPostgresDB returns a new postgres DB instance.
-func NewPostgresDB(cfg *config.Config) (*sqlx.DB, error) {
-
-	connectionString := fmt.Sprintf(
-		"postgres://%s:%s@%s:%s/%s?sslmode=%s",
-		cfg.Postgres.Username,
-		cfg.Postgres.Password,
-		cfg.Postgres.Host,
-		cfg.Postgres.Port,
-		cfg.Postgres.DBName,
-		cfg.Postgres.SSLMode,
-	)
+// NewPostgresDB returns a new postgres DB instance. The database is pinged up to
+// ConnectRetries more times while it is unavailable, waiting twice longer each time, log gets the failed attempts.
+func NewPostgresDB(cfg *config.Config, log logger.Logger) (*sqlx.DB, error) {
+	db, err := open(&cfg.Postgres)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = pingWithRetry(db.Ping, cfg.Postgres.ConnectRetries, cfg.Postgres.ConnectBackoff*time.Second, log); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return db, nil
+}
 
-	db, err := sqlx.Connect(cfg.Postgres.PgDriver, connectionString)
+// NewReplicatedDB returns the primary of NewPostgresDB with the replicas of the config,
+// replicas are not pinged, they receive reads once WatchReplicas found them healthy
+func NewReplicatedDB(cfg *config.Config, log logger.Logger) (*DB, error) {
+	primary, err := NewPostgresDB(cfg, log)
 	if err != nil {
-		fmt.Println("error connecting to db", connectionString)
 		return nil, err
 	}
+	db := NewDB(primary)
 
-	db.SetMaxOpenConns(maxOpenConnections)
-	db.SetConnMaxLifetime(connectionMaxLifetime * time.Second)
-	db.SetMaxIdleConns(maxIdleConnections)
-	db.SetConnMaxIdleTime(connectionMaxIdleTime * time.Second)
+	for _, addr := range cfg.Postgres.Replicas {
+		replicaCfg := cfg.Postgres
+		if replicaCfg.Host, replicaCfg.Port, err = net.SplitHostPort(addr); err != nil {
+			db.Close()
+			return nil, err
+		}
 
-	if err = db.Ping(); err != nil {
+		replicaDB, err := open(&replicaCfg)
+		if err != nil {
+			db.Close()
+			return nil, err
+		}
+		db.AddReplica(addr, replicaDB)
+	}
+
+	return db, nil
+}
+
+// open returns a pool of the config without connecting
+func open(cfg *config.PostgresConfig) (*sqlx.DB, error) {
+
+	// every query is a span with its statement, child of the span in the query context
+	sqlDB, err := otelsql.Open(
+		cfg.PgDriver,
+		DSN(cfg),
+		otelsql.WithAttributes(semconv.DBSystemPostgreSQL),
+		otelsql.WithSpanOptions(otelsql.SpanOptions{
+			OmitConnResetSession: true,
+			OmitRows:             true,
+		}),
+	)
+	if err != nil {
 		return nil, err
 	}
+	db := sqlx.NewDb(sqlDB, cfg.PgDriver)
+
+	db.SetMaxOpenConns(cfg.MaxOpenConns)
+	db.SetConnMaxLifetime(cfg.ConnMaxLifetime * time.Second)
+	db.SetMaxIdleConns(cfg.MaxIdleConns)
+	db.SetConnMaxIdleTime(cfg.ConnMaxIdleTime * time.Second)
 
 	return db, nil
 }
+
+// DSN returns the connection URL of the config, every part is escaped
+func DSN(cfg *config.PostgresConfig) string {
+	query := url.Values{}
+	query.Set("sslmode", cfg.SSLMode)
+	if cfg.SSLRootCert != "" {
+		query.Set("sslrootcert", cfg.SSLRootCert)
+	}
+	if cfg.SSLCert != "" {
+		query.Set("sslcert", cfg.SSLCert)
+		query.Set("sslkey", cfg.SSLKey)
+	}
+	if cfg.ConnectTimeout > 0 {
+		query.Set("connect_timeout", strconv.FormatInt(int64(cfg.ConnectTimeout), 10))
+	}
+
+	// session defaults of every connection, statement_timeout is in milliseconds
+	if cfg.StatementTimeout > 0 {
+		query.Set("statement_timeout", strconv.FormatInt((cfg.StatementTimeout*time.Second).Milliseconds(), 10))
+	}
+	if cfg.ApplicationName != "" {
+		query.Set("application_name", cfg.ApplicationName)
+	}
+
+	dsn := url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(cfg.Username, cfg.Password),
+		Host:     net.JoinHostPort(cfg.Host, cfg.Port),
+		Path:     "/" + cfg.DBName,
+		RawQuery: query.Encode(),
+	}
+
+	return dsn.String()
+}
+
+// pingWithRetry pings until it succeeds, the error is not an unavailable database or retries are exhausted
+func pingWithRetry(ping func() error, retries int, backoff time.Duration, log logger.Logger) error {
+	for attempt := 0; ; attempt++ {
+		err := ping()
+		if err == nil || attempt >= retries || !domainErrors.Is(MapError(err), domainErrors.KindUnavailable) {
+			return err
+		}
+
+		log.Warnf("postgres: attempt %d of %d failed, retrying in %s: %v", attempt+1, retries+1, backoff, err)
+		time.Sleep(backoff)
+
+		backoff *= 2
+		if backoff > MAX_CONNECT_BACKOFF {
+			backoff = MAX_CONNECT_BACKOFF
+		}
+	}
+}
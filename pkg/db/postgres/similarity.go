@@ -0,0 +1,73 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+)
+
+// SetSimilarityThresholdQuery sets the pg_trgm thresholds used by the % and <% operators
+// for the current transaction only.
+const SetSimilarityThresholdQuery = `SELECT set_config('pg_trgm.similarity_threshold', $1, true), set_config('pg_trgm.word_similarity_threshold', $1, true)`
+
+// WithSimilarityThreshold runs fn in a read-only transaction whose pg_trgm thresholds are set to threshold,
+// so the trigram operators can use the GIN indexes while honoring a configurable threshold.
+// In the transaction of WithinTx, fn runs in a savepoint rolled back afterwards, which restores the thresholds.
+func WithSimilarityThreshold(ctx context.Context, db Querier, threshold float64, fn func(tx Querier) error) error {
+
+	pool, ok := db.(*sqlx.DB)
+	if !ok {
+		tx, ok := db.(*sqlx.Tx)
+		if !ok {
+			return errors.Errorf("postgres.WithSimilarityThreshold: unsupported querier %T", db)
+		}
+		return withSavepointSimilarityThreshold(ctx, tx, threshold, fn)
+	}
+
+	tx, err := pool.BeginTxx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return errors.Wrap(MapError(err), "postgres.WithSimilarityThreshold.BeginTxx")
+	}
+	defer tx.Rollback() //nolint:errcheck // no-op after commit
+
+	if _, err = tx.ExecContext(
+		ctx,
+		SetSimilarityThresholdQuery,
+		strconv.FormatFloat(threshold, 'f', -1, 64),
+	); err != nil {
+		return errors.Wrap(MapError(err), "postgres.WithSimilarityThreshold.ExecContext")
+	}
+
+	if err = fn(tx); err != nil {
+		return err
+	}
+
+	return errors.Wrap(MapError(tx.Commit()), "postgres.WithSimilarityThreshold.Commit")
+}
+
+// withSavepointSimilarityThreshold sets the thresholds in a savepoint of the transaction while fn runs
+func withSavepointSimilarityThreshold(ctx context.Context, tx *sqlx.Tx, threshold float64, fn func(tx Querier) error) error {
+	if _, err := tx.ExecContext(ctx, "SAVEPOINT similarity"); err != nil {
+		return errors.Wrap(MapError(err), "postgres.WithSimilarityThreshold.Savepoint")
+	}
+
+	_, err := tx.ExecContext(ctx, SetSimilarityThresholdQuery, strconv.FormatFloat(threshold, 'f', -1, 64))
+	if err != nil {
+		err = errors.Wrap(MapError(err), "postgres.WithSimilarityThreshold.ExecContext")
+	} else {
+		err = fn(tx)
+	}
+
+	// fn only reads, rolling back restores the thresholds of the transaction
+	if _, rollbackErr := tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT similarity"); rollbackErr != nil && err == nil {
+		err = errors.Wrap(MapError(rollbackErr), "postgres.WithSimilarityThreshold.RollbackToSavepoint")
+	}
+	if _, releaseErr := tx.ExecContext(ctx, "RELEASE SAVEPOINT similarity"); releaseErr != nil && err == nil {
+		err = errors.Wrap(MapError(releaseErr), "postgres.WithSimilarityThreshold.ReleaseSavepoint")
+	}
+
+	return err
+}
@@ -0,0 +1,133 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+)
+
+const (
+	// TX_MAX_RETRIES is the number of times a transaction is run again after a serialization failure or a deadlock
+	TX_MAX_RETRIES = 3
+
+	// TX_RETRY_BACKOFF is the wait before the first retry, every next one waits one more
+	TX_RETRY_BACKOFF = 20 * time.Millisecond
+)
+
+// Querier runs queries on the pool or in a transaction, *sqlx.DB and *sqlx.Tx implement it
+type Querier interface {
+	sqlx.ExtContext
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+	SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+}
+
+// TxManager runs functions in one transaction, DB implements it
+type TxManager interface {
+
+	// WithinTx runs fn in a transaction committed when fn returns nil and rolled back otherwise.
+	// Repositories called with the ctx of fn run in the transaction, a nested WithinTx runs in a savepoint.
+	// The transaction is run again on serialization failures and deadlocks, so fn must be safe to repeat.
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// txKey binds the transaction of WithinTx to its context
+type txKey struct{}
+
+// txState is the transaction of a context and the depth of its savepoints
+type txState struct {
+	tx         *sqlx.Tx
+	savepoints int
+}
+
+// txFromContext returns the transaction of ctx, nil outside of WithinTx
+func txFromContext(ctx context.Context) *txState {
+	state, _ := ctx.Value(txKey{}).(*txState)
+	return state
+}
+
+// Writer returns the transaction of ctx, or the primary outside of WithinTx
+func (db *DB) Writer(ctx context.Context) Querier {
+	if state := txFromContext(ctx); state != nil {
+		return state.tx
+	}
+
+	return db.DB
+}
+
+// WithinTx implements TxManager.
+func (db *DB) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if state := txFromContext(ctx); state != nil {
+		return state.withinSavepoint(ctx, fn)
+	}
+
+	for attempt := 0; ; attempt++ {
+		err := db.withinTx(ctx, fn)
+		if err == nil || attempt >= TX_MAX_RETRIES || !isRetryable(err) {
+			return err
+		}
+
+		time.Sleep(TX_RETRY_BACKOFF * time.Duration(attempt+1))
+	}
+}
+
+// WithinSavepoint runs fn in a savepoint of the transaction of ctx, so an error of fn does not abort
+// the transaction. Outside of WithinTx fn runs as it is.
+func (db *DB) WithinSavepoint(ctx context.Context, fn func(ctx context.Context) error) error {
+	if state := txFromContext(ctx); state != nil {
+		return state.withinSavepoint(ctx, fn)
+	}
+
+	return fn(ctx)
+}
+
+// withinTx runs fn in a new transaction, reads of fn go to the primary through the transaction
+func (db *DB) withinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	tx, err := db.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(MapError(err), "postgres.WithinTx.BeginTxx")
+	}
+	defer tx.Rollback() //nolint:errcheck // no-op after commit
+
+	if err = fn(context.WithValue(WithPrimary(ctx), txKey{}, &txState{tx: tx})); err != nil {
+		return err
+	}
+
+	return errors.Wrap(MapError(tx.Commit()), "postgres.WithinTx.Commit")
+}
+
+// withinSavepoint runs fn in a savepoint of the transaction, an error of fn only rolls back the savepoint
+func (s *txState) withinSavepoint(ctx context.Context, fn func(ctx context.Context) error) error {
+	s.savepoints++
+	defer func() { s.savepoints-- }()
+	savepoint := "sp_" + strconv.Itoa(s.savepoints)
+
+	if _, err := s.tx.ExecContext(ctx, "SAVEPOINT "+savepoint); err != nil {
+		return errors.Wrap(MapError(err), "postgres.WithinTx.Savepoint")
+	}
+
+	if err := fn(ctx); err != nil {
+		if _, rollbackErr := s.tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+savepoint); rollbackErr != nil {
+			return errors.Wrap(MapError(rollbackErr), "postgres.WithinTx.RollbackToSavepoint")
+		}
+		return err
+	}
+
+	_, err := s.tx.ExecContext(ctx, "RELEASE SAVEPOINT "+savepoint)
+
+	return errors.Wrap(MapError(err), "postgres.WithinTx.ReleaseSavepoint")
+}
+
+// isRetryable reports whether running the transaction again may succeed
+func isRetryable(err error) bool {
+	var pgErr sqlStater
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+
+	return pgErr.SQLState() == serializationFailure || pgErr.SQLState() == deadlockDetected
+}
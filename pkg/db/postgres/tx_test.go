@@ -0,0 +1,178 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/require"
+)
+
+// stateError is an error of the driver with a SQLSTATE
+type stateError string
+
+func (e stateError) Error() string    { return "sqlstate " + string(e) }
+func (e stateError) SQLState() string { return string(e) }
+
+// newTxDB returns a DB whose primary expects the queries on mock
+func newTxDB(t *testing.T) (*DB, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	return NewDB(sqlx.NewDb(db, "sqlmock")), mock
+}
+
+func TestDB_WithinTx(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Commit", func(t *testing.T) {
+		db, mock := newTxDB(t)
+
+		mock.ExpectBegin()
+		mock.ExpectExec("DELETE FROM blogs").WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		err := db.WithinTx(context.Background(), func(ctx context.Context) error {
+
+			// reads and writes of ctx go to the transaction
+			require.IsType(t, &sqlx.Tx{}, db.Writer(ctx))
+			require.Same(t, db.Writer(ctx), db.Reader(ctx))
+			require.True(t, UsePrimary(ctx))
+
+			_, err := db.Writer(ctx).ExecContext(ctx, "DELETE FROM blogs")
+			return err
+		})
+
+		require.NoError(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Rollback", func(t *testing.T) {
+		db, mock := newTxDB(t)
+		errFn := errors.New("fn failed")
+
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+
+		err := db.WithinTx(context.Background(), func(ctx context.Context) error {
+			return errFn
+		})
+
+		require.ErrorIs(t, err, errFn)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Savepoint", func(t *testing.T) {
+		db, mock := newTxDB(t)
+		errFn := errors.New("fn failed")
+
+		mock.ExpectBegin()
+		mock.ExpectExec("SAVEPOINT sp_1").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("RELEASE SAVEPOINT sp_1").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("SAVEPOINT sp_1").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("SAVEPOINT sp_2").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("ROLLBACK TO SAVEPOINT sp_2").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("ROLLBACK TO SAVEPOINT sp_1").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		err := db.WithinTx(context.Background(), func(ctx context.Context) error {
+			tx := db.Writer(ctx)
+
+			// a nested WithinTx shares the transaction
+			require.NoError(t, db.WithinTx(ctx, func(ctx context.Context) error {
+				require.Same(t, tx, db.Writer(ctx))
+				return nil
+			}))
+
+			// its error only rolls back its savepoint
+			err := db.WithinTx(ctx, func(ctx context.Context) error {
+				return db.WithinTx(ctx, func(ctx context.Context) error {
+					return errFn
+				})
+			})
+			require.ErrorIs(t, err, errFn)
+
+			return nil
+		})
+
+		require.NoError(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Retry", func(t *testing.T) {
+		db, mock := newTxDB(t)
+
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE blogs SET title = title").WillReturnError(stateError(serializationFailure))
+		mock.ExpectRollback()
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE blogs SET title = title").WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		attempts := 0
+		err := db.WithinTx(context.Background(), func(ctx context.Context) error {
+			attempts++
+			_, err := db.Writer(ctx).ExecContext(ctx, "UPDATE blogs SET title = title")
+			return MapError(err)
+		})
+
+		require.NoError(t, err)
+		require.Equal(t, 2, attempts)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Retry exhausted", func(t *testing.T) {
+		db, mock := newTxDB(t)
+
+		for i := 0; i <= TX_MAX_RETRIES; i++ {
+			mock.ExpectBegin()
+			mock.ExpectRollback()
+		}
+
+		attempts := 0
+		err := db.WithinTx(context.Background(), func(ctx context.Context) error {
+			attempts++
+			return stateError(deadlockDetected)
+		})
+
+		require.Error(t, err)
+		require.Equal(t, TX_MAX_RETRIES+1, attempts)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("WithinSavepoint", func(t *testing.T) {
+		db, mock := newTxDB(t)
+		errFn := errors.New("fn failed")
+
+		// outside of WithinTx fn runs as it is
+		require.ErrorIs(t, db.WithinSavepoint(context.Background(), func(ctx context.Context) error {
+			return errFn
+		}), errFn)
+
+		// in a transaction its error only rolls back its savepoint
+		mock.ExpectBegin()
+		mock.ExpectExec("SAVEPOINT sp_1").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("ROLLBACK TO SAVEPOINT sp_1").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		err := db.WithinTx(context.Background(), func(ctx context.Context) error {
+			require.ErrorIs(t, db.WithinSavepoint(ctx, func(ctx context.Context) error {
+				return errFn
+			}), errFn)
+			return nil
+		})
+
+		require.NoError(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Writer", func(t *testing.T) {
+		db, _ := newTxDB(t)
+
+		// outside of WithinTx writes go to the primary
+		require.Same(t, db.DB, db.Writer(context.Background()))
+	})
+}
@@ -0,0 +1,107 @@
+package domainErrors
+
+import "errors"
+
+// Kind classifies a domain error, handlers choose the response status by it
+type Kind int
+
+const (
+	KindUnknown Kind = iota
+	KindNotFound
+	KindConflict
+	KindInvalid
+	KindUnauthorized
+	KindForbidden
+	KindUnavailable
+)
+
+var kindNames = map[Kind]string{
+	KindUnknown:      "unknown",
+	KindNotFound:     "not found",
+	KindConflict:     "conflict",
+	KindInvalid:      "invalid",
+	KindUnauthorized: "unauthorized",
+	KindForbidden:    "forbidden",
+	KindUnavailable:  "unavailable",
+}
+
+func (k Kind) String() string {
+	return kindNames[k]
+}
+
+// Error is an error of the domain. Message is safe to show to clients,
+// Err is the cause kept for logs and errors.Is.
+type Error struct {
+	Kind    Kind
+	Message string
+	Err     error
+}
+
+func (e *Error) Error() string {
+	if e.Err == nil {
+		return e.Message
+	}
+	if e.Message == "" {
+		return e.Err.Error()
+	}
+
+	return e.Message + ": " + e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// New constructs an Error of the kind, err may be nil
+func New(kind Kind, message string, err error) error {
+	return &Error{
+		Kind:    kind,
+		Message: message,
+		Err:     err,
+	}
+}
+
+// NotFound is returned when the requested record does not exist
+func NotFound(message string, err error) error {
+	return New(KindNotFound, message, err)
+}
+
+// Conflict is returned when the change collides with the current state, e.g. a duplicate
+func Conflict(message string, err error) error {
+	return New(KindConflict, message, err)
+}
+
+// Invalid is returned when well-formed input breaks a rule of the domain
+func Invalid(message string, err error) error {
+	return New(KindInvalid, message, err)
+}
+
+// Unauthorized is returned when the caller is not authenticated
+func Unauthorized(message string, err error) error {
+	return New(KindUnauthorized, message, err)
+}
+
+// Forbidden is returned when the caller is not allowed to do the operation
+func Forbidden(message string, err error) error {
+	return New(KindForbidden, message, err)
+}
+
+// Unavailable is returned when a dependency can not serve the request right now
+func Unavailable(message string, err error) error {
+	return New(KindUnavailable, message, err)
+}
+
+// KindOf returns the kind of the first Error in the chain of err, KindUnknown when there is none
+func KindOf(err error) Kind {
+	var domainErr *Error
+	if errors.As(err, &domainErr) {
+		return domainErr.Kind
+	}
+
+	return KindUnknown
+}
+
+// Is reports whether err is an Error of the kind
+func Is(err error, kind Kind) bool {
+	return KindOf(err) == kind
+}
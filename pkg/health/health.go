@@ -0,0 +1,152 @@
+package health
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// statuses of the probes and their checks
+const (
+	STATUS_OK       = "ok"
+	STATUS_FAIL     = "fail"
+	STATUS_DRAINING = "draining"
+)
+
+// Check reports whether a dependency is usable, it must return when ctx is done
+type Check func(ctx context.Context) error
+
+// DetailedCheck is a Check also reporting details of the dependency, e.g. its version
+type DetailedCheck func(ctx context.Context) (interface{}, error)
+
+// CheckResult is the result of one check of a probe
+type CheckResult struct {
+	Name     string      `json:"name" example:"postgres"`
+	Status   string      `json:"status" example:"ok"`
+	Duration string      `json:"duration" example:"1.2ms"`
+	Error    string      `json:"error,omitempty" example:"database is unavailable"`
+	Details  interface{} `json:"details,omitempty"`
+}
+
+// Response is the body of the probes
+type Response struct {
+	Status string         `json:"status" example:"ok"`
+	Checks []*CheckResult `json:"checks,omitempty"`
+}
+
+// Health serves the liveness and readiness probes
+type Health struct {
+	timeout  time.Duration
+	checks   map[string]DetailedCheck
+	draining atomic.Bool
+}
+
+// NewHealth returns the probes, every readiness check is canceled after timeout
+func NewHealth(timeout time.Duration) *Health {
+	return &Health{
+		timeout: timeout,
+		checks:  make(map[string]DetailedCheck),
+	}
+}
+
+// AddCheck adds a dependency the app is not ready without
+func (h *Health) AddCheck(name string, check Check) {
+	h.AddDetailedCheck(name, func(ctx context.Context) (interface{}, error) {
+		return nil, check(ctx)
+	})
+}
+
+// AddDetailedCheck adds a dependency the app is not ready without, reporting its details
+func (h *Health) AddDetailedCheck(name string, check DetailedCheck) {
+	h.checks[name] = check
+}
+
+// SetDraining makes the app not ready, so no new traffic is routed to it while it shuts down
+func (h *Health) SetDraining() {
+	h.draining.Store(true)
+}
+
+// Liveness reports that the process is alive and serving, it checks no dependencies
+//
+// @Summary Liveness probe
+// @Tags Health
+// @Produce  json
+// @Success 200 {object} health.Response
+// @Router /healthz [get]
+func (h *Health) Liveness(c echo.Context) error {
+	return c.JSON(http.StatusOK, &Response{Status: STATUS_OK})
+}
+
+// Readiness reports whether the app can serve traffic: it is not draining and every check passes
+//
+// @Summary Readiness probe
+// @Tags Health
+// @Produce  json
+// @Success 200 {object} health.Response
+// @Failure 503 {object} health.Response
+// @Router /readyz [get]
+func (h *Health) Readiness(c echo.Context) error {
+
+	if h.draining.Load() {
+		return c.JSON(http.StatusServiceUnavailable, &Response{Status: STATUS_DRAINING})
+	}
+
+	response := h.Check(c.Request().Context())
+	if response.Status != STATUS_OK {
+		return c.JSON(http.StatusServiceUnavailable, response)
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// Check runs every check concurrently and returns their results by name
+func (h *Health) Check(ctx context.Context) *Response {
+
+	ctx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+
+	var (
+		wg      sync.WaitGroup
+		results = make([]*CheckResult, 0, len(h.checks))
+		mu      sync.Mutex
+	)
+	for name, check := range h.checks {
+		wg.Add(1)
+		go func(name string, check DetailedCheck) {
+			defer wg.Done()
+
+			start := time.Now()
+			result := &CheckResult{Name: name, Status: STATUS_OK}
+			details, err := check(ctx)
+			result.Details = details
+			if err != nil {
+				result.Status = STATUS_FAIL
+				result.Error = err.Error()
+			}
+			result.Duration = time.Since(start).String()
+
+			mu.Lock()
+			results = append(results, result)
+			mu.Unlock()
+		}(name, check)
+	}
+	wg.Wait()
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Name < results[j].Name
+	})
+
+	response := &Response{Status: STATUS_OK, Checks: results}
+	for _, result := range results {
+		if result.Status != STATUS_OK {
+			response.Status = STATUS_FAIL
+		}
+	}
+
+	return response
+}
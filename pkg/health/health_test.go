@@ -0,0 +1,109 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+)
+
+// serve calls the probe and decodes its response
+func serve(t *testing.T, probe echo.HandlerFunc) (int, *Response) {
+	request := httptest.NewRequest(http.MethodGet, "/readyz", nil)
+	response := httptest.NewRecorder()
+
+	require.NoError(t, probe(echo.New().NewContext(request, response)))
+
+	result := &Response{}
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), result))
+
+	return response.Code, result
+}
+
+func TestHealth_Liveness(t *testing.T) {
+	t.Parallel()
+
+	// liveness checks nothing
+	h := NewHealth(time.Second)
+	h.AddCheck("postgres", func(ctx context.Context) error {
+		return errors.New("database is unavailable")
+	})
+
+	status, response := serve(t, h.Liveness)
+	require.Equal(t, http.StatusOK, status)
+	require.Equal(t, STATUS_OK, response.Status)
+}
+
+func TestHealth_Readiness(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Readiness ok", func(t *testing.T) {
+		h := NewHealth(time.Second)
+		h.AddCheck("postgres", func(ctx context.Context) error { return nil })
+		h.AddCheck("migrations", func(ctx context.Context) error { return nil })
+
+		status, response := serve(t, h.Readiness)
+		require.Equal(t, http.StatusOK, status)
+		require.Equal(t, STATUS_OK, response.Status)
+		require.Len(t, response.Checks, 2)
+		require.Equal(t, "migrations", response.Checks[0].Name)
+		require.Equal(t, "postgres", response.Checks[1].Name)
+		require.NotEmpty(t, response.Checks[0].Duration)
+	})
+
+	t.Run("Readiness fail", func(t *testing.T) {
+		h := NewHealth(time.Second)
+		h.AddCheck("postgres", func(ctx context.Context) error { return nil })
+		h.AddCheck("migrations", func(ctx context.Context) error {
+			return errors.New("migration version is 3, want 4")
+		})
+
+		status, response := serve(t, h.Readiness)
+		require.Equal(t, http.StatusServiceUnavailable, status)
+		require.Equal(t, STATUS_FAIL, response.Status)
+		require.Equal(t, STATUS_FAIL, response.Checks[0].Status)
+		require.Equal(t, "migration version is 3, want 4", response.Checks[0].Error)
+		require.Equal(t, STATUS_OK, response.Checks[1].Status)
+	})
+
+	t.Run("Readiness details", func(t *testing.T) {
+		h := NewHealth(time.Second)
+		h.AddDetailedCheck("migrations", func(ctx context.Context) (interface{}, error) {
+			return map[string]int{"version": 4}, nil
+		})
+
+		status, response := serve(t, h.Readiness)
+		require.Equal(t, http.StatusOK, status)
+		require.Equal(t, map[string]interface{}{"version": float64(4)}, response.Checks[0].Details)
+	})
+
+	t.Run("Readiness timeout", func(t *testing.T) {
+
+		// a hanging dependency fails once the timeout is over
+		h := NewHealth(10 * time.Millisecond)
+		h.AddCheck("postgres", func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		})
+
+		status, response := serve(t, h.Readiness)
+		require.Equal(t, http.StatusServiceUnavailable, status)
+		require.Equal(t, context.DeadlineExceeded.Error(), response.Checks[0].Error)
+	})
+
+	t.Run("Readiness draining", func(t *testing.T) {
+		h := NewHealth(time.Second)
+		h.AddCheck("postgres", func(ctx context.Context) error { return nil })
+		h.SetDraining()
+
+		status, response := serve(t, h.Readiness)
+		require.Equal(t, http.StatusServiceUnavailable, status)
+		require.Equal(t, STATUS_DRAINING, response.Status)
+	})
+}
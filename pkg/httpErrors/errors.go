@@ -4,11 +4,15 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/labstack/echo/v4"
+	"github.com/realtemirov/task-for-dell/pkg/domainErrors"
 	"github.com/realtemirov/task-for-dell/pkg/logger"
+	"github.com/realtemirov/task-for-dell/pkg/utils"
 )
 
 var (
@@ -18,46 +22,160 @@ var (
 	BadQueryParams   string = "BAD_QUERY_PARAMS"
 	RequestTimeOut   string = "REQUEST_TIMEOUT"
 	InternalServer   string = "INTERNAL_SERVER_ERROR"
+	FailedDependency string = "FAILED_DEPENDENCY"
+	ValidationFailed string = "VALIDATION_FAILED"
+	Conflict         string = "CONFLICT"
+	Invalid          string = "INVALID"
+	Unauthorized     string = "UNAUTHORIZED"
+	Forbidden        string = "FORBIDDEN"
+	Unavailable      string = "SERVICE_UNAVAILABLE"
 )
 
-type ErrorMessage struct {
-	Message    string `json:"message"`
-	StatusCode int    `json:"status_code"`
+// domainProblems maps every kind of domain error to its code and status
+var domainProblems = map[domainErrors.Kind]struct {
+	code   string
+	status int
+}{
+	domainErrors.KindNotFound:     {NotFound, http.StatusNotFound},
+	domainErrors.KindConflict:     {Conflict, http.StatusConflict},
+	domainErrors.KindInvalid:      {Invalid, http.StatusUnprocessableEntity},
+	domainErrors.KindUnauthorized: {Unauthorized, http.StatusUnauthorized},
+	domainErrors.KindForbidden:    {Forbidden, http.StatusForbidden},
+	domainErrors.KindUnavailable:  {Unavailable, http.StatusServiceUnavailable},
 }
 
-// NewErrorMessage
-func NewErrorMessage(message string, code int) (int, *ErrorMessage) {
-	return code, &ErrorMessage{
-		Message:    message,
-		StatusCode: code,
+const (
+	// MIMEApplicationProblemJSON is the content type of error responses
+	MIMEApplicationProblemJSON = "application/problem+json"
+
+	// ProblemTypeBase prefixes the type of every problem, followed by its code in kebab case
+	ProblemTypeBase = "/problems/"
+)
+
+// Problem is an error response as described by RFC 7807
+type Problem struct {
+	Type     string              `json:"type" example:"/problems/validation-failed"`
+	Title    string              `json:"title" example:"Bad Request"`
+	Status   int                 `json:"status" example:"400"`
+	Detail   string              `json:"detail,omitempty" example:"1 field failed validation"`
+	Instance string              `json:"instance,omitempty" example:"rFgB7aE1Xq2ZQ4d9KcVn0sLmT3yJw6Hu"`
+	Code     string              `json:"code" example:"VALIDATION_FAILED"`
+	Errors   []*utils.FieldError `json:"errors,omitempty"`
+}
+
+// NewProblem constructs a Problem of the code and status
+func NewProblem(code string, status int) (int, *Problem) {
+	return status, &Problem{
+		Type:   ProblemTypeBase + strings.ToLower(strings.ReplaceAll(code, "_", "-")),
+		Title:  http.StatusText(status),
+		Status: status,
+		Code:   code,
 	}
 }
 
-func ErrResponse(err error) (int, *ErrorMessage) {
+// WithDetail sets the human readable explanation of the problem
+func (p *Problem) WithDetail(detail string) *Problem {
+	p.Detail = detail
+	return p
+}
+
+// ErrResponse returns the status and problem of err, with title and detail in the locale
+func ErrResponse(err error, locale string) (int, *Problem) {
+	status, problem := problemOf(err, locale)
+	problem.Title = Translate(locale, problem.Code, problem.Title)
+
+	return status, problem
+}
+
+func problemOf(err error, locale string) (int, *Problem) {
+
+	// translates a detail, english details are their own keys
+	t := func(detail string) string {
+		return Translate(locale, detail, detail)
+	}
+
+	// field errors of a validation failure
+	if fieldErrors := utils.ValidationErrors(err, locale); fieldErrors != nil {
+		status, problem := NewProblem(ValidationFailed, http.StatusBadRequest)
+		problem.Errors = fieldErrors
+		if len(fieldErrors) == 1 {
+			return status, problem.WithDetail(t("1 field failed validation"))
+		}
+		return status, problem.WithDetail(fmt.Sprintf(t("%d fields failed validation"), len(fieldErrors)))
+	}
+
+	// typed errors of repositories and usecases
+	var domainErr *domainErrors.Error
+	if errors.As(err, &domainErr) {
+		if problem, ok := domainProblems[domainErr.Kind]; ok {
+			status, p := NewProblem(problem.code, problem.status)
+			return status, p.WithDetail(t(domainErr.Message))
+		}
+	}
+
+	// bind errors of echo, e.g. malformed json
+	var httpErr *echo.HTTPError
+	if errors.As(err, &httpErr) && httpErr.Code < http.StatusInternalServerError {
+		code := BadRequest
+		if httpErr.Code != http.StatusBadRequest {
+			code = strings.ToUpper(strings.ReplaceAll(http.StatusText(httpErr.Code), " ", "_"))
+		}
+		status, problem := NewProblem(code, httpErr.Code)
+		return status, problem.WithDetail(fmt.Sprint(httpErr.Message))
+	}
+
+	// path and query params that are not numbers or booleans
+	var numErr *strconv.NumError
+	if errors.As(err, &numErr) {
+		status, problem := NewProblem(BadQueryParams, http.StatusBadRequest)
+		return status, problem.WithDetail(fmt.Sprintf(t("%q is not a valid value"), numErr.Num))
+	}
+
 	switch {
 	case errors.Is(err, sql.ErrNoRows):
-		return NewErrorMessage(NotFound, http.StatusNotFound)
+		return NewProblem(NotFound, http.StatusNotFound)
+	case errors.Is(err, utils.ErrBulkAborted):
+		status, problem := NewProblem(FailedDependency, http.StatusFailedDependency)
+		return status, problem.WithDetail(t(utils.ErrBulkAborted.Error()))
+	case errors.Is(err, utils.ErrBulkSize):
+		return badRequest(utils.ErrBulkSize, locale)
+	case errors.Is(err, utils.ErrBulkMode):
+		return badRequest(utils.ErrBulkMode, locale)
+	case errors.Is(err, utils.ErrFormat):
+		return badRequest(utils.ErrFormat, locale)
+	case errors.Is(err, utils.ErrReport):
+		return badRequest(utils.ErrReport, locale)
+	case errors.Is(err, utils.ErrDryRun):
+		return badRequest(utils.ErrDryRun, locale)
+	case errors.Is(err, utils.ErrLocale):
+		return badRequest(utils.ErrLocale, locale)
+	case errors.Is(err, http.ErrMissingFile):
+		return badRequest(http.ErrMissingFile, locale)
 	case errors.Is(err, context.DeadlineExceeded):
-		return NewErrorMessage(RequestTimeOut, http.StatusRequestTimeout)
-	case strings.Contains(err.Error(), "SQLSTATE"):
-		return NewErrorMessage(BadRequest, http.StatusBadRequest)
-	case strings.Contains(err.Error(), "Field validation"):
-		return NewErrorMessage(BadRequest, http.StatusBadRequest)
-	case strings.Contains(err.Error(), "Unmarshal"):
-		return NewErrorMessage(BadRequest, http.StatusBadRequest)
+		return NewProblem(RequestTimeOut, http.StatusRequestTimeout)
 	default:
-		return NewErrorMessage(InternalServer, http.StatusInternalServerError)
+		return NewProblem(InternalServer, http.StatusInternalServerError)
 	}
 }
 
+// badRequest is a BAD_REQUEST problem detailed by the message of a known error
+func badRequest(err error, locale string) (int, *Problem) {
+	status, problem := NewProblem(BadRequest, http.StatusBadRequest)
+	return status, problem.WithDetail(Translate(locale, err.Error(), err.Error()))
+}
+
 func ErrResponseWithLog(ctx echo.Context, logger logger.Logger, err error) error {
-	logger.Errorf(
-		"ErrResponseWithLog, RequestID: %s, IPAddress: %s, Error: %s",
-		GetRequestID(ctx),
+	logger.FromContext(ctx.Request().Context()).Errorf(
+		"ErrResponseWithLog, IPAddress: %s, Error: %s",
 		GetIPAddress(ctx),
 		err,
 	)
-	return ctx.JSON(ErrResponse(err))
+
+	status, problem := ErrResponse(err, utils.GetLocale(ctx.Request().Context()))
+	problem.Instance = GetRequestID(ctx)
+
+	ctx.Response().Header().Set(echo.HeaderContentType, MIMEApplicationProblemJSON)
+	return ctx.JSON(status, problem)
 }
 
 // Get request id from echo context
@@ -0,0 +1,67 @@
+package httpErrors
+
+import (
+	"embed"
+	"encoding/json"
+	"io/fs"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/realtemirov/task-for-dell/pkg/utils"
+)
+
+// bundles are the message bundles of every locale, named <locale>.json.
+// Keys are error codes for titles and english messages for details.
+//
+//go:embed locales/*.json
+var bundles embed.FS
+
+var (
+	messagesMu sync.RWMutex
+	messages   = map[string]map[string]string{}
+)
+
+// LoadMessages loads the message bundle of every locale, call it once at startup.
+// Until then titles and details are english.
+func LoadMessages() error {
+	entries, err := fs.ReadDir(bundles, "locales")
+	if err != nil {
+		return err
+	}
+
+	loaded := make(map[string]map[string]string, len(entries))
+	for _, entry := range entries {
+		data, err := bundles.ReadFile(path.Join("locales", entry.Name()))
+		if err != nil {
+			return err
+		}
+
+		bundle := map[string]string{}
+		if err = json.Unmarshal(data, &bundle); err != nil {
+			return err
+		}
+		loaded[strings.TrimSuffix(entry.Name(), ".json")] = bundle
+	}
+
+	messagesMu.Lock()
+	messages = loaded
+	messagesMu.Unlock()
+
+	return nil
+}
+
+// Translate returns the message of key in the locale, falling back to the default
+// locale and then to fallback
+func Translate(locale, key, fallback string) string {
+	messagesMu.RLock()
+	defer messagesMu.RUnlock()
+
+	for _, l := range []string{locale, utils.DEFAULT_LOCALE} {
+		if message, ok := messages[l][key]; ok {
+			return message
+		}
+	}
+
+	return fallback
+}
@@ -0,0 +1,25 @@
+package logger
+
+import "context"
+
+// fieldsKey keeps the log fields of a request in its context
+type fieldsKey struct{}
+
+// NewContext returns a copy of ctx whose loggers add the key-value pairs to every line,
+// after the fields already in ctx
+func NewContext(ctx context.Context, args ...interface{}) context.Context {
+	prev := fieldsFromContext(ctx)
+
+	// never share the backing array of the parent fields
+	fields := make([]interface{}, 0, len(prev)+len(args))
+	fields = append(fields, prev...)
+	fields = append(fields, args...)
+
+	return context.WithValue(ctx, fieldsKey{}, fields)
+}
+
+// fieldsFromContext returns the log fields of ctx
+func fieldsFromContext(ctx context.Context) []interface{} {
+	fields, _ := ctx.Value(fieldsKey{}).([]interface{})
+	return fields
+}
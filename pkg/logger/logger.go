@@ -1,6 +1,8 @@
 package logger
 
 import (
+	"context"
+	"fmt"
 	"os"
 
 	"github.com/realtemirov/task-for-dell/config"
@@ -25,17 +27,27 @@ type Logger interface {
 	DPanicf(template string, args ...interface{})
 	Fatal(args ...interface{})
 	Fatalf(template string, args ...interface{})
+
+	// With returns a logger adding the key-value pairs to every line
+	With(args ...interface{}) Logger
+
+	// FromContext returns a logger adding the fields of ctx, e.g. the request ID, to every line
+	FromContext(ctx context.Context) Logger
+
+	// SetLevel changes the minimum level of every logger sharing this one's output
+	SetLevel(level string) error
 }
 
 // Logger
 type apiLogger struct {
 	cfg         *config.Config
 	sugarLogger *zap.SugaredLogger
+	level       zap.AtomicLevel
 }
 
-// App Logger constructor
+// App Logger constructor, lines are discarded until InitLogger
 func NewApiLogger(cfg *config.Config) *apiLogger {
-	return &apiLogger{cfg: cfg}
+	return &apiLogger{cfg: cfg, sugarLogger: zap.NewNop().Sugar(), level: zap.NewAtomicLevel()}
 }
 
 // For mapping config logger to app logger levels
@@ -85,7 +97,8 @@ func (l *apiLogger) InitLogger() {
 	}
 
 	encoderCfg.EncodeTime = zapcore.ISO8601TimeEncoder
-	core := zapcore.NewCore(encoder, logWriter, zap.NewAtomicLevelAt(logLevel))
+	l.level.SetLevel(logLevel)
+	core := zapcore.NewCore(encoder, logWriter, l.level)
 	logger := zap.New(core, zap.AddCaller(), zap.AddCallerSkip(1))
 
 	l.sugarLogger = logger.Sugar()
@@ -147,3 +160,27 @@ func (l *apiLogger) Fatal(args ...interface{}) {
 func (l *apiLogger) Fatalf(template string, args ...interface{}) {
 	l.sugarLogger.Fatalf(template, args...)
 }
+
+func (l *apiLogger) With(args ...interface{}) Logger {
+	return &apiLogger{cfg: l.cfg, sugarLogger: l.sugarLogger.With(args...), level: l.level}
+}
+
+// SetLevel implements Logger
+func (l *apiLogger) SetLevel(level string) error {
+	zapLevel, exist := loggerLevelMap[level]
+	if !exist {
+		return fmt.Errorf("logger: unknown level %q", level)
+	}
+	l.level.SetLevel(zapLevel)
+
+	return nil
+}
+
+func (l *apiLogger) FromContext(ctx context.Context) Logger {
+	fields := fieldsFromContext(ctx)
+	if len(fields) == 0 {
+		return l
+	}
+
+	return l.With(fields...)
+}
@@ -0,0 +1,23 @@
+package logger
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestApiLogger_SetLevel(t *testing.T) {
+	t.Parallel()
+
+	log := NewApiLogger(nil)
+	child := log.With("request_id", "1")
+
+	// loggers of With share the level
+	require.NoError(t, child.SetLevel("warn"))
+	require.False(t, log.level.Enabled(zapcore.InfoLevel))
+	require.True(t, log.level.Enabled(zapcore.WarnLevel))
+
+	require.EqualError(t, log.SetLevel("verbose"), `logger: unknown level "verbose"`)
+	require.True(t, log.level.Enabled(zapcore.WarnLevel))
+}
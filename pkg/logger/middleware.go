@@ -0,0 +1,73 @@
+package logger
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// USER_KEY is the key of the authenticated user in the echo context, logged when it is a string
+	USER_KEY = "user"
+
+	// log fields of every line of a request
+	FIELD_REQUEST_ID = "request_id"
+	FIELD_TRACE_ID   = "trace_id"
+)
+
+// Middleware keeps the request ID, and the trace ID of sampled requests, in the request context
+// so every line logged with FromContext carries them, then writes one access log line per request.
+// It runs after middleware.RequestID.
+func Middleware(log Logger) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			start := time.Now()
+			request := c.Request()
+
+			fields := []interface{}{FIELD_REQUEST_ID, c.Response().Header().Get(echo.HeaderXRequestID)}
+			if spanCtx := trace.SpanContextFromContext(request.Context()); spanCtx.IsSampled() {
+				fields = append(fields, FIELD_TRACE_ID, spanCtx.TraceID().String())
+			}
+			ctx := NewContext(request.Context(), fields...)
+			c.SetRequest(request.WithContext(ctx))
+
+			err := next(c)
+
+			// errors not written yet are written by the error handler of echo with their own status
+			status := c.Response().Status
+			var httpErr *echo.HTTPError
+			if err != nil && !c.Response().Committed {
+				status = http.StatusInternalServerError
+				if errors.As(err, &httpErr) {
+					status = httpErr.Code
+				}
+			}
+
+			user, _ := c.Get(USER_KEY).(string)
+			accessLog := log.FromContext(ctx).With(
+				"method", request.Method,
+				"path", request.URL.RequestURI(),
+				"status", status,
+				"latency", time.Since(start).String(),
+				"bytes_in", request.ContentLength,
+				"bytes_out", c.Response().Size,
+				"user", user,
+				"ip", c.RealIP(),
+			)
+
+			switch {
+			case status >= http.StatusInternalServerError:
+				accessLog.Error("request")
+			case status >= http.StatusBadRequest:
+				accessLog.Warn("request")
+			default:
+				accessLog.Info("request")
+			}
+
+			return err
+		}
+	}
+}
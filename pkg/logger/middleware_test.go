@@ -0,0 +1,69 @@
+package logger
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestMiddleware(t *testing.T) {
+
+	// observe logged lines
+	core, logs := observer.New(zapcore.DebugLevel)
+	log := &apiLogger{sugarLogger: zap.New(core).Sugar()}
+
+	e := echo.New()
+	e.Use(middleware.RequestID())
+	e.Use(Middleware(log))
+	e.GET("/v1/blogs/:id", func(c echo.Context) error {
+		c.Set(USER_KEY, "admin")
+		log.FromContext(c.Request().Context()).Info("blogUC.GetByID")
+		return c.String(http.StatusNotFound, "not found")
+	})
+
+	request := httptest.NewRequest(http.MethodGet, "/v1/blogs/1?locale=ru", nil)
+	request.Header.Set(echo.HeaderXRequestID, "request-1")
+	e.ServeHTTP(httptest.NewRecorder(), request)
+
+	entries := logs.All()
+	require.Len(t, entries, 2)
+
+	// lines logged while handling the request carry its ID
+	require.Equal(t, "blogUC.GetByID", entries[0].Message)
+	require.Equal(t, "request-1", entries[0].ContextMap()[FIELD_REQUEST_ID])
+
+	// then the access log line
+	accessLog := entries[1].ContextMap()
+	require.Equal(t, zapcore.WarnLevel, entries[1].Level)
+	require.Equal(t, "request-1", accessLog[FIELD_REQUEST_ID])
+	require.Equal(t, http.MethodGet, accessLog["method"])
+	require.Equal(t, "/v1/blogs/1?locale=ru", accessLog["path"])
+	require.EqualValues(t, http.StatusNotFound, accessLog["status"])
+	require.EqualValues(t, len("not found"), accessLog["bytes_out"])
+	require.Equal(t, "admin", accessLog["user"])
+	require.NotEmpty(t, accessLog["latency"])
+}
+
+func TestNewContext(t *testing.T) {
+
+	core, logs := observer.New(zapcore.DebugLevel)
+	log := &apiLogger{sugarLogger: zap.New(core).Sugar()}
+
+	// fields of the parent context are kept
+	parent := NewContext(httptest.NewRequest(http.MethodGet, "/", nil).Context(), FIELD_REQUEST_ID, "request-1")
+	child := NewContext(parent, "batch", 2)
+
+	log.FromContext(child).Info("child")
+	log.FromContext(parent).Info("parent")
+
+	entries := logs.All()
+	require.Equal(t, map[string]interface{}{FIELD_REQUEST_ID: "request-1", "batch": int64(2)}, entries[0].ContextMap())
+	require.Equal(t, map[string]interface{}{FIELD_REQUEST_ID: "request-1"}, entries[1].ContextMap())
+}
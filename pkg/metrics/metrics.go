@@ -0,0 +1,167 @@
+package metrics
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const (
+	// METRICS_PATH is the path metrics are scraped from
+	METRICS_PATH = "/metrics"
+
+	// UNMATCHED_ROUTE labels requests that matched no route, so unknown paths do not create new series
+	UNMATCHED_ROUTE = "unmatched"
+)
+
+var (
+	// registry of the metrics of the app, the default registry is left to libraries
+	registry = prometheus.NewRegistry()
+
+	// count of http requests by method, route and status
+	httpRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "http",
+		Name:      "requests_total",
+		Help:      "Count of HTTP requests by method, route and status.",
+	}, []string{"method", "route", "status"})
+
+	// latency of http requests by method, route and status
+	httpDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "http",
+		Name:      "request_duration_seconds",
+		Help:      "Latency of HTTP requests by method, route and status.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	// latency of repository methods
+	queryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "db",
+		Name:      "query_duration_seconds",
+		Help:      "Latency of repository methods by repository and method.",
+		Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5},
+	}, []string{"repository", "method"})
+
+	// count of cache lookups by cache and result
+	cacheRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "cache",
+		Name:      "requests_total",
+		Help:      "Count of cache lookups by cache and result: hit, miss or error.",
+	}, []string{"cache", "result"})
+
+	// count of outbox events published by type and result
+	outboxEvents = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "outbox",
+		Name:      "events_total",
+		Help:      "Count of outbox events published by type and result: delivered or failed.",
+	}, []string{"type", "result"})
+
+	// count of webhook delivery attempts by event type and result
+	webhookDeliveries = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "webhook",
+		Name:      "deliveries_total",
+		Help:      "Count of webhook delivery attempts by event type and result: delivered, failed or dead.",
+	}, []string{"type", "result"})
+)
+
+func init() {
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		httpRequests,
+		httpDuration,
+		queryDuration,
+		cacheRequests,
+		outboxEvents,
+		webhookDeliveries,
+	)
+}
+
+// RegisterDB exposes the pool stats of db: open, idle and in use connections, wait count and duration
+func RegisterDB(db *sql.DB, dbName string) error {
+	err := registry.Register(collectors.NewDBStatsCollector(db, dbName))
+
+	// the pool is already exposed, e.g. by an earlier server of the same process
+	var alreadyRegistered prometheus.AlreadyRegisteredError
+	if errors.As(err, &alreadyRegistered) {
+		return nil
+	}
+
+	return err
+}
+
+// ObserveQuery records the latency of a repository method started at start.
+//
+//	defer metrics.ObserveQuery("blogs", "Create", time.Now())
+func ObserveQuery(repository, method string, start time.Time) {
+	queryDuration.WithLabelValues(repository, method).Observe(time.Since(start).Seconds())
+}
+
+// ObserveCache counts a lookup of cache whose result is hit, miss or error
+func ObserveCache(cache, result string) {
+	cacheRequests.WithLabelValues(cache, result).Inc()
+}
+
+// ObserveEvent counts an outbox event of eventType whose publishing result is delivered or failed
+func ObserveEvent(eventType, result string) {
+	outboxEvents.WithLabelValues(eventType, result).Inc()
+}
+
+// ObserveWebhook counts an attempt of a webhook delivery of eventType whose result is delivered, failed or dead
+func ObserveWebhook(eventType, result string) {
+	webhookDeliveries.WithLabelValues(eventType, result).Inc()
+}
+
+// Middleware records count and latency of every request, labelled by its route template
+func Middleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			start := time.Now()
+			err := next(c)
+
+			route := c.Path()
+			if route == "" {
+				route = UNMATCHED_ROUTE
+			}
+
+			// errors not written yet are written by the error handler of echo with their own status
+			status := c.Response().Status
+			var httpErr *echo.HTTPError
+			if err != nil && !c.Response().Committed {
+				status = http.StatusInternalServerError
+				if errors.As(err, &httpErr) {
+					status = httpErr.Code
+				}
+			}
+
+			labels := []string{c.Request().Method, route, strconv.Itoa(status)}
+			httpRequests.WithLabelValues(labels...).Inc()
+			httpDuration.WithLabelValues(labels...).Observe(time.Since(start).Seconds())
+
+			return err
+		}
+	}
+}
+
+// Handler serves the metrics in the Prometheus text format
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
+
+// NewServer returns the server of the metrics listener, apart from the api so it is not exposed publicly
+func NewServer(addr string) *http.Server {
+	mux := http.NewServeMux()
+	mux.Handle(METRICS_PATH, Handler())
+
+	return &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+}
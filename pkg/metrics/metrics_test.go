@@ -0,0 +1,40 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMiddleware(t *testing.T) {
+
+	e := echo.New()
+	e.Use(Middleware())
+	e.GET("/v1/blogs/:id", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+	e.GET("/v1/blogs", func(c echo.Context) error {
+		return echo.NewHTTPError(http.StatusBadRequest)
+	})
+
+	// requests of the same route share its template
+	for _, target := range []string{"/v1/blogs/1", "/v1/blogs/2", "/v1/blogs"} {
+		e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, target, nil))
+	}
+	ObserveQuery("blogs", "GetByID", time.Now())
+
+	response := httptest.NewRecorder()
+	Handler().ServeHTTP(response, httptest.NewRequest(http.MethodGet, METRICS_PATH, nil))
+
+	require.Equal(t, http.StatusOK, response.Code)
+	body := response.Body.String()
+	require.Contains(t, body, `http_requests_total{method="GET",route="/v1/blogs/:id",status="200"} 2`)
+	require.Contains(t, body, `http_requests_total{method="GET",route="/v1/blogs",status="400"} 1`)
+	require.Contains(t, body, `http_request_duration_seconds_count{method="GET",route="/v1/blogs/:id",status="200"} 2`)
+	require.Contains(t, body, `db_query_duration_seconds_count{method="GetByID",repository="blogs"} 1`)
+	require.Contains(t, body, "go_goroutines")
+}
@@ -0,0 +1,70 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/realtemirov/task-for-dell/config"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestMiddleware(t *testing.T) {
+
+	// record spans instead of exporting them
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	_, err := NewTracerProvider(context.Background(), &config.Config{})
+	require.NoError(t, err)
+
+	e := echo.New()
+	e.Use(Middleware())
+	e.GET("/v1/blogs/:id", func(c echo.Context) error {
+		_, span := Start(c.Request().Context(), "blogUC.GetByID")
+		defer span.End()
+
+		return c.NoContent(http.StatusInternalServerError)
+	})
+
+	// continue the trace of the caller
+	request := httptest.NewRequest(http.MethodGet, "/v1/blogs/1", nil)
+	request.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	e.ServeHTTP(httptest.NewRecorder(), request)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+
+	// the usecase span ends first, as a child of the request span
+	usecaseSpan, requestSpan := spans[0], spans[1]
+	require.Equal(t, "blogUC.GetByID", usecaseSpan.Name())
+	require.Equal(t, requestSpan.SpanContext().SpanID(), usecaseSpan.Parent().SpanID())
+
+	require.Equal(t, "GET /v1/blogs/:id", requestSpan.Name())
+	require.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", requestSpan.SpanContext().TraceID().String())
+	require.Equal(t, "00f067aa0ba902b7", requestSpan.Parent().SpanID().String())
+	require.Equal(t, "Error", requestSpan.Status().Code.String())
+}
+
+func TestStart(t *testing.T) {
+
+	// nothing is recorded outside of a request, ctx is kept as it is
+	ctx := context.Background()
+	spanCtx, span := Start(ctx, "blogsRepo.GetAll")
+	defer span.End()
+
+	require.Equal(t, ctx, spanCtx)
+	require.False(t, span.IsRecording())
+}
+
+func TestNewTracerProvider(t *testing.T) {
+
+	_, err := NewTracerProvider(context.Background(), &config.Config{
+		Tracing: config.TracingConfig{Exporter: "zipkin"},
+	})
+	require.Error(t, err)
+}
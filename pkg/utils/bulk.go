@@ -0,0 +1,46 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+)
+
+const (
+	// BULK_MAX_SIZE is the maximum number of operations in one bulk request.
+	BULK_MAX_SIZE int = 1000
+
+	BULK_MODE_ATOMIC      string = "atomic"
+	BULK_MODE_BEST_EFFORT string = "best_effort"
+)
+
+var (
+	// ErrBulkAborted marks operations not applied because another operation of an atomic batch failed.
+	ErrBulkAborted = errors.New("bulk operation aborted, another operation in the batch failed")
+
+	// ErrBulkSize is returned for an empty bulk request or one with more than BULK_MAX_SIZE operations.
+	ErrBulkSize = fmt.Errorf("bulk request must have between 1 and %d operations", BULK_MAX_SIZE)
+
+	// ErrBulkMode is returned for an unknown bulk mode.
+	ErrBulkMode = fmt.Errorf("bulk mode must be %s or %s", BULK_MODE_ATOMIC, BULK_MODE_BEST_EFFORT)
+)
+
+// GetBulkMode returns the bulk mode, atomic by default
+func GetBulkMode(modeQuery string) (string, error) {
+	switch modeQuery {
+	case "", BULK_MODE_ATOMIC:
+		return BULK_MODE_ATOMIC, nil
+	case BULK_MODE_BEST_EFFORT:
+		return BULK_MODE_BEST_EFFORT, nil
+	default:
+		return "", ErrBulkMode
+	}
+}
+
+// ValidateBulkSize checks the number of operations in a bulk request
+func ValidateBulkSize(size int) error {
+	if size < 1 || size > BULK_MAX_SIZE {
+		return ErrBulkSize
+	}
+
+	return nil
+}
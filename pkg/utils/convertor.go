@@ -4,8 +4,12 @@ import (
 	"bytes"
 	"encoding/json"
 	"strconv"
+	"strings"
 )
 
+// likeEscaper escapes the LIKE wildcards, backslash is the default escape character in Postgres
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
 func StringToInt64(num string) (int64, error) {
 	return strconv.ParseInt(num, 10, 64)
 }
@@ -18,3 +22,8 @@ func AnyToBytesBuffer(i interface{}) (*bytes.Buffer, error) {
 	}
 	return buf, nil
 }
+
+// EscapeLike escapes s to be matched literally by a LIKE pattern.
+func EscapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
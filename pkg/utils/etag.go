@@ -0,0 +1,114 @@
+package utils
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	// ETAG_HASH_SIZE is the number of bytes of the sha256 of a body kept in its ETag
+	ETAG_HASH_SIZE = 16
+
+	HEADER_ETAG          string = "ETag"
+	HEADER_IF_NONE_MATCH string = "If-None-Match"
+)
+
+// ETag sends a weak ETag computed from the body of successful GET responses, and the
+// Cache-Control cacheControl when it is not empty. A request whose If-None-Match matches
+// the ETag is answered 304 Not Modified without the body. The tag is weak because the gzip
+// middleware sends the same tag for the compressed and the identity bytes of a body.
+func ETag(cacheControl string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if c.Request().Method != http.MethodGet {
+				return next(c)
+			}
+
+			// hold the response until its ETag is known
+			res := c.Response()
+			writer := res.Writer
+			buffered := &bufferedWriter{ResponseWriter: writer, status: http.StatusOK}
+			res.Writer = buffered
+			err := next(c)
+			res.Writer = writer
+
+			// nothing written, an error is written by the error handler
+			if !res.Committed {
+				return err
+			}
+
+			// errors and other statuses are sent as they are
+			if err != nil || buffered.status != http.StatusOK {
+				writer.WriteHeader(buffered.status)
+				if _, writeErr := writer.Write(buffered.body.Bytes()); err == nil {
+					err = writeErr
+				}
+				return err
+			}
+
+			etag := ComputeETag(buffered.body.Bytes())
+			header := res.Header()
+			header.Set(HEADER_ETAG, etag)
+			if cacheControl != "" {
+				header.Set(echo.HeaderCacheControl, cacheControl)
+			}
+
+			if MatchETag(c.Request().Header.Get(HEADER_IF_NONE_MATCH), etag) {
+				header.Del(echo.HeaderContentType)
+				header.Del(echo.HeaderContentLength)
+				res.Status = http.StatusNotModified
+				writer.WriteHeader(http.StatusNotModified)
+				return nil
+			}
+
+			writer.WriteHeader(http.StatusOK)
+			_, err = writer.Write(buffered.body.Bytes())
+
+			return err
+		}
+	}
+}
+
+// ComputeETag returns the weak ETag of body
+func ComputeETag(body []byte) string {
+	sum := sha256.Sum256(body)
+	return `W/"` + hex.EncodeToString(sum[:ETAG_HASH_SIZE]) + `"`
+}
+
+// MatchETag reports whether the If-None-Match header matches etag. Like RFC 9110 requires for
+// If-None-Match, tags are compared weakly, with or without W/, and * matches any etag.
+func MatchETag(ifNoneMatch, etag string) bool {
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, tag := range strings.Split(ifNoneMatch, ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+		if tag == "*" || tag == etag {
+			return true
+		}
+	}
+
+	return false
+}
+
+// bufferedWriter keeps the status and body of a response instead of sending them
+type bufferedWriter struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (w *bufferedWriter) WriteHeader(status int) {
+	w.status = status
+}
+
+func (w *bufferedWriter) Write(b []byte) (int, error) {
+	return w.body.Write(b)
+}
+
+func (w *bufferedWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,72 @@
+package utils
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func TestETag(t *testing.T) {
+	t.Parallel()
+
+	e := echo.New()
+	e.GET("/blogs/:id", func(c echo.Context) error {
+		if c.Param("id") == "0" {
+			return c.JSON(http.StatusNotFound, map[string]string{"title": "not found"})
+		}
+		return c.JSON(http.StatusOK, map[string]string{"title": c.Param("id")})
+	}, ETag("public, max-age=60"))
+
+	get := func(path, ifNoneMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if ifNoneMatch != "" {
+			req.Header.Set(HEADER_IF_NONE_MATCH, ifNoneMatch)
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("ETag", func(t *testing.T) {
+		rec := get("/blogs/1", "")
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.JSONEq(t, `{"title":"1"}`, rec.Body.String())
+		require.Equal(t, ComputeETag(rec.Body.Bytes()), rec.Header().Get(HEADER_ETAG))
+		require.True(t, strings.HasPrefix(rec.Header().Get(HEADER_ETAG), `W/"`))
+		require.Equal(t, "public, max-age=60", rec.Header().Get(echo.HeaderCacheControl))
+
+		// same content, same etag, another content another one
+		require.Equal(t, rec.Header().Get(HEADER_ETAG), get("/blogs/1", "").Header().Get(HEADER_ETAG))
+		require.NotEqual(t, rec.Header().Get(HEADER_ETAG), get("/blogs/2", "").Header().Get(HEADER_ETAG))
+	})
+
+	t.Run("Not Modified", func(t *testing.T) {
+		etag := get("/blogs/1", "").Header().Get(HEADER_ETAG)
+
+		// compared weakly, a client may send the tag without W/
+		strong := strings.TrimPrefix(etag, "W/")
+		for _, ifNoneMatch := range []string{etag, `"other", ` + etag, strong, `W/"other", ` + strong, "*"} {
+			rec := get("/blogs/1", ifNoneMatch)
+
+			require.Equal(t, http.StatusNotModified, rec.Code)
+			require.Empty(t, rec.Body.String())
+			require.Equal(t, etag, rec.Header().Get(HEADER_ETAG))
+		}
+
+		require.Equal(t, http.StatusOK, get("/blogs/1", `"other"`).Code)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		rec := get("/blogs/0", "*")
+
+		require.Equal(t, http.StatusNotFound, rec.Code)
+		require.JSONEq(t, `{"title":"not found"}`, rec.Body.String())
+		require.Empty(t, rec.Header().Get(HEADER_ETAG))
+		require.Empty(t, rec.Header().Get(echo.HeaderCacheControl))
+	})
+}
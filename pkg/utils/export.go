@@ -0,0 +1,112 @@
+package utils
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+)
+
+const (
+	FORMAT_NDJSON string = "ndjson"
+	FORMAT_CSV    string = "csv"
+
+	// EXPORT_FLUSH_SIZE is the number of records written between two flushes of a stream.
+	EXPORT_FLUSH_SIZE int = 100
+)
+
+// ErrFormat is returned for an unknown export or import format.
+var ErrFormat = errors.New("format must be ndjson or csv")
+
+// CSVRecorder is implemented by models exported as CSV
+type CSVRecorder interface {
+	CSVHeader() []string
+	CSVRecord() []string
+}
+
+// GetFormat returns the format of an export or import, ndjson by default
+func GetFormat(formatQuery string) (string, error) {
+	switch formatQuery {
+	case "", FORMAT_NDJSON:
+		return FORMAT_NDJSON, nil
+	case FORMAT_CSV:
+		return FORMAT_CSV, nil
+	default:
+		return "", ErrFormat
+	}
+}
+
+// GetFormatContentType returns the content type of the format
+func GetFormatContentType(format string) string {
+	if format == FORMAT_CSV {
+		return "text/csv; charset=utf-8"
+	}
+
+	return "application/x-ndjson"
+}
+
+// ExportWriter writes records as NDJSON or CSV, flushing the underlying writer
+// every EXPORT_FLUSH_SIZE records so they are streamed instead of buffered.
+type ExportWriter struct {
+	w       io.Writer
+	format  string
+	json    *json.Encoder
+	csv     *csv.Writer
+	written int
+}
+
+// NewExportWriter constructs an ExportWriter of the format
+func NewExportWriter(w io.Writer, format string) *ExportWriter {
+	e := &ExportWriter{w: w, format: format}
+	if format == FORMAT_CSV {
+		e.csv = csv.NewWriter(w)
+	} else {
+		e.json = json.NewEncoder(w)
+	}
+
+	return e
+}
+
+// Write writes one record, preceded by the header for the first CSV record
+func (e *ExportWriter) Write(record CSVRecorder) error {
+	if e.csv != nil {
+		if e.written == 0 {
+			if err := e.csv.Write(record.CSVHeader()); err != nil {
+				return err
+			}
+		}
+		if err := e.csv.Write(record.CSVRecord()); err != nil {
+			return err
+		}
+	} else if err := e.json.Encode(record); err != nil {
+		return err
+	}
+
+	e.written++
+	if e.written%EXPORT_FLUSH_SIZE == 0 {
+		return e.Flush()
+	}
+
+	return nil
+}
+
+// Written returns the number of records written
+func (e *ExportWriter) Written() int {
+	return e.written
+}
+
+// Flush sends buffered records to the client
+func (e *ExportWriter) Flush() error {
+	if e.csv != nil {
+		e.csv.Flush()
+		if err := e.csv.Error(); err != nil {
+			return err
+		}
+	}
+	if flusher, ok := e.w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+
+	return nil
+}
@@ -0,0 +1,195 @@
+package utils
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	// IMPORT_BATCH_SIZE is the number of records upserted by one statement.
+	IMPORT_BATCH_SIZE int = 500
+
+	// IMPORT_BODY_LIMIT is the maximum size of an uploaded import file.
+	IMPORT_BODY_LIMIT string = "64M"
+
+	// REPORT_JSON is the default format of an import report, csv is the other one.
+	REPORT_JSON string = "json"
+
+	// importRawSize is the maximum length of a rejected line kept in the report.
+	importRawSize int = 200
+)
+
+var (
+	// ErrReport is returned for an unknown import report format.
+	ErrReport = errors.New("report must be json or csv")
+
+	// ErrDryRun is returned for a dry_run query param that is not a boolean.
+	ErrDryRun = errors.New("dry_run must be a boolean")
+)
+
+// GetReportFormat returns the format of an import report, json by default
+func GetReportFormat(reportQuery string) (string, error) {
+	switch reportQuery {
+	case "", REPORT_JSON:
+		return REPORT_JSON, nil
+	case FORMAT_CSV:
+		return FORMAT_CSV, nil
+	default:
+		return "", ErrReport
+	}
+}
+
+// GetDryRun returns the dry_run query param, false by default
+func GetDryRun(dryRunQuery string) (bool, error) {
+	if dryRunQuery == "" {
+		return false, nil
+	}
+
+	dryRun, err := strconv.ParseBool(dryRunQuery)
+	if err != nil {
+		return false, ErrDryRun
+	}
+
+	return dryRun, nil
+}
+
+// GetImportFile returns the uploaded import file, from the multipart "file" field
+// or the raw request body.
+func GetImportFile(c echo.Context) (io.ReadCloser, error) {
+	if !strings.HasPrefix(c.Request().Header.Get(echo.HeaderContentType), echo.MIMEMultipartForm) {
+		return c.Request().Body, nil
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		return nil, err
+	}
+
+	return fileHeader.Open()
+}
+
+// CSVUnmarshaler is implemented by models imported from CSV
+type CSVUnmarshaler interface {
+	UnmarshalCSV(header, record []string) error
+}
+
+// ImportLineError is a line that can not be decoded, the import goes on with the next line.
+type ImportLineError struct {
+	Line int
+	Raw  string
+	Err  error
+}
+
+func (e *ImportLineError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+func (e *ImportLineError) Unwrap() error {
+	return e.Err
+}
+
+// ImportReader reads records from NDJSON or CSV one line at a time.
+type ImportReader struct {
+	format string
+	ndjson *bufio.Reader
+	csv    *csv.Reader
+	header []string
+	line   int
+}
+
+// NewImportReader constructs an ImportReader of the format
+func NewImportReader(r io.Reader, format string) *ImportReader {
+	i := &ImportReader{format: format}
+	if format == FORMAT_CSV {
+		i.csv = csv.NewReader(r)
+		i.csv.FieldsPerRecord = -1
+	} else {
+		i.ndjson = bufio.NewReader(r)
+	}
+
+	return i
+}
+
+// Next decodes the next record into v and returns its line number.
+// It returns io.EOF at the end, an *ImportLineError for a line that can not be decoded
+// and any other error when the input can not be read anymore.
+func (i *ImportReader) Next(v CSVUnmarshaler) (int, error) {
+	if i.csv != nil {
+		return i.nextCSV(v)
+	}
+
+	return i.nextNDJSON(v)
+}
+
+func (i *ImportReader) nextNDJSON(v CSVUnmarshaler) (int, error) {
+	for {
+		data, err := i.ndjson.ReadBytes('\n')
+		if len(data) == 0 && err != nil {
+			return i.line, err
+		}
+		if err != nil && !errors.Is(err, io.EOF) {
+			return i.line, err
+		}
+		i.line++
+
+		// blank lines are not records
+		data = bytes.TrimSpace(data)
+		if len(data) == 0 {
+			continue
+		}
+
+		if err := json.Unmarshal(data, v); err != nil {
+			return i.line, &ImportLineError{Line: i.line, Raw: truncate(string(data)), Err: err}
+		}
+
+		return i.line, nil
+	}
+}
+
+func (i *ImportReader) nextCSV(v CSVUnmarshaler) (int, error) {
+
+	// first line is the header
+	if i.header == nil {
+		header, err := i.csv.Read()
+		if err != nil {
+			return 0, err
+		}
+		for n := range header {
+			header[n] = strings.ToLower(strings.TrimSpace(header[n]))
+		}
+		i.header = header
+	}
+
+	record, err := i.csv.Read()
+	if err != nil {
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			return parseErr.StartLine, &ImportLineError{Line: parseErr.StartLine, Err: parseErr.Err}
+		}
+		return i.line, err
+	}
+	i.line, _ = i.csv.FieldPos(0)
+
+	if err := v.UnmarshalCSV(i.header, record); err != nil {
+		return i.line, &ImportLineError{Line: i.line, Raw: truncate(strings.Join(record, ",")), Err: err}
+	}
+
+	return i.line, nil
+}
+
+func truncate(s string) string {
+	if len(s) <= importRawSize {
+		return s
+	}
+
+	return s[:importRawSize] + "..."
+}
@@ -0,0 +1,116 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	LOCALE_EN string = "en"
+	LOCALE_RU string = "ru"
+	LOCALE_UZ string = "uz"
+
+	// DEFAULT_LOCALE is used when Accept-Language has no supported locale.
+	DEFAULT_LOCALE string = LOCALE_EN
+
+	HEADER_ACCEPT_LANGUAGE  string = "Accept-Language"
+	HEADER_CONTENT_LANGUAGE string = "Content-Language"
+)
+
+// SUPPORTED_LOCALES are the locales messages are translated to
+var SUPPORTED_LOCALES = []string{LOCALE_EN, LOCALE_RU, LOCALE_UZ}
+
+// ErrLocale is returned for a locale that is not supported.
+var ErrLocale = errors.New("locale must be en, ru or uz")
+
+// localeKey is the context key of the request locale
+type localeKey struct{}
+
+// WithLocale returns a copy of ctx carrying the locale
+func WithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, localeKey{}, locale)
+}
+
+// GetLocale returns the locale of ctx, DEFAULT_LOCALE when there is none
+func GetLocale(ctx context.Context) string {
+	if locale, ok := ctx.Value(localeKey{}).(string); ok && locale != "" {
+		return locale
+	}
+
+	return DEFAULT_LOCALE
+}
+
+// Locale selects the locale of every request from its locale query param or else
+// its Accept-Language header, and keeps it in the request context.
+func Locale() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			locale := c.QueryParam("locale")
+			if !isSupportedLocale(locale) {
+				locale = ParseAcceptLanguage(c.Request().Header.Get(HEADER_ACCEPT_LANGUAGE))
+			}
+
+			c.Response().Header().Set(HEADER_CONTENT_LANGUAGE, locale)
+			c.Response().Header().Add(echo.HeaderVary, HEADER_ACCEPT_LANGUAGE)
+			c.SetRequest(c.Request().WithContext(WithLocale(c.Request().Context(), locale)))
+
+			return next(c)
+		}
+	}
+}
+
+// ParseAcceptLanguage returns the supported locale with the highest quality in an
+// Accept-Language header, e.g. "uz-UZ,ru;q=0.9,en;q=0.8" is uz. Regions are ignored.
+func ParseAcceptLanguage(header string) string {
+
+	type weighted struct {
+		locale  string
+		quality float64
+	}
+
+	var candidates []weighted
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+
+		quality := 1.0
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(q, 64)
+			if err != nil {
+				continue
+			}
+			quality = parsed
+		}
+
+		// only the language of the tag matters, uz-Latn-UZ is uz
+		base, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+		if quality > 0 && isSupportedLocale(base) {
+			candidates = append(candidates, weighted{locale: base, quality: quality})
+		}
+	}
+
+	if len(candidates) == 0 {
+		return DEFAULT_LOCALE
+	}
+
+	// stable keeps the order of the header for equal qualities
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].quality > candidates[j].quality
+	})
+
+	return candidates[0].locale
+}
+
+func isSupportedLocale(locale string) bool {
+	for _, supported := range SUPPORTED_LOCALES {
+		if locale == supported {
+			return true
+		}
+	}
+
+	return false
+}
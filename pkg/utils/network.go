@@ -0,0 +1,49 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"syscall"
+)
+
+// ErrPrivateNetwork is returned for an address of a private network, see PublicIP.
+var ErrPrivateNetwork = errors.New("address is not public")
+
+// sharedAddressSpace is 100.64.0.0/10, of carrier-grade NAT and of some cloud metadata services
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// PublicIP reports whether ip is reachable on the internet: it is not a loopback, private, link-local
+// (like the metadata service 169.254.169.254), shared, multicast or unspecified address.
+func PublicIP(ip net.IP) bool {
+	return !ip.IsLoopback() &&
+		!ip.IsPrivate() &&
+		!ip.IsLinkLocalUnicast() &&
+		!ip.IsLinkLocalMulticast() &&
+		!ip.IsInterfaceLocalMulticast() &&
+		!ip.IsMulticast() &&
+		!ip.IsUnspecified() &&
+		!sharedAddressSpace.Contains(ip)
+}
+
+// LocalHost reports whether host names this machine, "localhost" and its subdomains resolve to loopback
+func LocalHost(host string) bool {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	return host == "localhost" || strings.HasSuffix(host, ".localhost")
+}
+
+// PublicDialControl is a net.Dialer Control refusing to connect to addresses which are not public,
+// it runs after the host is resolved so a name resolving to a private address is refused as well.
+func PublicDialControl(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+
+	if ip := net.ParseIP(host); ip == nil || !PublicIP(ip) {
+		return fmt.Errorf("dial %s %s: %w", network, address, ErrPrivateNetwork)
+	}
+
+	return nil
+}
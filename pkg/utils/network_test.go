@@ -0,0 +1,50 @@
+package utils
+
+import (
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPublicIP(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		ip   string
+		want bool
+	}{
+		{"93.184.216.34", true},
+		{"2606:2800:220:1:248:1893:25c8:1946", true},
+		{"127.0.0.1", false},
+		{"::1", false},
+		{"10.0.0.1", false},
+		{"172.16.0.1", false},
+		{"192.168.1.1", false},
+		{"169.254.169.254", false},
+		{"100.100.100.200", false},
+		{"fd00::1", false},
+		{"fe80::1", false},
+		{"0.0.0.0", false},
+		{"224.0.0.1", false},
+		{"::ffff:127.0.0.1", false},
+	}
+
+	for _, tt := range tests {
+		require.Equal(t, tt.want, PublicIP(net.ParseIP(tt.ip)), tt.ip)
+	}
+}
+
+func TestPublicDialControl(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, PublicDialControl("tcp4", "93.184.216.34:443", nil))
+	require.NoError(t, PublicDialControl("tcp6", "[2606:2800:220:1:248:1893:25c8:1946]:443", nil))
+
+	err := PublicDialControl("tcp4", "169.254.169.254:80", nil)
+	require.True(t, errors.Is(err, ErrPrivateNetwork))
+
+	err = PublicDialControl("tcp6", "[::1]:80", nil)
+	require.True(t, errors.Is(err, ErrPrivateNetwork))
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"math"
 	"strconv"
+	"sync/atomic"
 
 	"github.com/labstack/echo/v4"
 )
@@ -11,27 +12,67 @@ import (
 const (
 	DEFAULT_SIZE int = 10
 	MAX_SIZE     int = 50
+
+	// SUGGESTIONS_SIZE is the number of "did you mean" titles returned for an empty search.
+	SUGGESTIONS_SIZE int = 5
+
+	// DEFAULT_SIMILARITY_THRESHOLD is the pg_trgm similarity used when none is configured.
+	DEFAULT_SIMILARITY_THRESHOLD float64 = 0.3
 )
 
+// page sizes and fuzzy search of the config, swapped when it is reloaded
+var (
+	defaultSize   atomic.Int64
+	maxSize       atomic.Int64
+	fuzzyDisabled atomic.Bool
+)
+
+// SetPageSizes sets the limit of queries without one and the maximum limit
+func SetPageSizes(defaultLimit, maxLimit int) {
+	defaultSize.Store(int64(defaultLimit))
+	maxSize.Store(int64(maxLimit))
+}
+
+// SetFuzzyEnabled turns the fuzzy query param on or off, queries are exact while it is off
+func SetFuzzyEnabled(enabled bool) {
+	fuzzyDisabled.Store(!enabled)
+}
+
+// pageSizes returns the default and maximum limit, DEFAULT_SIZE and MAX_SIZE until SetPageSizes
+func pageSizes() (int, int) {
+	defaultLimit, maxLimit := int(defaultSize.Load()), int(maxSize.Load())
+	if defaultLimit <= 0 || maxLimit <= 0 {
+		return DEFAULT_SIZE, MAX_SIZE
+	}
+
+	return defaultLimit, maxLimit
+}
+
 type Query struct {
-	Limit  int    `json:"limit,omitempty"`
-	Page   int    `json:"page,omitempty"`
-	Search string `json:"search,omitempty"`
-	Sort   string `json:"sort,omitempty"`
+	Limit     int     `json:"limit,omitempty"`
+	Page      int     `json:"page,omitempty"`
+	Search    string  `json:"search,omitempty"`
+	Sort      string  `json:"sort,omitempty"`
+	Fuzzy     bool    `json:"fuzzy,omitempty"`
+	Threshold float64 `json:"-"`
+
+	// AvailableLocale keeps only contents translated to the locale
+	AvailableLocale string `json:"available_locale,omitempty"`
 }
 
 // SetLimit
 func (q *Query) SetLimit(sizeQuery string) error {
+	defaultLimit, maxLimit := pageSizes()
 	if sizeQuery == "" {
-		q.Limit = DEFAULT_SIZE
+		q.Limit = defaultLimit
 		return nil
 	}
 	n, err := strconv.Atoi(sizeQuery)
 	if err != nil {
 		return err
 	}
-	if n > MAX_SIZE || n < 0 {
-		n = MAX_SIZE
+	if n > maxLimit || n < 0 {
+		n = maxLimit
 	}
 	q.Limit = n
 
@@ -58,6 +99,41 @@ func (q *Query) SetSort(sortQuery string) {
 	q.Sort = sortQuery
 }
 
+// SetFuzzy
+func (q *Query) SetFuzzy(fuzzyQuery string) error {
+	if fuzzyQuery == "" {
+		q.Fuzzy = false
+		return nil
+	}
+	fuzzy, err := strconv.ParseBool(fuzzyQuery)
+	if err != nil {
+		return err
+	}
+	q.Fuzzy = fuzzy && !fuzzyDisabled.Load()
+
+	return nil
+}
+
+// SetAvailableLocale
+func (q *Query) SetAvailableLocale(localeQuery string) error {
+	if localeQuery != "" && !isSupportedLocale(localeQuery) {
+		return ErrLocale
+	}
+	q.AvailableLocale = localeQuery
+
+	return nil
+}
+
+// GetAvailableLocale returns the locale contents must be translated to,
+// empty for the default locale which every content is in
+func (q *Query) GetAvailableLocale() string {
+	if q.AvailableLocale == DEFAULT_LOCALE {
+		return ""
+	}
+
+	return q.AvailableLocale
+}
+
 // GetOffset
 func (q *Query) GetOffset() int {
 	if q.Page == 0 {
@@ -84,6 +160,15 @@ func (q *Query) GetSort() string {
 	return q.Sort
 }
 
+// GetThreshold returns the minimum trigram similarity for fuzzy search
+func (q *Query) GetThreshold() float64 {
+	if q.Threshold <= 0 || q.Threshold > 1 {
+		return DEFAULT_SIMILARITY_THRESHOLD
+	}
+
+	return q.Threshold
+}
+
 // GetPage
 func (q *Query) GetPage() int {
 	if q.Page == 0 {
@@ -106,7 +191,13 @@ func GetPaginationFromCtx(c echo.Context) (*Query, error) {
 	if err := q.SetLimit(c.QueryParam("limit")); err != nil {
 		return nil, err
 	}
-	
+	if err := q.SetFuzzy(c.QueryParam("fuzzy")); err != nil {
+		return nil, err
+	}
+	if err := q.SetAvailableLocale(c.QueryParam("available_locale")); err != nil {
+		return nil, err
+	}
+
 	q.SetSort(c.QueryParam("sort"))
 	q.Search = c.QueryParam("search")
 
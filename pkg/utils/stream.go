@@ -0,0 +1,43 @@
+package utils
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// responseControllerKey is the echo context key of the raw response controller
+const responseControllerKey = "response_controller"
+
+// ResponseController keeps a controller of the raw response writer, before middlewares
+// like gzip wrap it and hide its deadlines. Register it before those middlewares.
+func ResponseController() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			c.Set(responseControllerKey, http.NewResponseController(c.Response().Writer))
+			return next(c)
+		}
+	}
+}
+
+// SetReadDeadline changes the read deadline of the request body, so long uploads
+// can outlive the server ReadTimeout. A zero deadline means no deadline.
+func SetReadDeadline(c echo.Context, deadline time.Time) error {
+	return responseController(c).SetReadDeadline(deadline)
+}
+
+// SetWriteDeadline changes the write deadline of the response, so long-running streams
+// can outlive the server WriteTimeout. A zero deadline means no deadline.
+func SetWriteDeadline(c echo.Context, deadline time.Time) error {
+	return responseController(c).SetWriteDeadline(deadline)
+}
+
+func responseController(c echo.Context) *http.ResponseController {
+	rc, ok := c.Get(responseControllerKey).(*http.ResponseController)
+	if !ok {
+		rc = http.NewResponseController(c.Response().Writer)
+	}
+
+	return rc
+}
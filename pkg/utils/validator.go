@@ -2,18 +2,177 @@ package utils
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
 
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/ru"
+	"github.com/go-playground/locales/uz"
+	ut "github.com/go-playground/universal-translator"
 	"github.com/go-playground/validator/v10"
+	enTranslations "github.com/go-playground/validator/v10/translations/en"
+	ruTranslations "github.com/go-playground/validator/v10/translations/ru"
 )
 
 // Use a single instance of Validate, it caches struct info
 var validate *validator.Validate
 
+// translators of validation messages, one per supported locale
+var translators *ut.UniversalTranslator
+
+// uzTranslations are the validation messages in uzbek, validator has no uzbek translations.
+// Rules with a "-string" variant describe the length of strings instead of numbers.
+var uzTranslations = map[string]string{
+	"required":        "{0} majburiy maydon",
+	"required_unless": "{0} majburiy maydon",
+	"min":             "{0} kamida {1} bo'lishi kerak",
+	"min-string":      "{0} kamida {1} ta belgidan iborat bo'lishi kerak",
+	"gte":             "{0} {1} dan katta yoki teng bo'lishi kerak",
+	"gte-string":      "{0} kamida {1} ta belgidan iborat bo'lishi kerak",
+	"max":             "{0} ko'pi bilan {1} bo'lishi kerak",
+	"max-string":      "{0} ko'pi bilan {1} ta belgidan iborat bo'lishi kerak",
+	"lte":             "{0} {1} dan kichik yoki teng bo'lishi kerak",
+	"lte-string":      "{0} ko'pi bilan {1} ta belgidan iborat bo'lishi kerak",
+	"oneof":           "{0} quyidagilardan biri bo'lishi kerak [{1}]",
+}
+
 func init() {
 	validate = validator.New()
+
+	// report fields by their json name, as clients send them
+	validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		if name == "" {
+			return field.Name
+		}
+		return name
+	})
+
+	if err := registerTranslations(); err != nil {
+		panic(fmt.Sprintf("utils: register validation translations: %v", err))
+	}
+}
+
+// registerTranslations registers the validation messages of every supported locale
+func registerTranslations() error {
+	translators = ut.New(en.New(), en.New(), ru.New(), uz.New())
+
+	enTrans, _ := translators.GetTranslator(LOCALE_EN)
+	if err := enTranslations.RegisterDefaultTranslations(validate, enTrans); err != nil {
+		return err
+	}
+
+	ruTrans, _ := translators.GetTranslator(LOCALE_RU)
+	if err := ruTranslations.RegisterDefaultTranslations(validate, ruTrans); err != nil {
+		return err
+	}
+
+	uzTrans, _ := translators.GetTranslator(LOCALE_UZ)
+	for key, text := range uzTranslations {
+		if err := uzTrans.Add(key, text, false); err != nil {
+			return err
+		}
+	}
+	for tag := range uzTranslations {
+		if strings.HasSuffix(tag, "-string") {
+			continue
+		}
+		if err := validate.RegisterTranslation(tag, uzTrans, noopRegister, translateUz); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// noopRegister is used for uzbek, whose messages are added before their rules are registered
+func noopRegister(ut.Translator) error {
+	return nil
+}
+
+// translateUz translates a failed rule to uzbek
+func translateUz(trans ut.Translator, fieldErr validator.FieldError) string {
+	key := fieldErr.Tag()
+	if _, ok := uzTranslations[key+"-string"]; ok && fieldErr.Kind() == reflect.String {
+		key += "-string"
+	}
+
+	message, err := trans.T(key, fieldErr.Field(), fieldErr.Param())
+	if err != nil {
+		return fieldErr.Error()
+	}
+
+	return message
+}
+
+// FieldError is one failed rule of a validated field
+type FieldError struct {
+	Field   string `json:"field" example:"title"`
+	Rule    string `json:"rule" example:"gte"`
+	Param   string `json:"param,omitempty" example:"3"`
+	Message string `json:"message" example:"title must be at least 3 characters in length"`
 }
 
 // Validate struct fields
 func ValidateStruct(ctx context.Context, s interface{}) error {
 	return validate.StructCtx(ctx, s)
 }
+
+// ValidationErrors returns one FieldError per failed field of a validation error,
+// with messages in the locale. It returns nil when err is not a validation error.
+func ValidationErrors(err error, locale string) []*FieldError {
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		return nil
+	}
+
+	fieldErrors := make([]*FieldError, 0, len(validationErrors))
+	for _, fieldErr := range validationErrors {
+		fieldErrors = append(fieldErrors, &FieldError{
+			Field:   fieldErr.Field(),
+			Rule:    fieldErr.Tag(),
+			Param:   fieldErr.Param(),
+			Message: fieldMessage(fieldErr, locale),
+		})
+	}
+
+	return fieldErrors
+}
+
+// ValidationMessages returns one message per failed field of a validation error, in the locale
+func ValidationMessages(err error, locale string) []string {
+	fieldErrors := ValidationErrors(err, locale)
+	if fieldErrors == nil {
+		return nil
+	}
+
+	messages := make([]string, 0, len(fieldErrors))
+	for _, fieldErr := range fieldErrors {
+		messages = append(messages, fieldErr.Message)
+	}
+
+	return messages
+}
+
+// fieldMessage translates the failed rule of a field to the locale, falling back to english
+// and then to the rule itself for rules without translation
+func fieldMessage(fieldErr validator.FieldError, locale string) string {
+	for _, l := range []string{locale, DEFAULT_LOCALE} {
+		trans, found := translators.GetTranslator(l)
+		if !found {
+			continue
+		}
+
+		// untranslated rules come back as the raw error
+		if message := fieldErr.Translate(trans); message != fieldErr.Error() {
+			return message
+		}
+	}
+
+	return fmt.Sprintf("%s is not valid (%s)", fieldErr.Field(), fieldErr.Tag())
+}